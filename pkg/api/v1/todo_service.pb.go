@@ -10,6 +10,7 @@ import (
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger/options"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
+	status "google.golang.org/genproto/googleapis/rpc/status"
 	grpc "google.golang.org/grpc"
 	math "math"
 )
@@ -25,11 +26,165 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type DataFormat int32
+
+const (
+	DataFormat_JSONL DataFormat = 0
+	DataFormat_CSV   DataFormat = 1
+)
+
+var DataFormat_name = map[int32]string{
+	0: "JSONL",
+	1: "CSV",
+}
+
+var DataFormat_value = map[string]int32{
+	"JSONL": 0,
+	"CSV":   1,
+}
+
+func (x DataFormat) String() string {
+	return proto.EnumName(DataFormat_name, int32(x))
+}
+
+func (DataFormat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{0}
+}
+
+type ToDo_ReminderStatus int32
+
+const (
+	ToDo_PENDING   ToDo_ReminderStatus = 0
+	ToDo_DELIVERED ToDo_ReminderStatus = 1
+	ToDo_FAILED    ToDo_ReminderStatus = 2
+)
+
+var ToDo_ReminderStatus_name = map[int32]string{
+	0: "PENDING",
+	1: "DELIVERED",
+	2: "FAILED",
+}
+
+var ToDo_ReminderStatus_value = map[string]int32{
+	"PENDING":   0,
+	"DELIVERED": 1,
+	"FAILED":    2,
+}
+
+func (x ToDo_ReminderStatus) String() string {
+	return proto.EnumName(ToDo_ReminderStatus_name, int32(x))
+}
+
+func (ToDo_ReminderStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{0, 0}
+}
+
+type ImportResult_Outcome int32
+
+const (
+	ImportResult_CREATED   ImportResult_Outcome = 0
+	ImportResult_DUPLICATE ImportResult_Outcome = 1
+	ImportResult_INVALID   ImportResult_Outcome = 2
+)
+
+var ImportResult_Outcome_name = map[int32]string{
+	0: "CREATED",
+	1: "DUPLICATE",
+	2: "INVALID",
+}
+
+var ImportResult_Outcome_value = map[string]int32{
+	"CREATED":   0,
+	"DUPLICATE": 1,
+	"INVALID":   2,
+}
+
+func (x ImportResult_Outcome) String() string {
+	return proto.EnumName(ImportResult_Outcome_name, int32(x))
+}
+
+func (ImportResult_Outcome) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{37, 0}
+}
+
+type ToDoEvent_Type int32
+
+const (
+	ToDoEvent_UNKNOWN   ToDoEvent_Type = 0
+	ToDoEvent_CREATED   ToDoEvent_Type = 1
+	ToDoEvent_UPDATED   ToDoEvent_Type = 2
+	ToDoEvent_DELETED   ToDoEvent_Type = 3
+	ToDoEvent_UNDELETED ToDoEvent_Type = 4
+	ToDoEvent_PURGED    ToDoEvent_Type = 5
+	ToDoEvent_REMINDER  ToDoEvent_Type = 6
+)
+
+var ToDoEvent_Type_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "CREATED",
+	2: "UPDATED",
+	3: "DELETED",
+	4: "UNDELETED",
+	5: "PURGED",
+	6: "REMINDER",
+}
+
+var ToDoEvent_Type_value = map[string]int32{
+	"UNKNOWN":   0,
+	"CREATED":   1,
+	"UPDATED":   2,
+	"DELETED":   3,
+	"UNDELETED": 4,
+	"PURGED":    5,
+	"REMINDER":  6,
+}
+
+func (x ToDoEvent_Type) String() string {
+	return proto.EnumName(ToDoEvent_Type_name, int32(x))
+}
+
+func (ToDoEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{46, 0}
+}
+
+type WebhookDelivery_Status int32
+
+const (
+	WebhookDelivery_PENDING   WebhookDelivery_Status = 0
+	WebhookDelivery_DELIVERED WebhookDelivery_Status = 1
+	WebhookDelivery_DEAD      WebhookDelivery_Status = 2
+)
+
+var WebhookDelivery_Status_name = map[int32]string{
+	0: "PENDING",
+	1: "DELIVERED",
+	2: "DEAD",
+}
+
+var WebhookDelivery_Status_value = map[string]int32{
+	"PENDING":   0,
+	"DELIVERED": 1,
+	"DEAD":      2,
+}
+
+func (x WebhookDelivery_Status) String() string {
+	return proto.EnumName(WebhookDelivery_Status_name, int32(x))
+}
+
+func (WebhookDelivery_Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{50, 0}
+}
+
 type ToDo struct {
 	Id                   int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	Title                string               `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
 	Description          string               `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	Reminder             *timestamp.Timestamp `protobuf:"bytes,4,opt,name=reminder,proto3" json:"reminder,omitempty"`
+	DeleteTime           *timestamp.Timestamp `protobuf:"bytes,5,opt,name=deleteTime,proto3" json:"deleteTime,omitempty"`
+	ReminderStatus       ToDo_ReminderStatus  `protobuf:"varint,6,opt,name=reminderStatus,proto3,enum=v1.ToDo_ReminderStatus" json:"reminderStatus,omitempty"`
+	ReminderDeliveryTime *timestamp.Timestamp `protobuf:"bytes,7,opt,name=reminderDeliveryTime,proto3" json:"reminderDeliveryTime,omitempty"`
+	Recurrence           string               `protobuf:"bytes,8,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	TimeZone             string               `protobuf:"bytes,9,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -88,9 +243,45 @@ func (m *ToDo) GetReminder() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *ToDo) GetDeleteTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.DeleteTime
+	}
+	return nil
+}
+
+func (m *ToDo) GetReminderStatus() ToDo_ReminderStatus {
+	if m != nil {
+		return m.ReminderStatus
+	}
+	return ToDo_PENDING
+}
+
+func (m *ToDo) GetReminderDeliveryTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.ReminderDeliveryTime
+	}
+	return nil
+}
+
+func (m *ToDo) GetRecurrence() string {
+	if m != nil {
+		return m.Recurrence
+	}
+	return ""
+}
+
+func (m *ToDo) GetTimeZone() string {
+	if m != nil {
+		return m.TimeZone
+	}
+	return ""
+}
+
 type CreateRequest struct {
 	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
 	ToDo                 *ToDo    `protobuf:"bytes,2,opt,name=toDo,proto3" json:"toDo,omitempty"`
+	RequestId            string   `protobuf:"bytes,3,opt,name=requestId,proto3" json:"requestId,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -135,6 +326,13 @@ func (m *CreateRequest) GetToDo() *ToDo {
 	return nil
 }
 
+func (m *CreateRequest) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
 type CreateResponse struct {
 	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
 	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
@@ -185,6 +383,7 @@ func (m *CreateResponse) GetId() int64 {
 type ReadRequest struct {
 	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
 	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	ShowDeleted          bool     `protobuf:"varint,3,opt,name=showDeleted,proto3" json:"showDeleted,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -229,6 +428,13 @@ func (m *ReadRequest) GetId() int64 {
 	return 0
 }
 
+func (m *ReadRequest) GetShowDeleted() bool {
+	if m != nil {
+		return m.ShowDeleted
+	}
+	return false
+}
+
 type ReadResponse struct {
 	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
 	ToDo                 *ToDo    `protobuf:"bytes,2,opt,name=toDo,proto3" json:"toDo,omitempty"`
@@ -466,6 +672,7 @@ func (m *DeleteResponse) GetDeleted() int64 {
 
 type ReadAllRequest struct {
 	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	ShowDeleted          bool     `protobuf:"varint,2,opt,name=showDeleted,proto3" json:"showDeleted,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -503,6 +710,13 @@ func (m *ReadAllRequest) GetApi() string {
 	return ""
 }
 
+func (m *ReadAllRequest) GetShowDeleted() bool {
+	if m != nil {
+		return m.ShowDeleted
+	}
+	return false
+}
+
 type ReadAllResponse struct {
 	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
 	ToDos                []*ToDo  `protobuf:"bytes,2,rep,name=toDos,proto3" json:"toDos,omitempty"`
@@ -550,179 +764,3540 @@ func (m *ReadAllResponse) GetToDos() []*ToDo {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*ToDo)(nil), "v1.ToDo")
-	proto.RegisterType((*CreateRequest)(nil), "v1.CreateRequest")
-	proto.RegisterType((*CreateResponse)(nil), "v1.CreateResponse")
-	proto.RegisterType((*ReadRequest)(nil), "v1.ReadRequest")
-	proto.RegisterType((*ReadResponse)(nil), "v1.ReadResponse")
-	proto.RegisterType((*UpdateRequest)(nil), "v1.UpdateRequest")
-	proto.RegisterType((*UpdateResponse)(nil), "v1.UpdateResponse")
-	proto.RegisterType((*DeleteRequest)(nil), "v1.DeleteRequest")
-	proto.RegisterType((*DeleteResponse)(nil), "v1.DeleteResponse")
-	proto.RegisterType((*ReadAllRequest)(nil), "v1.ReadAllRequest")
-	proto.RegisterType((*ReadAllResponse)(nil), "v1.ReadAllResponse")
+type StreamAllRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	ShowDeleted          bool     `protobuf:"varint,2,opt,name=showDeleted,proto3" json:"showDeleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("todo_service.proto", fileDescriptor_af1b42e10a177658) }
+func (m *StreamAllRequest) Reset()         { *m = StreamAllRequest{} }
+func (m *StreamAllRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamAllRequest) ProtoMessage()    {}
+func (*StreamAllRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{11}
+}
 
-var fileDescriptor_af1b42e10a177658 = []byte{
-	// 686 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xc1, 0x4e, 0xdb, 0x4a,
-	0x14, 0x95, 0x9d, 0x10, 0xe0, 0x86, 0x24, 0xbc, 0x81, 0xa7, 0x17, 0x59, 0xe8, 0x3d, 0xcb, 0xab,
-	0x28, 0x7a, 0xb1, 0x89, 0x8b, 0x90, 0x1a, 0xa1, 0x02, 0x25, 0xaa, 0xba, 0xe9, 0xc6, 0xa5, 0x9b,
-	0x6e, 0x2a, 0x63, 0x4f, 0xcd, 0x20, 0xc7, 0xe3, 0xce, 0x4c, 0x42, 0x24, 0xc4, 0xa6, 0x8b, 0xae,
-	0xab, 0x76, 0xd7, 0x0f, 0xea, 0xa2, 0xdb, 0xfe, 0x42, 0x3f, 0xa4, 0x9a, 0x19, 0x3b, 0x60, 0x20,
-	0x6c, 0xba, 0xb2, 0xe7, 0xce, 0xb9, 0xf7, 0x9c, 0xe3, 0x33, 0x1e, 0x40, 0x82, 0xc6, 0xf4, 0x1d,
-	0xc7, 0x6c, 0x46, 0x22, 0xec, 0xe6, 0x8c, 0x0a, 0x8a, 0xcc, 0xd9, 0xd0, 0xfa, 0x2f, 0xa1, 0x34,
-	0x49, 0xb1, 0xa7, 0x2a, 0x67, 0xd3, 0xf7, 0x9e, 0x20, 0x13, 0xcc, 0x45, 0x38, 0xc9, 0x35, 0xc8,
-	0xda, 0x29, 0x00, 0x61, 0x4e, 0xbc, 0x30, 0xcb, 0xa8, 0x08, 0x05, 0xa1, 0x19, 0x2f, 0x76, 0xff,
-	0x57, 0x8f, 0x68, 0x90, 0xe0, 0x6c, 0xc0, 0x2f, 0xc3, 0x24, 0xc1, 0xcc, 0xa3, 0xb9, 0x42, 0xdc,
-	0x47, 0x3b, 0x9f, 0x0c, 0xa8, 0x9f, 0xd2, 0x31, 0x45, 0x6d, 0x30, 0x49, 0xdc, 0x35, 0x6c, 0xa3,
-	0x57, 0x0b, 0x4c, 0x12, 0xa3, 0x6d, 0x58, 0x11, 0x44, 0xa4, 0xb8, 0x6b, 0xda, 0x46, 0x6f, 0x3d,
-	0xd0, 0x0b, 0x64, 0x43, 0x33, 0xc6, 0x3c, 0x62, 0x44, 0x0d, 0xec, 0xd6, 0xd4, 0xde, 0xed, 0x12,
-	0xda, 0x87, 0x35, 0x86, 0x27, 0x24, 0x8b, 0x31, 0xeb, 0xd6, 0x6d, 0xa3, 0xd7, 0xf4, 0x2d, 0x57,
-	0xeb, 0x75, 0x4b, 0x43, 0xee, 0x69, 0x69, 0x28, 0x58, 0x60, 0x9d, 0x43, 0x68, 0x9d, 0x30, 0x1c,
-	0x0a, 0x1c, 0xe0, 0x0f, 0x53, 0xcc, 0x05, 0xda, 0x84, 0x5a, 0x98, 0x13, 0xa5, 0x68, 0x3d, 0x90,
-	0xaf, 0x68, 0x07, 0xea, 0x82, 0x8e, 0xa9, 0x52, 0xd4, 0xf4, 0xd7, 0xdc, 0xd9, 0xd0, 0x95, 0xd2,
-	0x03, 0x55, 0x75, 0x7c, 0x68, 0x97, 0x03, 0x78, 0x4e, 0x33, 0x8e, 0x1f, 0x98, 0xa0, 0x4d, 0x9a,
-	0xa5, 0x49, 0xc7, 0x83, 0x66, 0x80, 0xc3, 0x78, 0x39, 0xe5, 0xdd, 0x86, 0x67, 0xb0, 0xa1, 0x1b,
-	0x96, 0x52, 0x3c, 0x2e, 0xf2, 0x10, 0x5a, 0x6f, 0xf2, 0xf8, 0x0f, 0x5c, 0x1e, 0x40, 0xbb, 0x1c,
-	0xb0, 0x54, 0x42, 0x17, 0x56, 0xa7, 0x0a, 0x53, 0x2a, 0x2f, 0x97, 0xce, 0x10, 0x5a, 0x63, 0x9c,
-	0xe2, 0xc7, 0xe8, 0xef, 0x3a, 0x3e, 0x80, 0x76, 0xd9, 0xf2, 0x18, 0x61, 0xac, 0x30, 0x0b, 0xc2,
-	0x62, 0xe9, 0x38, 0xd0, 0x96, 0xdf, 0xeb, 0x38, 0x4d, 0x97, 0x32, 0x3a, 0x27, 0xd0, 0x59, 0x60,
-	0x96, 0x52, 0xfc, 0x0b, 0x2b, 0xd2, 0x3f, 0xef, 0x9a, 0x76, 0xad, 0xf2, 0x59, 0x74, 0xd9, 0xff,
-	0x5c, 0x83, 0xa6, 0x5c, 0xbf, 0xd6, 0xbf, 0x13, 0x1a, 0x43, 0x43, 0x9f, 0x06, 0xf4, 0x97, 0x84,
-	0x56, 0x8e, 0x96, 0x85, 0x6e, 0x97, 0x34, 0xa5, 0xb3, 0xf5, 0xf1, 0xe7, 0xaf, 0xaf, 0x66, 0xcb,
-	0x59, 0xf3, 0x66, 0x43, 0x4f, 0xfe, 0x99, 0x23, 0xa3, 0x8f, 0x8e, 0xa0, 0x2e, 0xa5, 0xa1, 0x8e,
-	0x6c, 0xb8, 0x75, 0x52, 0xac, 0xcd, 0x9b, 0x42, 0xd1, 0xff, 0xb7, 0xea, 0xef, 0xa0, 0x96, 0xec,
-	0x8f, 0x69, 0x4c, 0xbd, 0x2b, 0x12, 0x5f, 0xa3, 0x04, 0x1a, 0x3a, 0x2f, 0xad, 0xa3, 0x12, 0xbe,
-	0xd6, 0x51, 0x8d, 0xd3, 0xd9, 0x57, 0x73, 0x76, 0x2d, 0x54, 0xea, 0xf0, 0xae, 0xa4, 0x43, 0x97,
-	0xc4, 0xd7, 0x23, 0xa3, 0xff, 0xf6, 0x1f, 0xff, 0xe1, 0x0d, 0xf4, 0x02, 0x1a, 0x3a, 0x27, 0x4d,
-	0x54, 0x89, 0x59, 0x13, 0x55, 0x63, 0x2c, 0x05, 0xf7, 0x5b, 0x37, 0xf3, 0xa4, 0xe0, 0x97, 0xb0,
-	0x5a, 0xa4, 0x81, 0x50, 0x69, 0xf2, 0x26, 0x3e, 0x6b, 0xab, 0x52, 0x2b, 0x46, 0x6d, 0xab, 0x51,
-	0x6d, 0xb4, 0xb1, 0x18, 0x15, 0xa6, 0xe9, 0xf3, 0x1f, 0xc6, 0x97, 0xe3, 0xef, 0x06, 0x3a, 0x83,
-	0x0d, 0x19, 0x8c, 0x5d, 0x5c, 0x74, 0xce, 0x2b, 0xf0, 0x12, 0x3a, 0x48, 0x58, 0x1e, 0x0d, 0xce,
-	0x85, 0xc8, 0x07, 0x0c, 0x73, 0x31, 0x98, 0x90, 0x88, 0xd1, 0x02, 0x31, 0x10, 0x53, 0x41, 0x19,
-	0x09, 0x53, 0x3b, 0x67, 0xf4, 0x02, 0x47, 0x02, 0x75, 0x24, 0x90, 0x8f, 0x3c, 0x6f, 0x3e, 0x9f,
-	0xbb, 0x11, 0x9d, 0x58, 0xeb, 0xf3, 0xf9, 0x91, 0x7e, 0xf5, 0x6b, 0x43, 0x77, 0xb7, 0x6f, 0x18,
-	0xfe, 0x66, 0x98, 0xe7, 0x29, 0x89, 0xd4, 0xe5, 0xe6, 0x5d, 0x70, 0x9a, 0x8d, 0xee, 0x55, 0x82,
-	0xa7, 0x50, 0xdb, 0xdb, 0xdd, 0x43, 0x3e, 0xf4, 0x02, 0x2c, 0xa6, 0x2c, 0xb3, 0x2f, 0xcf, 0x71,
-	0x66, 0x8b, 0x73, 0x6c, 0x33, 0xcc, 0xe9, 0x94, 0x45, 0xd8, 0x8e, 0x29, 0xe6, 0x76, 0x46, 0x85,
-	0x8d, 0xe7, 0x84, 0x0b, 0x17, 0x35, 0xa0, 0xfe, 0xcd, 0x34, 0x56, 0xcf, 0x1a, 0xea, 0xf2, 0x7a,
-	0xf2, 0x3b, 0x00, 0x00, 0xff, 0xff, 0x0b, 0x7c, 0xe7, 0x8c, 0xb5, 0x05, 0x00, 0x00,
+func (m *StreamAllRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamAllRequest.Unmarshal(m, b)
+}
+func (m *StreamAllRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamAllRequest.Marshal(b, m, deterministic)
+}
+func (m *StreamAllRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamAllRequest.Merge(m, src)
+}
+func (m *StreamAllRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamAllRequest.Size(m)
+}
+func (m *StreamAllRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamAllRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_StreamAllRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *StreamAllRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
 
-// ToDoServiceClient is the client API for ToDoService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ToDoServiceClient interface {
-	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
-	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
-	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
-	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
-	ReadAll(ctx context.Context, in *ReadAllRequest, opts ...grpc.CallOption) (*ReadAllResponse, error)
+func (m *StreamAllRequest) GetShowDeleted() bool {
+	if m != nil {
+		return m.ShowDeleted
+	}
+	return false
 }
 
-type toDoServiceClient struct {
-	cc *grpc.ClientConn
+type StreamAllResponse struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	ToDo                 *ToDo    `protobuf:"bytes,2,opt,name=toDo,proto3" json:"toDo,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func NewToDoServiceClient(cc *grpc.ClientConn) ToDoServiceClient {
-	return &toDoServiceClient{cc}
+func (m *StreamAllResponse) Reset()         { *m = StreamAllResponse{} }
+func (m *StreamAllResponse) String() string { return proto.CompactTextString(m) }
+func (*StreamAllResponse) ProtoMessage()    {}
+func (*StreamAllResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{12}
 }
 
-func (c *toDoServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
-	out := new(CreateResponse)
-	err := c.cc.Invoke(ctx, "/v1.ToDoService/Create", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *StreamAllResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamAllResponse.Unmarshal(m, b)
+}
+func (m *StreamAllResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamAllResponse.Marshal(b, m, deterministic)
+}
+func (m *StreamAllResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamAllResponse.Merge(m, src)
+}
+func (m *StreamAllResponse) XXX_Size() int {
+	return xxx_messageInfo_StreamAllResponse.Size(m)
+}
+func (m *StreamAllResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamAllResponse.DiscardUnknown(m)
 }
 
-func (c *toDoServiceClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error) {
-	out := new(ReadResponse)
-	err := c.cc.Invoke(ctx, "/v1.ToDoService/Read", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_StreamAllResponse proto.InternalMessageInfo
+
+func (m *StreamAllResponse) GetApi() string {
+	if m != nil {
+		return m.Api
 	}
-	return out, nil
+	return ""
 }
 
-func (c *toDoServiceClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error) {
-	out := new(UpdateResponse)
-	err := c.cc.Invoke(ctx, "/v1.ToDoService/Update", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *StreamAllResponse) GetToDo() *ToDo {
+	if m != nil {
+		return m.ToDo
 	}
-	return out, nil
+	return nil
 }
 
-func (c *toDoServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
-	out := new(DeleteResponse)
-	err := c.cc.Invoke(ctx, "/v1.ToDoService/Delete", in, out, opts...)
-	if err != nil {
-		return nil, err
+type UndeleteRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UndeleteRequest) Reset()         { *m = UndeleteRequest{} }
+func (m *UndeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*UndeleteRequest) ProtoMessage()    {}
+func (*UndeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{13}
+}
+
+func (m *UndeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UndeleteRequest.Unmarshal(m, b)
+}
+func (m *UndeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UndeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *UndeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UndeleteRequest.Merge(m, src)
+}
+func (m *UndeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_UndeleteRequest.Size(m)
+}
+func (m *UndeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UndeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UndeleteRequest proto.InternalMessageInfo
+
+func (m *UndeleteRequest) GetApi() string {
+	if m != nil {
+		return m.Api
 	}
-	return out, nil
+	return ""
 }
 
-func (c *toDoServiceClient) ReadAll(ctx context.Context, in *ReadAllRequest, opts ...grpc.CallOption) (*ReadAllResponse, error) {
-	out := new(ReadAllResponse)
-	err := c.cc.Invoke(ctx, "/v1.ToDoService/ReadAll", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *UndeleteRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
 	}
-	return out, nil
+	return 0
 }
 
-// ToDoServiceServer is the server API for ToDoService service.
-type ToDoServiceServer interface {
-	Create(context.Context, *CreateRequest) (*CreateResponse, error)
-	Read(context.Context, *ReadRequest) (*ReadResponse, error)
-	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
-	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
-	ReadAll(context.Context, *ReadAllRequest) (*ReadAllResponse, error)
+type UndeleteResponse struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Undeleted            int64    `protobuf:"varint,2,opt,name=undeleted,proto3" json:"undeleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func RegisterToDoServiceServer(s *grpc.Server, srv ToDoServiceServer) {
-	s.RegisterService(&_ToDoService_serviceDesc, srv)
+func (m *UndeleteResponse) Reset()         { *m = UndeleteResponse{} }
+func (m *UndeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*UndeleteResponse) ProtoMessage()    {}
+func (*UndeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{14}
 }
 
-func _ToDoService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(ToDoServiceServer).Create(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/v1.ToDoService/Create",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ToDoServiceServer).Create(ctx, req.(*CreateRequest))
+func (m *UndeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UndeleteResponse.Unmarshal(m, b)
+}
+func (m *UndeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UndeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *UndeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UndeleteResponse.Merge(m, src)
+}
+func (m *UndeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_UndeleteResponse.Size(m)
+}
+func (m *UndeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UndeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UndeleteResponse proto.InternalMessageInfo
+
+func (m *UndeleteResponse) GetApi() string {
+	if m != nil {
+		return m.Api
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _ToDoService_Read_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReadRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *UndeleteResponse) GetUndeleted() int64 {
+	if m != nil {
+		return m.Undeleted
 	}
-	if interceptor == nil {
-		return srv.(ToDoServiceServer).Read(ctx, in)
+	return 0
+}
+
+type PurgeRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PurgeRequest) Reset()         { *m = PurgeRequest{} }
+func (m *PurgeRequest) String() string { return proto.CompactTextString(m) }
+func (*PurgeRequest) ProtoMessage()    {}
+func (*PurgeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{15}
+}
+
+func (m *PurgeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PurgeRequest.Unmarshal(m, b)
+}
+func (m *PurgeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PurgeRequest.Marshal(b, m, deterministic)
+}
+func (m *PurgeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeRequest.Merge(m, src)
+}
+func (m *PurgeRequest) XXX_Size() int {
+	return xxx_messageInfo_PurgeRequest.Size(m)
+}
+func (m *PurgeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeRequest proto.InternalMessageInfo
+
+func (m *PurgeRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *PurgeRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type PurgeResponse struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Purged               int64    `protobuf:"varint,2,opt,name=purged,proto3" json:"purged,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PurgeResponse) Reset()         { *m = PurgeResponse{} }
+func (m *PurgeResponse) String() string { return proto.CompactTextString(m) }
+func (*PurgeResponse) ProtoMessage()    {}
+func (*PurgeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{16}
+}
+
+func (m *PurgeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PurgeResponse.Unmarshal(m, b)
+}
+func (m *PurgeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PurgeResponse.Marshal(b, m, deterministic)
+}
+func (m *PurgeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeResponse.Merge(m, src)
+}
+func (m *PurgeResponse) XXX_Size() int {
+	return xxx_messageInfo_PurgeResponse.Size(m)
+}
+func (m *PurgeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeResponse proto.InternalMessageInfo
+
+func (m *PurgeResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *PurgeResponse) GetPurged() int64 {
+	if m != nil {
+		return m.Purged
+	}
+	return 0
+}
+
+type Revision struct {
+	Revision             int64                `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	Action               ToDoEvent_Type       `protobuf:"varint,2,opt,name=action,proto3,enum=v1.ToDoEvent_Type" json:"action,omitempty"`
+	OldToDo              *ToDo                `protobuf:"bytes,3,opt,name=oldToDo,proto3" json:"oldToDo,omitempty"`
+	NewToDo              *ToDo                `protobuf:"bytes,4,opt,name=newToDo,proto3" json:"newToDo,omitempty"`
+	Principal            string               `protobuf:"bytes,5,opt,name=principal,proto3" json:"principal,omitempty"`
+	ChangeTime           *timestamp.Timestamp `protobuf:"bytes,6,opt,name=changeTime,proto3" json:"changeTime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Revision) Reset()         { *m = Revision{} }
+func (m *Revision) String() string { return proto.CompactTextString(m) }
+func (*Revision) ProtoMessage()    {}
+func (*Revision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{17}
+}
+
+func (m *Revision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Revision.Unmarshal(m, b)
+}
+func (m *Revision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Revision.Marshal(b, m, deterministic)
+}
+func (m *Revision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Revision.Merge(m, src)
+}
+func (m *Revision) XXX_Size() int {
+	return xxx_messageInfo_Revision.Size(m)
+}
+func (m *Revision) XXX_DiscardUnknown() {
+	xxx_messageInfo_Revision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Revision proto.InternalMessageInfo
+
+func (m *Revision) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *Revision) GetAction() ToDoEvent_Type {
+	if m != nil {
+		return m.Action
+	}
+	return ToDoEvent_UNKNOWN
+}
+
+func (m *Revision) GetOldToDo() *ToDo {
+	if m != nil {
+		return m.OldToDo
+	}
+	return nil
+}
+
+func (m *Revision) GetNewToDo() *ToDo {
+	if m != nil {
+		return m.NewToDo
+	}
+	return nil
+}
+
+func (m *Revision) GetPrincipal() string {
+	if m != nil {
+		return m.Principal
+	}
+	return ""
+}
+
+func (m *Revision) GetChangeTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.ChangeTime
+	}
+	return nil
+}
+
+type ListRevisionsRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListRevisionsRequest) Reset()         { *m = ListRevisionsRequest{} }
+func (m *ListRevisionsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRevisionsRequest) ProtoMessage()    {}
+func (*ListRevisionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{18}
+}
+
+func (m *ListRevisionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRevisionsRequest.Unmarshal(m, b)
+}
+func (m *ListRevisionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRevisionsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRevisionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRevisionsRequest.Merge(m, src)
+}
+func (m *ListRevisionsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRevisionsRequest.Size(m)
+}
+func (m *ListRevisionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRevisionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRevisionsRequest proto.InternalMessageInfo
+
+func (m *ListRevisionsRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ListRevisionsRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type ListRevisionsResponse struct {
+	Api                  string      `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Revisions            []*Revision `protobuf:"bytes,2,rep,name=revisions,proto3" json:"revisions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListRevisionsResponse) Reset()         { *m = ListRevisionsResponse{} }
+func (m *ListRevisionsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListRevisionsResponse) ProtoMessage()    {}
+func (*ListRevisionsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{19}
+}
+
+func (m *ListRevisionsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRevisionsResponse.Unmarshal(m, b)
+}
+func (m *ListRevisionsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRevisionsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListRevisionsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRevisionsResponse.Merge(m, src)
+}
+func (m *ListRevisionsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListRevisionsResponse.Size(m)
+}
+func (m *ListRevisionsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRevisionsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRevisionsResponse proto.InternalMessageInfo
+
+func (m *ListRevisionsResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ListRevisionsResponse) GetRevisions() []*Revision {
+	if m != nil {
+		return m.Revisions
+	}
+	return nil
+}
+
+type ReadAtRequest struct {
+	Api                  string               `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64                `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Time                 *timestamp.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ReadAtRequest) Reset()         { *m = ReadAtRequest{} }
+func (m *ReadAtRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadAtRequest) ProtoMessage()    {}
+func (*ReadAtRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{20}
+}
+
+func (m *ReadAtRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadAtRequest.Unmarshal(m, b)
+}
+func (m *ReadAtRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadAtRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadAtRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadAtRequest.Merge(m, src)
+}
+func (m *ReadAtRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadAtRequest.Size(m)
+}
+func (m *ReadAtRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadAtRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadAtRequest proto.InternalMessageInfo
+
+func (m *ReadAtRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ReadAtRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *ReadAtRequest) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+type ReadAtResponse struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	ToDo                 *ToDo    `protobuf:"bytes,2,opt,name=toDo,proto3" json:"toDo,omitempty"`
+	Revision             int64    `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadAtResponse) Reset()         { *m = ReadAtResponse{} }
+func (m *ReadAtResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadAtResponse) ProtoMessage()    {}
+func (*ReadAtResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{21}
+}
+
+func (m *ReadAtResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadAtResponse.Unmarshal(m, b)
+}
+func (m *ReadAtResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadAtResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadAtResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadAtResponse.Merge(m, src)
+}
+func (m *ReadAtResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadAtResponse.Size(m)
+}
+func (m *ReadAtResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadAtResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadAtResponse proto.InternalMessageInfo
+
+func (m *ReadAtResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ReadAtResponse) GetToDo() *ToDo {
+	if m != nil {
+		return m.ToDo
+	}
+	return nil
+}
+
+func (m *ReadAtResponse) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+type RevertRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Revision             int64    `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevertRequest) Reset()         { *m = RevertRequest{} }
+func (m *RevertRequest) String() string { return proto.CompactTextString(m) }
+func (*RevertRequest) ProtoMessage()    {}
+func (*RevertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{22}
+}
+
+func (m *RevertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevertRequest.Unmarshal(m, b)
+}
+func (m *RevertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevertRequest.Marshal(b, m, deterministic)
+}
+func (m *RevertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevertRequest.Merge(m, src)
+}
+func (m *RevertRequest) XXX_Size() int {
+	return xxx_messageInfo_RevertRequest.Size(m)
+}
+func (m *RevertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevertRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevertRequest proto.InternalMessageInfo
+
+func (m *RevertRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *RevertRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *RevertRequest) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+type RevertResponse struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	ToDo                 *ToDo    `protobuf:"bytes,2,opt,name=toDo,proto3" json:"toDo,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevertResponse) Reset()         { *m = RevertResponse{} }
+func (m *RevertResponse) String() string { return proto.CompactTextString(m) }
+func (*RevertResponse) ProtoMessage()    {}
+func (*RevertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{23}
+}
+
+func (m *RevertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevertResponse.Unmarshal(m, b)
+}
+func (m *RevertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevertResponse.Marshal(b, m, deterministic)
+}
+func (m *RevertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevertResponse.Merge(m, src)
+}
+func (m *RevertResponse) XXX_Size() int {
+	return xxx_messageInfo_RevertResponse.Size(m)
+}
+func (m *RevertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevertResponse proto.InternalMessageInfo
+
+func (m *RevertResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *RevertResponse) GetToDo() *ToDo {
+	if m != nil {
+		return m.ToDo
+	}
+	return nil
+}
+
+type CompleteRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CompleteRequest) Reset()         { *m = CompleteRequest{} }
+func (m *CompleteRequest) String() string { return proto.CompactTextString(m) }
+func (*CompleteRequest) ProtoMessage()    {}
+func (*CompleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{24}
+}
+
+func (m *CompleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompleteRequest.Unmarshal(m, b)
+}
+func (m *CompleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompleteRequest.Marshal(b, m, deterministic)
+}
+func (m *CompleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompleteRequest.Merge(m, src)
+}
+func (m *CompleteRequest) XXX_Size() int {
+	return xxx_messageInfo_CompleteRequest.Size(m)
+}
+func (m *CompleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompleteRequest proto.InternalMessageInfo
+
+func (m *CompleteRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *CompleteRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type CompleteResponse struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	ToDo                 *ToDo    `protobuf:"bytes,2,opt,name=toDo,proto3" json:"toDo,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CompleteResponse) Reset()         { *m = CompleteResponse{} }
+func (m *CompleteResponse) String() string { return proto.CompactTextString(m) }
+func (*CompleteResponse) ProtoMessage()    {}
+func (*CompleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{25}
+}
+
+func (m *CompleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompleteResponse.Unmarshal(m, b)
+}
+func (m *CompleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompleteResponse.Marshal(b, m, deterministic)
+}
+func (m *CompleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompleteResponse.Merge(m, src)
+}
+func (m *CompleteResponse) XXX_Size() int {
+	return xxx_messageInfo_CompleteResponse.Size(m)
+}
+func (m *CompleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompleteResponse proto.InternalMessageInfo
+
+func (m *CompleteResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *CompleteResponse) GetToDo() *ToDo {
+	if m != nil {
+		return m.ToDo
+	}
+	return nil
+}
+
+type ListOccurrencesRequest struct {
+	Api                  string               `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64                `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Count                int32                `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Recurrence           string               `protobuf:"bytes,4,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	Start                *timestamp.Timestamp `protobuf:"bytes,5,opt,name=start,proto3" json:"start,omitempty"`
+	TimeZone             string               `protobuf:"bytes,6,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ListOccurrencesRequest) Reset()         { *m = ListOccurrencesRequest{} }
+func (m *ListOccurrencesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListOccurrencesRequest) ProtoMessage()    {}
+func (*ListOccurrencesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{26}
+}
+
+func (m *ListOccurrencesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOccurrencesRequest.Unmarshal(m, b)
+}
+func (m *ListOccurrencesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOccurrencesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListOccurrencesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOccurrencesRequest.Merge(m, src)
+}
+func (m *ListOccurrencesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListOccurrencesRequest.Size(m)
+}
+func (m *ListOccurrencesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOccurrencesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOccurrencesRequest proto.InternalMessageInfo
+
+func (m *ListOccurrencesRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ListOccurrencesRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *ListOccurrencesRequest) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ListOccurrencesRequest) GetRecurrence() string {
+	if m != nil {
+		return m.Recurrence
+	}
+	return ""
+}
+
+func (m *ListOccurrencesRequest) GetStart() *timestamp.Timestamp {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (m *ListOccurrencesRequest) GetTimeZone() string {
+	if m != nil {
+		return m.TimeZone
+	}
+	return ""
+}
+
+type ListOccurrencesResponse struct {
+	Api                  string                 `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Occurrences          []*timestamp.Timestamp `protobuf:"bytes,2,rep,name=occurrences,proto3" json:"occurrences,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *ListOccurrencesResponse) Reset()         { *m = ListOccurrencesResponse{} }
+func (m *ListOccurrencesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListOccurrencesResponse) ProtoMessage()    {}
+func (*ListOccurrencesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{27}
+}
+
+func (m *ListOccurrencesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOccurrencesResponse.Unmarshal(m, b)
+}
+func (m *ListOccurrencesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOccurrencesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListOccurrencesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOccurrencesResponse.Merge(m, src)
+}
+func (m *ListOccurrencesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListOccurrencesResponse.Size(m)
+}
+func (m *ListOccurrencesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOccurrencesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOccurrencesResponse proto.InternalMessageInfo
+
+func (m *ListOccurrencesResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ListOccurrencesResponse) GetOccurrences() []*timestamp.Timestamp {
+	if m != nil {
+		return m.Occurrences
+	}
+	return nil
+}
+
+type ImportICSRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Calendar             string   `protobuf:"bytes,2,opt,name=calendar,proto3" json:"calendar,omitempty"`
+	BestEffort           bool     `protobuf:"varint,3,opt,name=bestEffort,proto3" json:"bestEffort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportICSRequest) Reset()         { *m = ImportICSRequest{} }
+func (m *ImportICSRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportICSRequest) ProtoMessage()    {}
+func (*ImportICSRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{28}
+}
+
+func (m *ImportICSRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportICSRequest.Unmarshal(m, b)
+}
+func (m *ImportICSRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportICSRequest.Marshal(b, m, deterministic)
+}
+func (m *ImportICSRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportICSRequest.Merge(m, src)
+}
+func (m *ImportICSRequest) XXX_Size() int {
+	return xxx_messageInfo_ImportICSRequest.Size(m)
+}
+func (m *ImportICSRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportICSRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportICSRequest proto.InternalMessageInfo
+
+func (m *ImportICSRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ImportICSRequest) GetCalendar() string {
+	if m != nil {
+		return m.Calendar
+	}
+	return ""
+}
+
+func (m *ImportICSRequest) GetBestEffort() bool {
+	if m != nil {
+		return m.BestEffort
+	}
+	return false
+}
+
+type ImportICSResponse struct {
+	Api                  string         `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Results              []*BatchResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ImportICSResponse) Reset()         { *m = ImportICSResponse{} }
+func (m *ImportICSResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportICSResponse) ProtoMessage()    {}
+func (*ImportICSResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{29}
+}
+
+func (m *ImportICSResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportICSResponse.Unmarshal(m, b)
+}
+func (m *ImportICSResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportICSResponse.Marshal(b, m, deterministic)
+}
+func (m *ImportICSResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportICSResponse.Merge(m, src)
+}
+func (m *ImportICSResponse) XXX_Size() int {
+	return xxx_messageInfo_ImportICSResponse.Size(m)
+}
+func (m *ImportICSResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportICSResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportICSResponse proto.InternalMessageInfo
+
+func (m *ImportICSResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ImportICSResponse) GetResults() []*BatchResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type ExportICSRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Token                string   `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	Component            string   `protobuf:"bytes,4,opt,name=component,proto3" json:"component,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportICSRequest) Reset()         { *m = ExportICSRequest{} }
+func (m *ExportICSRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportICSRequest) ProtoMessage()    {}
+func (*ExportICSRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{30}
+}
+
+func (m *ExportICSRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportICSRequest.Unmarshal(m, b)
+}
+func (m *ExportICSRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportICSRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportICSRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportICSRequest.Merge(m, src)
+}
+func (m *ExportICSRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportICSRequest.Size(m)
+}
+func (m *ExportICSRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportICSRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportICSRequest proto.InternalMessageInfo
+
+func (m *ExportICSRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ExportICSRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *ExportICSRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *ExportICSRequest) GetComponent() string {
+	if m != nil {
+		return m.Component
+	}
+	return ""
+}
+
+type ExportICSResponse struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Calendar             string   `protobuf:"bytes,2,opt,name=calendar,proto3" json:"calendar,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportICSResponse) Reset()         { *m = ExportICSResponse{} }
+func (m *ExportICSResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportICSResponse) ProtoMessage()    {}
+func (*ExportICSResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{31}
+}
+
+func (m *ExportICSResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportICSResponse.Unmarshal(m, b)
+}
+func (m *ExportICSResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportICSResponse.Marshal(b, m, deterministic)
+}
+func (m *ExportICSResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportICSResponse.Merge(m, src)
+}
+func (m *ExportICSResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportICSResponse.Size(m)
+}
+func (m *ExportICSResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportICSResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportICSResponse proto.InternalMessageInfo
+
+func (m *ExportICSResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ExportICSResponse) GetCalendar() string {
+	if m != nil {
+		return m.Calendar
+	}
+	return ""
+}
+
+type GetCalendarTokenRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetCalendarTokenRequest) Reset()         { *m = GetCalendarTokenRequest{} }
+func (m *GetCalendarTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*GetCalendarTokenRequest) ProtoMessage()    {}
+func (*GetCalendarTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{32}
+}
+
+func (m *GetCalendarTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCalendarTokenRequest.Unmarshal(m, b)
+}
+func (m *GetCalendarTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCalendarTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *GetCalendarTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCalendarTokenRequest.Merge(m, src)
+}
+func (m *GetCalendarTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_GetCalendarTokenRequest.Size(m)
+}
+func (m *GetCalendarTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCalendarTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCalendarTokenRequest proto.InternalMessageInfo
+
+func (m *GetCalendarTokenRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+type GetCalendarTokenResponse struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	User                 string   `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Token                string   `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetCalendarTokenResponse) Reset()         { *m = GetCalendarTokenResponse{} }
+func (m *GetCalendarTokenResponse) String() string { return proto.CompactTextString(m) }
+func (*GetCalendarTokenResponse) ProtoMessage()    {}
+func (*GetCalendarTokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{33}
+}
+
+func (m *GetCalendarTokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetCalendarTokenResponse.Unmarshal(m, b)
+}
+func (m *GetCalendarTokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetCalendarTokenResponse.Marshal(b, m, deterministic)
+}
+func (m *GetCalendarTokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetCalendarTokenResponse.Merge(m, src)
+}
+func (m *GetCalendarTokenResponse) XXX_Size() int {
+	return xxx_messageInfo_GetCalendarTokenResponse.Size(m)
+}
+func (m *GetCalendarTokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetCalendarTokenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetCalendarTokenResponse proto.InternalMessageInfo
+
+func (m *GetCalendarTokenResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *GetCalendarTokenResponse) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *GetCalendarTokenResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type ExportRequest struct {
+	Api                  string               `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Format               DataFormat           `protobuf:"varint,2,opt,name=format,proto3,enum=v1.DataFormat" json:"format,omitempty"`
+	ShowDeleted          bool                 `protobuf:"varint,3,opt,name=showDeleted,proto3" json:"showDeleted,omitempty"`
+	ReminderFrom         *timestamp.Timestamp `protobuf:"bytes,4,opt,name=reminderFrom,proto3" json:"reminderFrom,omitempty"`
+	ReminderTo           *timestamp.Timestamp `protobuf:"bytes,5,opt,name=reminderTo,proto3" json:"reminderTo,omitempty"`
+	TitleContains        string               `protobuf:"bytes,6,opt,name=titleContains,proto3" json:"titleContains,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ExportRequest) Reset()         { *m = ExportRequest{} }
+func (m *ExportRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportRequest) ProtoMessage()    {}
+func (*ExportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{34}
+}
+
+func (m *ExportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportRequest.Unmarshal(m, b)
+}
+func (m *ExportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportRequest.Merge(m, src)
+}
+func (m *ExportRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportRequest.Size(m)
+}
+func (m *ExportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportRequest proto.InternalMessageInfo
+
+func (m *ExportRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ExportRequest) GetFormat() DataFormat {
+	if m != nil {
+		return m.Format
+	}
+	return DataFormat_JSONL
+}
+
+func (m *ExportRequest) GetShowDeleted() bool {
+	if m != nil {
+		return m.ShowDeleted
+	}
+	return false
+}
+
+func (m *ExportRequest) GetReminderFrom() *timestamp.Timestamp {
+	if m != nil {
+		return m.ReminderFrom
+	}
+	return nil
+}
+
+func (m *ExportRequest) GetReminderTo() *timestamp.Timestamp {
+	if m != nil {
+		return m.ReminderTo
+	}
+	return nil
+}
+
+func (m *ExportRequest) GetTitleContains() string {
+	if m != nil {
+		return m.TitleContains
+	}
+	return ""
+}
+
+type ExportResponse struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportResponse) Reset()         { *m = ExportResponse{} }
+func (m *ExportResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportResponse) ProtoMessage()    {}
+func (*ExportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{35}
+}
+
+func (m *ExportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportResponse.Unmarshal(m, b)
+}
+func (m *ExportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportResponse.Marshal(b, m, deterministic)
+}
+func (m *ExportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportResponse.Merge(m, src)
+}
+func (m *ExportResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportResponse.Size(m)
+}
+func (m *ExportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportResponse proto.InternalMessageInfo
+
+func (m *ExportResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ExportResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type ImportRequest struct {
+	Api                  string     `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Format               DataFormat `protobuf:"varint,2,opt,name=format,proto3,enum=v1.DataFormat" json:"format,omitempty"`
+	DryRun               bool       `protobuf:"varint,3,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	Data                 []byte     `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ImportRequest) Reset()         { *m = ImportRequest{} }
+func (m *ImportRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportRequest) ProtoMessage()    {}
+func (*ImportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{36}
+}
+
+func (m *ImportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportRequest.Unmarshal(m, b)
+}
+func (m *ImportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportRequest.Marshal(b, m, deterministic)
+}
+func (m *ImportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportRequest.Merge(m, src)
+}
+func (m *ImportRequest) XXX_Size() int {
+	return xxx_messageInfo_ImportRequest.Size(m)
+}
+func (m *ImportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportRequest proto.InternalMessageInfo
+
+func (m *ImportRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ImportRequest) GetFormat() DataFormat {
+	if m != nil {
+		return m.Format
+	}
+	return DataFormat_JSONL
+}
+
+func (m *ImportRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *ImportRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type ImportResult struct {
+	Record               int32                `protobuf:"varint,1,opt,name=record,proto3" json:"record,omitempty"`
+	ExternalId           string               `protobuf:"bytes,2,opt,name=externalId,proto3" json:"externalId,omitempty"`
+	Id                   int64                `protobuf:"varint,3,opt,name=id,proto3" json:"id,omitempty"`
+	Outcome              ImportResult_Outcome `protobuf:"varint,4,opt,name=outcome,proto3,enum=v1.ImportResult_Outcome" json:"outcome,omitempty"`
+	Status               *status.Status       `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ImportResult) Reset()         { *m = ImportResult{} }
+func (m *ImportResult) String() string { return proto.CompactTextString(m) }
+func (*ImportResult) ProtoMessage()    {}
+func (*ImportResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{37}
+}
+
+func (m *ImportResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportResult.Unmarshal(m, b)
+}
+func (m *ImportResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportResult.Marshal(b, m, deterministic)
+}
+func (m *ImportResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportResult.Merge(m, src)
+}
+func (m *ImportResult) XXX_Size() int {
+	return xxx_messageInfo_ImportResult.Size(m)
+}
+func (m *ImportResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportResult proto.InternalMessageInfo
+
+func (m *ImportResult) GetRecord() int32 {
+	if m != nil {
+		return m.Record
+	}
+	return 0
+}
+
+func (m *ImportResult) GetExternalId() string {
+	if m != nil {
+		return m.ExternalId
+	}
+	return ""
+}
+
+func (m *ImportResult) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *ImportResult) GetOutcome() ImportResult_Outcome {
+	if m != nil {
+		return m.Outcome
+	}
+	return ImportResult_CREATED
+}
+
+func (m *ImportResult) GetStatus() *status.Status {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type ImportResponse struct {
+	Api                  string          `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	DryRun               bool            `protobuf:"varint,2,opt,name=dryRun,proto3" json:"dryRun,omitempty"`
+	Created              int32           `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"`
+	Duplicates           int32           `protobuf:"varint,4,opt,name=duplicates,proto3" json:"duplicates,omitempty"`
+	Invalid              int32           `protobuf:"varint,5,opt,name=invalid,proto3" json:"invalid,omitempty"`
+	Results              []*ImportResult `protobuf:"bytes,6,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ImportResponse) Reset()         { *m = ImportResponse{} }
+func (m *ImportResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportResponse) ProtoMessage()    {}
+func (*ImportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{38}
+}
+
+func (m *ImportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImportResponse.Unmarshal(m, b)
+}
+func (m *ImportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImportResponse.Marshal(b, m, deterministic)
+}
+func (m *ImportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportResponse.Merge(m, src)
+}
+func (m *ImportResponse) XXX_Size() int {
+	return xxx_messageInfo_ImportResponse.Size(m)
+}
+func (m *ImportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportResponse proto.InternalMessageInfo
+
+func (m *ImportResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ImportResponse) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *ImportResponse) GetCreated() int32 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *ImportResponse) GetDuplicates() int32 {
+	if m != nil {
+		return m.Duplicates
+	}
+	return 0
+}
+
+func (m *ImportResponse) GetInvalid() int32 {
+	if m != nil {
+		return m.Invalid
+	}
+	return 0
+}
+
+func (m *ImportResponse) GetResults() []*ImportResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type BatchResult struct {
+	Id                   int64          `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status               *status.Status `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *BatchResult) Reset()         { *m = BatchResult{} }
+func (m *BatchResult) String() string { return proto.CompactTextString(m) }
+func (*BatchResult) ProtoMessage()    {}
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{39}
+}
+
+func (m *BatchResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchResult.Unmarshal(m, b)
+}
+func (m *BatchResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchResult.Marshal(b, m, deterministic)
+}
+func (m *BatchResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchResult.Merge(m, src)
+}
+func (m *BatchResult) XXX_Size() int {
+	return xxx_messageInfo_BatchResult.Size(m)
+}
+func (m *BatchResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchResult proto.InternalMessageInfo
+
+func (m *BatchResult) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *BatchResult) GetStatus() *status.Status {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+type BatchCreateRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	ToDos                []*ToDo  `protobuf:"bytes,2,rep,name=toDos,proto3" json:"toDos,omitempty"`
+	BestEffort           bool     `protobuf:"varint,3,opt,name=bestEffort,proto3" json:"bestEffort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchCreateRequest) Reset()         { *m = BatchCreateRequest{} }
+func (m *BatchCreateRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchCreateRequest) ProtoMessage()    {}
+func (*BatchCreateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{40}
+}
+
+func (m *BatchCreateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchCreateRequest.Unmarshal(m, b)
+}
+func (m *BatchCreateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchCreateRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchCreateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchCreateRequest.Merge(m, src)
+}
+func (m *BatchCreateRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchCreateRequest.Size(m)
+}
+func (m *BatchCreateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchCreateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchCreateRequest proto.InternalMessageInfo
+
+func (m *BatchCreateRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchCreateRequest) GetToDos() []*ToDo {
+	if m != nil {
+		return m.ToDos
+	}
+	return nil
+}
+
+func (m *BatchCreateRequest) GetBestEffort() bool {
+	if m != nil {
+		return m.BestEffort
+	}
+	return false
+}
+
+type BatchCreateResponse struct {
+	Api                  string         `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Results              []*BatchResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *BatchCreateResponse) Reset()         { *m = BatchCreateResponse{} }
+func (m *BatchCreateResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchCreateResponse) ProtoMessage()    {}
+func (*BatchCreateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{41}
+}
+
+func (m *BatchCreateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchCreateResponse.Unmarshal(m, b)
+}
+func (m *BatchCreateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchCreateResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchCreateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchCreateResponse.Merge(m, src)
+}
+func (m *BatchCreateResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchCreateResponse.Size(m)
+}
+func (m *BatchCreateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchCreateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchCreateResponse proto.InternalMessageInfo
+
+func (m *BatchCreateResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchCreateResponse) GetResults() []*BatchResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type BatchUpdateRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	ToDos                []*ToDo  `protobuf:"bytes,2,rep,name=toDos,proto3" json:"toDos,omitempty"`
+	BestEffort           bool     `protobuf:"varint,3,opt,name=bestEffort,proto3" json:"bestEffort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchUpdateRequest) Reset()         { *m = BatchUpdateRequest{} }
+func (m *BatchUpdateRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchUpdateRequest) ProtoMessage()    {}
+func (*BatchUpdateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{42}
+}
+
+func (m *BatchUpdateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchUpdateRequest.Unmarshal(m, b)
+}
+func (m *BatchUpdateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchUpdateRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchUpdateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchUpdateRequest.Merge(m, src)
+}
+func (m *BatchUpdateRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchUpdateRequest.Size(m)
+}
+func (m *BatchUpdateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchUpdateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchUpdateRequest proto.InternalMessageInfo
+
+func (m *BatchUpdateRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchUpdateRequest) GetToDos() []*ToDo {
+	if m != nil {
+		return m.ToDos
+	}
+	return nil
+}
+
+func (m *BatchUpdateRequest) GetBestEffort() bool {
+	if m != nil {
+		return m.BestEffort
+	}
+	return false
+}
+
+type BatchUpdateResponse struct {
+	Api                  string         `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Results              []*BatchResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *BatchUpdateResponse) Reset()         { *m = BatchUpdateResponse{} }
+func (m *BatchUpdateResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchUpdateResponse) ProtoMessage()    {}
+func (*BatchUpdateResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{43}
+}
+
+func (m *BatchUpdateResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchUpdateResponse.Unmarshal(m, b)
+}
+func (m *BatchUpdateResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchUpdateResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchUpdateResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchUpdateResponse.Merge(m, src)
+}
+func (m *BatchUpdateResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchUpdateResponse.Size(m)
+}
+func (m *BatchUpdateResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchUpdateResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchUpdateResponse proto.InternalMessageInfo
+
+func (m *BatchUpdateResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchUpdateResponse) GetResults() []*BatchResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type BatchDeleteRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Ids                  []int64  `protobuf:"varint,2,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	BestEffort           bool     `protobuf:"varint,3,opt,name=bestEffort,proto3" json:"bestEffort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchDeleteRequest) Reset()         { *m = BatchDeleteRequest{} }
+func (m *BatchDeleteRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteRequest) ProtoMessage()    {}
+func (*BatchDeleteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{44}
+}
+
+func (m *BatchDeleteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteRequest.Unmarshal(m, b)
+}
+func (m *BatchDeleteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchDeleteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteRequest.Merge(m, src)
+}
+func (m *BatchDeleteRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteRequest.Size(m)
+}
+func (m *BatchDeleteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteRequest proto.InternalMessageInfo
+
+func (m *BatchDeleteRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchDeleteRequest) GetIds() []int64 {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+func (m *BatchDeleteRequest) GetBestEffort() bool {
+	if m != nil {
+		return m.BestEffort
+	}
+	return false
+}
+
+type BatchDeleteResponse struct {
+	Api                  string         `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Results              []*BatchResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *BatchDeleteResponse) Reset()         { *m = BatchDeleteResponse{} }
+func (m *BatchDeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchDeleteResponse) ProtoMessage()    {}
+func (*BatchDeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{45}
+}
+
+func (m *BatchDeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchDeleteResponse.Unmarshal(m, b)
+}
+func (m *BatchDeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchDeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchDeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchDeleteResponse.Merge(m, src)
+}
+func (m *BatchDeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchDeleteResponse.Size(m)
+}
+func (m *BatchDeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchDeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchDeleteResponse proto.InternalMessageInfo
+
+func (m *BatchDeleteResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *BatchDeleteResponse) GetResults() []*BatchResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type ToDoEvent struct {
+	Sequence             int64                `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Type                 ToDoEvent_Type       `protobuf:"varint,2,opt,name=type,proto3,enum=v1.ToDoEvent_Type" json:"type,omitempty"`
+	ToDo                 *ToDo                `protobuf:"bytes,3,opt,name=toDo,proto3" json:"toDo,omitempty"`
+	Time                 *timestamp.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ToDoEvent) Reset()         { *m = ToDoEvent{} }
+func (m *ToDoEvent) String() string { return proto.CompactTextString(m) }
+func (*ToDoEvent) ProtoMessage()    {}
+func (*ToDoEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{46}
+}
+
+func (m *ToDoEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ToDoEvent.Unmarshal(m, b)
+}
+func (m *ToDoEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ToDoEvent.Marshal(b, m, deterministic)
+}
+func (m *ToDoEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ToDoEvent.Merge(m, src)
+}
+func (m *ToDoEvent) XXX_Size() int {
+	return xxx_messageInfo_ToDoEvent.Size(m)
+}
+func (m *ToDoEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_ToDoEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ToDoEvent proto.InternalMessageInfo
+
+func (m *ToDoEvent) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *ToDoEvent) GetType() ToDoEvent_Type {
+	if m != nil {
+		return m.Type
+	}
+	return ToDoEvent_UNKNOWN
+}
+
+func (m *ToDoEvent) GetToDo() *ToDo {
+	if m != nil {
+		return m.ToDo
+	}
+	return nil
+}
+
+func (m *ToDoEvent) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	SinceSequence        int64    `protobuf:"varint,2,opt,name=sinceSequence,proto3" json:"sinceSequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{47}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetSinceSequence() int64 {
+	if m != nil {
+		return m.SinceSequence
+	}
+	return 0
+}
+
+type WatchResponse struct {
+	Api                  string     `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Event                *ToDoEvent `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *WatchResponse) Reset()         { *m = WatchResponse{} }
+func (m *WatchResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchResponse) ProtoMessage()    {}
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{48}
+}
+
+func (m *WatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchResponse.Unmarshal(m, b)
+}
+func (m *WatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchResponse.Marshal(b, m, deterministic)
+}
+func (m *WatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchResponse.Merge(m, src)
+}
+func (m *WatchResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchResponse.Size(m)
+}
+func (m *WatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchResponse proto.InternalMessageInfo
+
+func (m *WatchResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *WatchResponse) GetEvent() *ToDoEvent {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+type Webhook struct {
+	Id                   int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url                  string               `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Events               []ToDoEvent_Type     `protobuf:"varint,3,rep,packed,name=events,proto3,enum=v1.ToDoEvent_Type" json:"events,omitempty"`
+	Secret               string               `protobuf:"bytes,4,opt,name=secret,proto3" json:"secret,omitempty"`
+	CreateTime           *timestamp.Timestamp `protobuf:"bytes,5,opt,name=createTime,proto3" json:"createTime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Webhook) Reset()         { *m = Webhook{} }
+func (m *Webhook) String() string { return proto.CompactTextString(m) }
+func (*Webhook) ProtoMessage()    {}
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{49}
+}
+
+func (m *Webhook) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Webhook.Unmarshal(m, b)
+}
+func (m *Webhook) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Webhook.Marshal(b, m, deterministic)
+}
+func (m *Webhook) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Webhook.Merge(m, src)
+}
+func (m *Webhook) XXX_Size() int {
+	return xxx_messageInfo_Webhook.Size(m)
+}
+func (m *Webhook) XXX_DiscardUnknown() {
+	xxx_messageInfo_Webhook.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Webhook proto.InternalMessageInfo
+
+func (m *Webhook) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Webhook) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Webhook) GetEvents() []ToDoEvent_Type {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *Webhook) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *Webhook) GetCreateTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreateTime
+	}
+	return nil
+}
+
+type WebhookDelivery struct {
+	Id                   int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId            int64                  `protobuf:"varint,2,opt,name=webhookId,proto3" json:"webhookId,omitempty"`
+	Event                *ToDoEvent             `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	Status               WebhookDelivery_Status `protobuf:"varint,4,opt,name=status,proto3,enum=v1.WebhookDelivery_Status" json:"status,omitempty"`
+	Attempts             int32                  `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	ResponseCode         int32                  `protobuf:"varint,6,opt,name=responseCode,proto3" json:"responseCode,omitempty"`
+	Error                string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	CreateTime           *timestamp.Timestamp   `protobuf:"bytes,8,opt,name=createTime,proto3" json:"createTime,omitempty"`
+	NextAttemptTime      *timestamp.Timestamp   `protobuf:"bytes,9,opt,name=nextAttemptTime,proto3" json:"nextAttemptTime,omitempty"`
+	DeliveryTime         *timestamp.Timestamp   `protobuf:"bytes,10,opt,name=deliveryTime,proto3" json:"deliveryTime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *WebhookDelivery) Reset()         { *m = WebhookDelivery{} }
+func (m *WebhookDelivery) String() string { return proto.CompactTextString(m) }
+func (*WebhookDelivery) ProtoMessage()    {}
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{50}
+}
+
+func (m *WebhookDelivery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WebhookDelivery.Unmarshal(m, b)
+}
+func (m *WebhookDelivery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WebhookDelivery.Marshal(b, m, deterministic)
+}
+func (m *WebhookDelivery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WebhookDelivery.Merge(m, src)
+}
+func (m *WebhookDelivery) XXX_Size() int {
+	return xxx_messageInfo_WebhookDelivery.Size(m)
+}
+func (m *WebhookDelivery) XXX_DiscardUnknown() {
+	xxx_messageInfo_WebhookDelivery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WebhookDelivery proto.InternalMessageInfo
+
+func (m *WebhookDelivery) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *WebhookDelivery) GetWebhookId() int64 {
+	if m != nil {
+		return m.WebhookId
+	}
+	return 0
+}
+
+func (m *WebhookDelivery) GetEvent() *ToDoEvent {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *WebhookDelivery) GetStatus() WebhookDelivery_Status {
+	if m != nil {
+		return m.Status
+	}
+	return WebhookDelivery_PENDING
+}
+
+func (m *WebhookDelivery) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *WebhookDelivery) GetResponseCode() int32 {
+	if m != nil {
+		return m.ResponseCode
+	}
+	return 0
+}
+
+func (m *WebhookDelivery) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *WebhookDelivery) GetCreateTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreateTime
+	}
+	return nil
+}
+
+func (m *WebhookDelivery) GetNextAttemptTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.NextAttemptTime
+	}
+	return nil
+}
+
+func (m *WebhookDelivery) GetDeliveryTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.DeliveryTime
+	}
+	return nil
+}
+
+type CreateWebhookRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Webhook              *Webhook `protobuf:"bytes,2,opt,name=webhook,proto3" json:"webhook,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateWebhookRequest) Reset()         { *m = CreateWebhookRequest{} }
+func (m *CreateWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateWebhookRequest) ProtoMessage()    {}
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{51}
+}
+
+func (m *CreateWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateWebhookRequest.Unmarshal(m, b)
+}
+func (m *CreateWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateWebhookRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateWebhookRequest.Merge(m, src)
+}
+func (m *CreateWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateWebhookRequest.Size(m)
+}
+func (m *CreateWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateWebhookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateWebhookRequest proto.InternalMessageInfo
+
+func (m *CreateWebhookRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *CreateWebhookRequest) GetWebhook() *Webhook {
+	if m != nil {
+		return m.Webhook
+	}
+	return nil
+}
+
+type CreateWebhookResponse struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Secret               string   `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateWebhookResponse) Reset()         { *m = CreateWebhookResponse{} }
+func (m *CreateWebhookResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateWebhookResponse) ProtoMessage()    {}
+func (*CreateWebhookResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{52}
+}
+
+func (m *CreateWebhookResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateWebhookResponse.Unmarshal(m, b)
+}
+func (m *CreateWebhookResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateWebhookResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateWebhookResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateWebhookResponse.Merge(m, src)
+}
+func (m *CreateWebhookResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateWebhookResponse.Size(m)
+}
+func (m *CreateWebhookResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateWebhookResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateWebhookResponse proto.InternalMessageInfo
+
+func (m *CreateWebhookResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *CreateWebhookResponse) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *CreateWebhookResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+type ListWebhooksRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListWebhooksRequest) Reset()         { *m = ListWebhooksRequest{} }
+func (m *ListWebhooksRequest) String() string { return proto.CompactTextString(m) }
+func (*ListWebhooksRequest) ProtoMessage()    {}
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{53}
+}
+
+func (m *ListWebhooksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhooksRequest.Unmarshal(m, b)
+}
+func (m *ListWebhooksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhooksRequest.Marshal(b, m, deterministic)
+}
+func (m *ListWebhooksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhooksRequest.Merge(m, src)
+}
+func (m *ListWebhooksRequest) XXX_Size() int {
+	return xxx_messageInfo_ListWebhooksRequest.Size(m)
+}
+func (m *ListWebhooksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhooksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhooksRequest proto.InternalMessageInfo
+
+func (m *ListWebhooksRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+type ListWebhooksResponse struct {
+	Api                  string     `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Webhooks             []*Webhook `protobuf:"bytes,2,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListWebhooksResponse) Reset()         { *m = ListWebhooksResponse{} }
+func (m *ListWebhooksResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWebhooksResponse) ProtoMessage()    {}
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{54}
+}
+
+func (m *ListWebhooksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhooksResponse.Unmarshal(m, b)
+}
+func (m *ListWebhooksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhooksResponse.Marshal(b, m, deterministic)
+}
+func (m *ListWebhooksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhooksResponse.Merge(m, src)
+}
+func (m *ListWebhooksResponse) XXX_Size() int {
+	return xxx_messageInfo_ListWebhooksResponse.Size(m)
+}
+func (m *ListWebhooksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhooksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhooksResponse proto.InternalMessageInfo
+
+func (m *ListWebhooksResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if m != nil {
+		return m.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteWebhookRequest) Reset()         { *m = DeleteWebhookRequest{} }
+func (m *DeleteWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteWebhookRequest) ProtoMessage()    {}
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{55}
+}
+
+func (m *DeleteWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWebhookRequest.Unmarshal(m, b)
+}
+func (m *DeleteWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWebhookRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWebhookRequest.Merge(m, src)
+}
+func (m *DeleteWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteWebhookRequest.Size(m)
+}
+func (m *DeleteWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWebhookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWebhookRequest proto.InternalMessageInfo
+
+func (m *DeleteWebhookRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *DeleteWebhookRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type DeleteWebhookResponse struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Deleted              int64    `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteWebhookResponse) Reset()         { *m = DeleteWebhookResponse{} }
+func (m *DeleteWebhookResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteWebhookResponse) ProtoMessage()    {}
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{56}
+}
+
+func (m *DeleteWebhookResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWebhookResponse.Unmarshal(m, b)
+}
+func (m *DeleteWebhookResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWebhookResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteWebhookResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWebhookResponse.Merge(m, src)
+}
+func (m *DeleteWebhookResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteWebhookResponse.Size(m)
+}
+func (m *DeleteWebhookResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWebhookResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWebhookResponse proto.InternalMessageInfo
+
+func (m *DeleteWebhookResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *DeleteWebhookResponse) GetDeleted() int64 {
+	if m != nil {
+		return m.Deleted
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesRequest struct {
+	Api                  string   `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	WebhookId            int64    `protobuf:"varint,2,opt,name=webhookId,proto3" json:"webhookId,omitempty"`
+	DeadOnly             bool     `protobuf:"varint,3,opt,name=deadOnly,proto3" json:"deadOnly,omitempty"`
+	Limit                int32    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListWebhookDeliveriesRequest) Reset()         { *m = ListWebhookDeliveriesRequest{} }
+func (m *ListWebhookDeliveriesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListWebhookDeliveriesRequest) ProtoMessage()    {}
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{57}
+}
+
+func (m *ListWebhookDeliveriesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhookDeliveriesRequest.Unmarshal(m, b)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhookDeliveriesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhookDeliveriesRequest.Merge(m, src)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListWebhookDeliveriesRequest.Size(m)
+}
+func (m *ListWebhookDeliveriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhookDeliveriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhookDeliveriesRequest proto.InternalMessageInfo
+
+func (m *ListWebhookDeliveriesRequest) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ListWebhookDeliveriesRequest) GetWebhookId() int64 {
+	if m != nil {
+		return m.WebhookId
+	}
+	return 0
+}
+
+func (m *ListWebhookDeliveriesRequest) GetDeadOnly() bool {
+	if m != nil {
+		return m.DeadOnly
+	}
+	return false
+}
+
+func (m *ListWebhookDeliveriesRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Api                  string             `protobuf:"bytes,1,opt,name=api,proto3" json:"api,omitempty"`
+	Deliveries           []*WebhookDelivery `protobuf:"bytes,2,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ListWebhookDeliveriesResponse) Reset()         { *m = ListWebhookDeliveriesResponse{} }
+func (m *ListWebhookDeliveriesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWebhookDeliveriesResponse) ProtoMessage()    {}
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af1b42e10a177658, []int{58}
+}
+
+func (m *ListWebhookDeliveriesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Unmarshal(m, b)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhookDeliveriesResponse.Merge(m, src)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListWebhookDeliveriesResponse.Size(m)
+}
+func (m *ListWebhookDeliveriesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhookDeliveriesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhookDeliveriesResponse proto.InternalMessageInfo
+
+func (m *ListWebhookDeliveriesResponse) GetApi() string {
+	if m != nil {
+		return m.Api
+	}
+	return ""
+}
+
+func (m *ListWebhookDeliveriesResponse) GetDeliveries() []*WebhookDelivery {
+	if m != nil {
+		return m.Deliveries
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("v1.DataFormat", DataFormat_name, DataFormat_value)
+	proto.RegisterEnum("v1.ToDo_ReminderStatus", ToDo_ReminderStatus_name, ToDo_ReminderStatus_value)
+	proto.RegisterEnum("v1.ImportResult_Outcome", ImportResult_Outcome_name, ImportResult_Outcome_value)
+	proto.RegisterEnum("v1.ToDoEvent_Type", ToDoEvent_Type_name, ToDoEvent_Type_value)
+	proto.RegisterEnum("v1.WebhookDelivery_Status", WebhookDelivery_Status_name, WebhookDelivery_Status_value)
+	proto.RegisterType((*ToDo)(nil), "v1.ToDo")
+	proto.RegisterType((*CreateRequest)(nil), "v1.CreateRequest")
+	proto.RegisterType((*CreateResponse)(nil), "v1.CreateResponse")
+	proto.RegisterType((*ReadRequest)(nil), "v1.ReadRequest")
+	proto.RegisterType((*ReadResponse)(nil), "v1.ReadResponse")
+	proto.RegisterType((*UpdateRequest)(nil), "v1.UpdateRequest")
+	proto.RegisterType((*UpdateResponse)(nil), "v1.UpdateResponse")
+	proto.RegisterType((*DeleteRequest)(nil), "v1.DeleteRequest")
+	proto.RegisterType((*DeleteResponse)(nil), "v1.DeleteResponse")
+	proto.RegisterType((*ReadAllRequest)(nil), "v1.ReadAllRequest")
+	proto.RegisterType((*ReadAllResponse)(nil), "v1.ReadAllResponse")
+	proto.RegisterType((*StreamAllRequest)(nil), "v1.StreamAllRequest")
+	proto.RegisterType((*StreamAllResponse)(nil), "v1.StreamAllResponse")
+	proto.RegisterType((*UndeleteRequest)(nil), "v1.UndeleteRequest")
+	proto.RegisterType((*UndeleteResponse)(nil), "v1.UndeleteResponse")
+	proto.RegisterType((*PurgeRequest)(nil), "v1.PurgeRequest")
+	proto.RegisterType((*PurgeResponse)(nil), "v1.PurgeResponse")
+	proto.RegisterType((*Revision)(nil), "v1.Revision")
+	proto.RegisterType((*ListRevisionsRequest)(nil), "v1.ListRevisionsRequest")
+	proto.RegisterType((*ListRevisionsResponse)(nil), "v1.ListRevisionsResponse")
+	proto.RegisterType((*ReadAtRequest)(nil), "v1.ReadAtRequest")
+	proto.RegisterType((*ReadAtResponse)(nil), "v1.ReadAtResponse")
+	proto.RegisterType((*RevertRequest)(nil), "v1.RevertRequest")
+	proto.RegisterType((*RevertResponse)(nil), "v1.RevertResponse")
+	proto.RegisterType((*CompleteRequest)(nil), "v1.CompleteRequest")
+	proto.RegisterType((*CompleteResponse)(nil), "v1.CompleteResponse")
+	proto.RegisterType((*ListOccurrencesRequest)(nil), "v1.ListOccurrencesRequest")
+	proto.RegisterType((*ListOccurrencesResponse)(nil), "v1.ListOccurrencesResponse")
+	proto.RegisterType((*ImportICSRequest)(nil), "v1.ImportICSRequest")
+	proto.RegisterType((*ImportICSResponse)(nil), "v1.ImportICSResponse")
+	proto.RegisterType((*ExportICSRequest)(nil), "v1.ExportICSRequest")
+	proto.RegisterType((*ExportICSResponse)(nil), "v1.ExportICSResponse")
+	proto.RegisterType((*GetCalendarTokenRequest)(nil), "v1.GetCalendarTokenRequest")
+	proto.RegisterType((*GetCalendarTokenResponse)(nil), "v1.GetCalendarTokenResponse")
+	proto.RegisterType((*ExportRequest)(nil), "v1.ExportRequest")
+	proto.RegisterType((*ExportResponse)(nil), "v1.ExportResponse")
+	proto.RegisterType((*ImportRequest)(nil), "v1.ImportRequest")
+	proto.RegisterType((*ImportResult)(nil), "v1.ImportResult")
+	proto.RegisterType((*ImportResponse)(nil), "v1.ImportResponse")
+	proto.RegisterType((*BatchResult)(nil), "v1.BatchResult")
+	proto.RegisterType((*BatchCreateRequest)(nil), "v1.BatchCreateRequest")
+	proto.RegisterType((*BatchCreateResponse)(nil), "v1.BatchCreateResponse")
+	proto.RegisterType((*BatchUpdateRequest)(nil), "v1.BatchUpdateRequest")
+	proto.RegisterType((*BatchUpdateResponse)(nil), "v1.BatchUpdateResponse")
+	proto.RegisterType((*BatchDeleteRequest)(nil), "v1.BatchDeleteRequest")
+	proto.RegisterType((*BatchDeleteResponse)(nil), "v1.BatchDeleteResponse")
+	proto.RegisterType((*ToDoEvent)(nil), "v1.ToDoEvent")
+	proto.RegisterType((*WatchRequest)(nil), "v1.WatchRequest")
+	proto.RegisterType((*WatchResponse)(nil), "v1.WatchResponse")
+	proto.RegisterType((*Webhook)(nil), "v1.Webhook")
+	proto.RegisterType((*WebhookDelivery)(nil), "v1.WebhookDelivery")
+	proto.RegisterType((*CreateWebhookRequest)(nil), "v1.CreateWebhookRequest")
+	proto.RegisterType((*CreateWebhookResponse)(nil), "v1.CreateWebhookResponse")
+	proto.RegisterType((*ListWebhooksRequest)(nil), "v1.ListWebhooksRequest")
+	proto.RegisterType((*ListWebhooksResponse)(nil), "v1.ListWebhooksResponse")
+	proto.RegisterType((*DeleteWebhookRequest)(nil), "v1.DeleteWebhookRequest")
+	proto.RegisterType((*DeleteWebhookResponse)(nil), "v1.DeleteWebhookResponse")
+	proto.RegisterType((*ListWebhookDeliveriesRequest)(nil), "v1.ListWebhookDeliveriesRequest")
+	proto.RegisterType((*ListWebhookDeliveriesResponse)(nil), "v1.ListWebhookDeliveriesResponse")
+}
+
+func init() { proto.RegisterFile("todo_service.proto", fileDescriptor_af1b42e10a177658) }
+
+var fileDescriptor_af1b42e10a177658 = []byte{
+	// 2835 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x5a, 0xcd, 0x73, 0x1b, 0xc7,
+	0xb1, 0xd7, 0xe2, 0x1b, 0x4d, 0x02, 0x04, 0x47, 0x14, 0x09, 0xad, 0x69, 0x7b, 0xbd, 0xcf, 0xcf,
+	0xd6, 0xe3, 0x33, 0x01, 0x0a, 0x72, 0xa9, 0x6c, 0x3e, 0x97, 0xfd, 0x28, 0x02, 0x74, 0x90, 0xd0,
+	0x14, 0xb5, 0x24, 0x25, 0x97, 0xca, 0x89, 0xb3, 0xda, 0x1d, 0x82, 0x6b, 0x03, 0x3b, 0xc8, 0xee,
+	0x80, 0xa2, 0x92, 0x72, 0x55, 0xca, 0x27, 0xe7, 0x90, 0x4b, 0x72, 0x49, 0xe5, 0x2f, 0x48, 0x95,
+	0x0f, 0xa9, 0x54, 0x6e, 0x39, 0xe4, 0x9e, 0x73, 0xee, 0xa9, 0x1c, 0x72, 0xca, 0x39, 0x7f, 0x40,
+	0x6a, 0xbe, 0x80, 0xdd, 0xc5, 0x27, 0x15, 0x9f, 0x88, 0xe9, 0xee, 0xf9, 0x75, 0x4f, 0xcf, 0x4c,
+	0x4f, 0x77, 0x2f, 0x01, 0x51, 0xe2, 0x92, 0xcf, 0x43, 0x1c, 0x5c, 0x7a, 0x0e, 0xae, 0xf5, 0x03,
+	0x42, 0x09, 0x4a, 0x5d, 0xde, 0xd5, 0x5f, 0xef, 0x10, 0xd2, 0xe9, 0xe2, 0x3a, 0xa7, 0x3c, 0x1b,
+	0x9c, 0xd7, 0xa9, 0xd7, 0xc3, 0x21, 0xb5, 0x7b, 0x7d, 0x21, 0xa4, 0x6f, 0x4a, 0x01, 0xbb, 0xef,
+	0xd5, 0x6d, 0xdf, 0x27, 0xd4, 0xa6, 0x1e, 0xf1, 0x43, 0xc9, 0xdd, 0x90, 0xdc, 0xa0, 0xef, 0xd4,
+	0x43, 0x6a, 0xd3, 0x81, 0x62, 0xbc, 0xc3, 0xff, 0x38, 0xdb, 0x1d, 0xec, 0x6f, 0x87, 0xcf, 0xed,
+	0x4e, 0x07, 0x07, 0x75, 0xd2, 0xe7, 0x53, 0xc7, 0x61, 0xcc, 0xbf, 0xa4, 0x21, 0x73, 0x4a, 0x9a,
+	0x04, 0x95, 0x21, 0xe5, 0xb9, 0x55, 0xcd, 0xd0, 0xee, 0xa4, 0xad, 0x94, 0xe7, 0xa2, 0x35, 0xc8,
+	0x52, 0x8f, 0x76, 0x71, 0x35, 0x65, 0x68, 0x77, 0x8a, 0x96, 0x18, 0x20, 0x03, 0x96, 0x5c, 0x1c,
+	0x3a, 0x81, 0xc7, 0x01, 0xab, 0x69, 0xce, 0x8b, 0x92, 0xd0, 0x7d, 0x28, 0x04, 0xb8, 0xe7, 0xf9,
+	0x2e, 0x0e, 0xaa, 0x19, 0x43, 0xbb, 0xb3, 0xd4, 0xd0, 0x6b, 0xc2, 0xd4, 0x9a, 0x5a, 0x69, 0xed,
+	0x54, 0xad, 0xd4, 0x1a, 0xca, 0xa2, 0x5d, 0x00, 0x17, 0x77, 0x31, 0xc5, 0x8c, 0x59, 0xcd, 0xce,
+	0x9d, 0x19, 0x91, 0x46, 0x1f, 0x41, 0x59, 0xe1, 0x9c, 0x70, 0x57, 0x54, 0x73, 0x86, 0x76, 0xa7,
+	0xdc, 0xd8, 0xa8, 0x5d, 0xde, 0xad, 0xb1, 0xd5, 0xd5, 0xac, 0x18, 0xdb, 0x4a, 0x88, 0xa3, 0x23,
+	0x58, 0x53, 0x94, 0x26, 0xee, 0x7a, 0x97, 0x38, 0x78, 0xc1, 0xcd, 0xc8, 0xcf, 0x35, 0x63, 0xe2,
+	0x3c, 0xf4, 0x1a, 0x40, 0x80, 0x9d, 0x41, 0x10, 0x60, 0xdf, 0xc1, 0xd5, 0x02, 0xf7, 0x52, 0x84,
+	0x82, 0x74, 0x28, 0xb0, 0xdd, 0x7e, 0x4a, 0x7c, 0x5c, 0x2d, 0x72, 0xee, 0x70, 0x6c, 0xbe, 0x07,
+	0xe5, 0xb8, 0xb5, 0x68, 0x09, 0xf2, 0xc7, 0xad, 0xa3, 0x66, 0xfb, 0xe8, 0xe3, 0xca, 0x0d, 0x54,
+	0x82, 0x62, 0xb3, 0x75, 0xd8, 0x7e, 0xdc, 0xb2, 0x5a, 0xcd, 0x8a, 0x86, 0x00, 0x72, 0x07, 0x7b,
+	0xed, 0xc3, 0x56, 0xb3, 0x92, 0x32, 0x7f, 0x08, 0xa5, 0xfd, 0x00, 0xdb, 0x14, 0x5b, 0xf8, 0x27,
+	0x03, 0x1c, 0x52, 0x54, 0x81, 0xb4, 0xdd, 0xf7, 0xf8, 0xa6, 0x16, 0x2d, 0xf6, 0x13, 0x6d, 0x42,
+	0x86, 0x92, 0x26, 0xe1, 0x9b, 0xba, 0xd4, 0x28, 0x28, 0xff, 0x58, 0x9c, 0x8a, 0x36, 0xa1, 0x18,
+	0x88, 0xa9, 0x6d, 0x57, 0xee, 0xed, 0x88, 0x60, 0x36, 0xa0, 0xac, 0xe0, 0xc3, 0x3e, 0xf1, 0x43,
+	0x3c, 0x01, 0x5f, 0x9c, 0xa2, 0x94, 0x3a, 0x45, 0xe6, 0x23, 0x58, 0xb2, 0xb0, 0xed, 0x4e, 0x37,
+	0x28, 0x31, 0x81, 0x1d, 0xb0, 0xf0, 0x82, 0x3c, 0x6f, 0xf2, 0xcd, 0x15, 0x46, 0x14, 0xac, 0x28,
+	0xc9, 0xfc, 0x10, 0x96, 0x05, 0xe4, 0x54, 0x23, 0x66, 0x2e, 0xd2, 0xfc, 0x08, 0x4a, 0x67, 0x7d,
+	0xf7, 0xe5, 0xbd, 0x64, 0x7e, 0x00, 0x65, 0x05, 0x30, 0xd5, 0x84, 0x2a, 0xe4, 0x07, 0x5c, 0x46,
+	0xad, 0x4d, 0x0d, 0xcd, 0xbb, 0x50, 0x12, 0x2b, 0x59, 0xd8, 0x27, 0x4c, 0xa1, 0x9a, 0x32, 0x4b,
+	0xa1, 0x2b, 0x7d, 0x26, 0x15, 0xca, 0xa1, 0xd9, 0x64, 0xe7, 0xc9, 0x76, 0xf7, 0xba, 0xdd, 0xe9,
+	0x1a, 0x13, 0x5e, 0x4f, 0x8d, 0x7b, 0x7d, 0x1f, 0x56, 0x86, 0x28, 0x53, 0x8d, 0x78, 0x0d, 0xb2,
+	0xcc, 0x43, 0x61, 0x35, 0x65, 0xa4, 0x63, 0x8e, 0x13, 0x64, 0xf3, 0x00, 0x2a, 0x27, 0x34, 0xc0,
+	0x76, 0xef, 0x3f, 0x36, 0x66, 0x35, 0x82, 0xf3, 0x92, 0xe7, 0xe0, 0x1e, 0xac, 0x9c, 0xf9, 0xee,
+	0x35, 0xb7, 0xe2, 0x01, 0x54, 0x46, 0x93, 0x66, 0x28, 0x2e, 0x0e, 0xfc, 0xf8, 0x76, 0x8c, 0x08,
+	0xe6, 0x0e, 0x2c, 0x1f, 0x0f, 0x82, 0xce, 0x35, 0xb4, 0xbe, 0x0f, 0x25, 0x39, 0x63, 0xaa, 0xca,
+	0x75, 0xc8, 0xf5, 0x99, 0x88, 0x9a, 0x26, 0x47, 0xe6, 0xbf, 0x34, 0x28, 0x58, 0xf8, 0xd2, 0x0b,
+	0x59, 0x6c, 0xd6, 0x59, 0x6c, 0x16, 0xbf, 0x65, 0xa4, 0x1f, 0x8e, 0xd1, 0x16, 0xe4, 0x6c, 0x87,
+	0x07, 0xf5, 0x14, 0x8f, 0x9d, 0x48, 0xb9, 0xab, 0x75, 0x89, 0x7d, 0x5a, 0x3b, 0x7d, 0xd1, 0xc7,
+	0x96, 0x94, 0x40, 0x26, 0xe4, 0x49, 0xd7, 0x65, 0xcc, 0x6a, 0x3a, 0xe1, 0x5b, 0xc5, 0x60, 0x32,
+	0x3e, 0x7e, 0xce, 0x65, 0x32, 0x49, 0x19, 0xc9, 0x60, 0x7e, 0xea, 0x07, 0x9e, 0xef, 0x78, 0x7d,
+	0xbb, 0xcb, 0x43, 0x7e, 0xd1, 0x1a, 0x11, 0xd8, 0x8b, 0xe0, 0x5c, 0xd8, 0x7e, 0x47, 0xbc, 0x08,
+	0xb9, 0xf9, 0x2f, 0xc2, 0x48, 0xda, 0x7c, 0x0f, 0xd6, 0x0e, 0xbd, 0x90, 0xaa, 0x95, 0x87, 0x8b,
+	0xfb, 0xfa, 0x0c, 0x6e, 0x25, 0x66, 0x4e, 0xf5, 0xf9, 0x16, 0x0b, 0x97, 0x52, 0x4c, 0x1e, 0xf9,
+	0x65, 0xb6, 0x48, 0x35, 0xd7, 0x1a, 0xb1, 0x4d, 0x1b, 0x4a, 0xfc, 0xfe, 0xd0, 0xc5, 0x43, 0x61,
+	0x0d, 0x32, 0xec, 0x51, 0xa8, 0xa6, 0xe7, 0xae, 0x9c, 0xcb, 0x99, 0x9f, 0xc9, 0x8b, 0x4e, 0x5f,
+	0xf6, 0x4a, 0xc4, 0xce, 0x47, 0x3a, 0x7e, 0x3e, 0xcc, 0x4f, 0xd8, 0x02, 0x2e, 0x71, 0x70, 0x8d,
+	0x05, 0xcc, 0x82, 0xfb, 0x7f, 0x28, 0x2b, 0xb8, 0x97, 0xbf, 0xbf, 0xfb, 0xa4, 0xd7, 0xbf, 0xf6,
+	0xfd, 0x1d, 0x4d, 0x7a, 0x49, 0xc5, 0x7f, 0xd6, 0x60, 0x9d, 0x1d, 0x91, 0x87, 0x8e, 0x7a, 0xcf,
+	0x17, 0x3f, 0x5e, 0x2c, 0xad, 0x72, 0xc8, 0xc0, 0xa7, 0xdc, 0x21, 0x59, 0x4b, 0x0c, 0x12, 0xf9,
+	0x42, 0x66, 0x2c, 0x5f, 0xd8, 0x81, 0x6c, 0x48, 0xed, 0x80, 0x2e, 0x90, 0x17, 0x09, 0xc1, 0x58,
+	0x86, 0x91, 0x4b, 0x64, 0x18, 0x1e, 0x6c, 0x8c, 0xd9, 0x3f, 0xd5, 0x17, 0x1f, 0xc0, 0x12, 0x19,
+	0x09, 0xca, 0x63, 0x3e, 0xcb, 0x80, 0xa8, 0xb8, 0xf9, 0x63, 0xa8, 0xb4, 0x7b, 0x7d, 0x12, 0xd0,
+	0xf6, 0xfe, 0xc9, 0x74, 0x27, 0xe9, 0x50, 0x70, 0xec, 0x2e, 0xf6, 0x5d, 0x3b, 0x90, 0xe9, 0xe6,
+	0x70, 0xcc, 0x5c, 0xf3, 0x0c, 0x87, 0xb4, 0x75, 0x7e, 0x4e, 0x02, 0x2a, 0xf3, 0x81, 0x08, 0xc5,
+	0x3c, 0x86, 0xd5, 0x88, 0x86, 0xa9, 0xcb, 0xf8, 0x1f, 0xc8, 0x07, 0x38, 0x1c, 0x74, 0xa9, 0x5a,
+	0xc2, 0x0a, 0xdb, 0xd5, 0x07, 0x36, 0x75, 0x2e, 0x2c, 0x4e, 0xb7, 0x14, 0xdf, 0xec, 0x42, 0xa5,
+	0x75, 0x35, 0xd7, 0x66, 0x04, 0x99, 0x41, 0x88, 0x95, 0xbd, 0xfc, 0x37, 0xcf, 0x99, 0xc9, 0x97,
+	0x58, 0xe5, 0xc5, 0x62, 0xc0, 0xa2, 0x9c, 0x43, 0x7a, 0x7d, 0xe2, 0x63, 0x9f, 0xca, 0xbd, 0x1d,
+	0x11, 0xcc, 0x3d, 0x58, 0x6d, 0x5d, 0xcd, 0xb7, 0x7f, 0x86, 0x8b, 0xcc, 0xff, 0x85, 0x8d, 0x8f,
+	0x31, 0xdd, 0x97, 0xc3, 0x53, 0xa6, 0x74, 0xaa, 0xdd, 0xe6, 0x63, 0xa8, 0x8e, 0x0b, 0x4f, 0x55,
+	0xbb, 0xf0, 0x2a, 0xcd, 0x5f, 0xa6, 0xa0, 0x24, 0x16, 0x32, 0xdd, 0x67, 0x6f, 0x41, 0xee, 0x9c,
+	0x04, 0x3d, 0x9b, 0xca, 0x37, 0xa6, 0xcc, 0xf6, 0xa0, 0x69, 0x53, 0xfb, 0x80, 0x53, 0x2d, 0xc9,
+	0x9d, 0x9f, 0x04, 0xa2, 0x0f, 0x61, 0x59, 0x25, 0xde, 0x07, 0x01, 0xe9, 0x2d, 0x50, 0x69, 0xc4,
+	0xe4, 0xd9, 0xdb, 0xa2, 0xc6, 0xa7, 0x64, 0x91, 0x6a, 0x63, 0x24, 0x8d, 0xde, 0x84, 0x12, 0x2f,
+	0x86, 0xf6, 0x89, 0x4f, 0x6d, 0xcf, 0x0f, 0xe5, 0xfd, 0x8a, 0x13, 0xcd, 0xfb, 0x50, 0x56, 0xee,
+	0x98, 0xe5, 0x5d, 0xd7, 0xa6, 0x36, 0xf7, 0xc6, 0xb2, 0xc5, 0x7f, 0x9b, 0x03, 0x28, 0xb5, 0x7b,
+	0xdf, 0x8d, 0x1b, 0xd7, 0x21, 0xe7, 0x06, 0x2f, 0xac, 0x81, 0x2f, 0x3d, 0x28, 0x47, 0x43, 0xb5,
+	0x99, 0x88, 0xda, 0x7f, 0x6a, 0xb0, 0xac, 0xf4, 0xb2, 0x6b, 0xc0, 0x26, 0x07, 0xd8, 0x21, 0x81,
+	0xa8, 0x09, 0xb3, 0x96, 0x1c, 0xb1, 0xfb, 0x88, 0xaf, 0x28, 0x0e, 0x7c, 0xbb, 0xdb, 0x76, 0xe5,
+	0xb9, 0x88, 0x50, 0x64, 0xc0, 0x4b, 0x0f, 0x03, 0x5e, 0x03, 0xf2, 0x64, 0x40, 0x1d, 0xd2, 0x13,
+	0x71, 0xad, 0xdc, 0xa8, 0x32, 0x6b, 0xa3, 0xaa, 0x6a, 0x0f, 0x05, 0xdf, 0x52, 0x82, 0x2c, 0x17,
+	0x11, 0x25, 0xad, 0xdc, 0x19, 0xa4, 0x76, 0x26, 0xe8, 0x3b, 0x35, 0x59, 0xc2, 0x49, 0x09, 0xb3,
+	0x01, 0x79, 0x39, 0x9f, 0xd5, 0x49, 0xfb, 0x56, 0x6b, 0xef, 0xb4, 0xd5, 0x94, 0x75, 0xd2, 0xd9,
+	0xf1, 0x61, 0x7b, 0x7f, 0xef, 0xb4, 0x55, 0xd1, 0x18, 0xaf, 0x7d, 0xf4, 0x78, 0xef, 0xb0, 0xcd,
+	0x0a, 0xa5, 0x3f, 0x69, 0x50, 0x6e, 0xf7, 0xe6, 0x6c, 0xce, 0xc8, 0x7b, 0xa9, 0x98, 0xf7, 0xaa,
+	0x90, 0x77, 0x78, 0x19, 0xe4, 0xca, 0x18, 0xae, 0x86, 0xcc, 0x35, 0xee, 0xa0, 0xdf, 0xf5, 0x1c,
+	0x9b, 0xe2, 0x90, 0xaf, 0x36, 0x6b, 0x45, 0x28, 0x6c, 0xa6, 0xe7, 0x5f, 0xda, 0x5d, 0xcf, 0xe5,
+	0xeb, 0xca, 0x5a, 0x6a, 0x88, 0xb6, 0x46, 0xd1, 0x29, 0xc7, 0xa3, 0x53, 0x25, 0xe9, 0xa4, 0x51,
+	0x78, 0x6a, 0xc3, 0x52, 0x24, 0x6c, 0x8d, 0xd5, 0xed, 0x23, 0xdf, 0xa5, 0xe6, 0xfa, 0xee, 0x1c,
+	0x10, 0x87, 0x9a, 0x57, 0x35, 0xce, 0xc9, 0xeb, 0xe7, 0xc6, 0x68, 0x0b, 0x6e, 0xc6, 0xf4, 0x7c,
+	0x17, 0x51, 0x5a, 0xd9, 0x3e, 0xaf, 0x96, 0xfb, 0xae, 0x6c, 0x9f, 0x5b, 0xf2, 0x5d, 0xc3, 0xf6,
+	0x4f, 0xa5, 0xed, 0xf3, 0x0a, 0xc1, 0x0a, 0xa4, 0x3d, 0x57, 0xc0, 0xa5, 0x2d, 0xf6, 0x73, 0x61,
+	0x6b, 0xe7, 0xd6, 0x8b, 0xd7, 0xb0, 0xf6, 0xeb, 0x14, 0x14, 0x87, 0x85, 0x00, 0x7b, 0x88, 0x42,
+	0x66, 0x30, 0x4b, 0x54, 0x64, 0x0d, 0xa1, 0xc6, 0xe8, 0x2d, 0xc8, 0xd0, 0x17, 0x7d, 0x3c, 0xa3,
+	0x82, 0xe0, 0xfc, 0x61, 0x7e, 0x95, 0x9e, 0x98, 0x85, 0xaa, 0xbc, 0x37, 0xb3, 0x60, 0xde, 0xeb,
+	0x40, 0x86, 0x61, 0xb3, 0x2b, 0x7e, 0x76, 0xf4, 0x83, 0xa3, 0x87, 0x4f, 0x8e, 0x2a, 0x37, 0xa2,
+	0xb1, 0x80, 0x5f, 0xfe, 0xb3, 0xe3, 0x26, 0x1f, 0xa4, 0xd8, 0xa0, 0xd9, 0x3a, 0x6c, 0xb1, 0x41,
+	0x9a, 0x45, 0x89, 0xb3, 0x23, 0x35, 0xcc, 0xb0, 0x6e, 0xca, 0xf1, 0x99, 0xf5, 0x71, 0xab, 0x59,
+	0xc9, 0xa2, 0x65, 0x28, 0x58, 0xad, 0x4f, 0xda, 0x47, 0xcd, 0x96, 0x55, 0xc9, 0x99, 0x07, 0xb0,
+	0xfc, 0x44, 0x38, 0x67, 0xda, 0x66, 0xbd, 0x09, 0xa5, 0xd0, 0xf3, 0x1d, 0x7c, 0xa2, 0xbc, 0x23,
+	0x92, 0xbe, 0x38, 0xd1, 0x3c, 0x80, 0x92, 0xc4, 0x99, 0xba, 0x35, 0xff, 0x05, 0x59, 0xcc, 0x3c,
+	0x26, 0x2f, 0x70, 0x29, 0xe6, 0x46, 0x4b, 0xf0, 0xcc, 0x6f, 0x35, 0xc8, 0x3f, 0xc1, 0xcf, 0x2e,
+	0x08, 0xf9, 0x72, 0x2c, 0x04, 0x54, 0x20, 0x3d, 0x08, 0xba, 0x32, 0x36, 0xb3, 0x9f, 0x2c, 0x28,
+	0xf0, 0x69, 0x61, 0x35, 0x6d, 0xa4, 0xa7, 0x15, 0x77, 0x42, 0x82, 0xc5, 0xbd, 0x10, 0x3b, 0x01,
+	0x56, 0xb9, 0x8a, 0x1c, 0xf1, 0x72, 0x8c, 0xdf, 0xdf, 0x45, 0x1b, 0x74, 0x23, 0x69, 0xf3, 0xef,
+	0x69, 0x58, 0x91, 0xd6, 0xaa, 0x3e, 0xd9, 0x98, 0xd5, 0x9b, 0x50, 0x7c, 0x2e, 0x44, 0xda, 0xc3,
+	0xa2, 0x79, 0x48, 0x18, 0x39, 0x25, 0x3d, 0xdd, 0x29, 0xa8, 0x31, 0x8c, 0x7d, 0xe2, 0xa9, 0xd1,
+	0x99, 0x54, 0x42, 0x6f, 0x22, 0x06, 0xb2, 0xf3, 0x6c, 0x53, 0x8a, 0x7b, 0x7d, 0x1a, 0xca, 0xa8,
+	0x3c, 0x1c, 0x23, 0x93, 0x65, 0x19, 0x62, 0x9f, 0xf6, 0x89, 0x2b, 0x12, 0xe9, 0xac, 0x15, 0xa3,
+	0xb1, 0x6c, 0x08, 0x07, 0x01, 0x09, 0x78, 0xaf, 0xb0, 0x68, 0x89, 0x41, 0xc2, 0x59, 0x85, 0xeb,
+	0x38, 0x0b, 0x35, 0x61, 0xc5, 0xc7, 0x57, 0x74, 0x4f, 0x58, 0xc1, 0x01, 0x8a, 0x73, 0x01, 0x92,
+	0x53, 0x58, 0x86, 0xe4, 0x46, 0x5b, 0x99, 0x30, 0x3f, 0x43, 0x8a, 0xca, 0x9b, 0x35, 0xc8, 0x2d,
+	0xd0, 0x7e, 0x2c, 0x40, 0xa6, 0xd9, 0xda, 0x63, 0x6f, 0xea, 0x43, 0x58, 0x13, 0xe1, 0x5d, 0xfa,
+	0x7b, 0xfa, 0x45, 0xf9, 0x6f, 0xc8, 0xcb, 0x7d, 0x95, 0x27, 0x7c, 0x29, 0xb2, 0x4d, 0x96, 0xe2,
+	0x99, 0x8f, 0xe0, 0x56, 0x02, 0x70, 0xd1, 0xae, 0x63, 0xe4, 0x08, 0xa7, 0xa3, 0x47, 0xd8, 0x7c,
+	0x1b, 0x6e, 0xb2, 0xc2, 0x47, 0x02, 0x4e, 0xaf, 0xda, 0xcc, 0x47, 0xb0, 0x16, 0x17, 0x9c, 0xaa,
+	0xfa, 0x6d, 0x28, 0x48, 0x83, 0x55, 0x20, 0x8d, 0xad, 0x66, 0xc8, 0x64, 0x1d, 0x09, 0x11, 0x94,
+	0xe7, 0xfa, 0x27, 0x59, 0xb3, 0xee, 0xc3, 0xad, 0xc4, 0xcc, 0x97, 0xe8, 0x02, 0xfe, 0x5c, 0x83,
+	0xcd, 0xc8, 0x92, 0xe4, 0x6d, 0xf0, 0x66, 0x95, 0xae, 0xb3, 0x2f, 0xa4, 0x0e, 0x05, 0x17, 0xdb,
+	0xee, 0x43, 0xbf, 0xfb, 0x42, 0xbe, 0x43, 0xc3, 0x31, 0xbb, 0x13, 0x5d, 0xaf, 0xe7, 0x51, 0x99,
+	0x03, 0x89, 0x81, 0x79, 0x0e, 0xaf, 0x4e, 0xb1, 0x60, 0xea, 0x7a, 0xee, 0xf1, 0x8f, 0x02, 0x52,
+	0x4e, 0xfa, 0xf7, 0xe6, 0x84, 0x4b, 0x6d, 0x45, 0xc4, 0xb6, 0x0c, 0x80, 0x51, 0x32, 0x8c, 0x8a,
+	0x90, 0xfd, 0xfe, 0xc9, 0xc3, 0xa3, 0xc3, 0xca, 0x0d, 0x94, 0x87, 0xf4, 0xfe, 0xc9, 0xe3, 0x8a,
+	0xd6, 0xf8, 0x5b, 0x19, 0x96, 0x58, 0xf0, 0x38, 0x11, 0x1f, 0x65, 0x50, 0x13, 0x72, 0xe2, 0xa8,
+	0xa1, 0x55, 0x06, 0x1e, 0x4b, 0x87, 0x74, 0x14, 0x25, 0x09, 0x4b, 0xcd, 0x9b, 0x5f, 0xff, 0xf5,
+	0x1f, 0xbf, 0x4e, 0x95, 0xcc, 0x42, 0xfd, 0xf2, 0x6e, 0x9d, 0x12, 0x97, 0xec, 0x6a, 0x5b, 0xa8,
+	0x03, 0x39, 0x91, 0x24, 0x08, 0x94, 0x58, 0x62, 0xa2, 0xa3, 0x28, 0x49, 0xa2, 0xdc, 0xe7, 0x28,
+	0x3b, 0x3a, 0x52, 0x28, 0xf5, 0x9f, 0xb1, 0x07, 0xb0, 0xe6, 0xb9, 0x5f, 0xed, 0x6a, 0x5b, 0x4f,
+	0x37, 0x1a, 0x93, 0x19, 0xe8, 0x00, 0x72, 0xe2, 0x40, 0x08, 0x45, 0xb1, 0x2c, 0x42, 0x47, 0x51,
+	0x92, 0x54, 0x74, 0x8b, 0x2b, 0x5a, 0xd9, 0x2a, 0x8d, 0xf0, 0x3c, 0xf7, 0x2b, 0xf4, 0x3d, 0xc8,
+	0xcb, 0x9e, 0x2e, 0x42, 0xa2, 0x6f, 0x15, 0x6d, 0x13, 0xeb, 0x37, 0x63, 0x34, 0x09, 0xb5, 0xc6,
+	0xa1, 0xca, 0x68, 0x79, 0x08, 0x65, 0x77, 0xbb, 0xe8, 0x53, 0x28, 0xa8, 0xb6, 0x28, 0xe2, 0xd3,
+	0x12, 0x9d, 0x55, 0x7d, 0x2d, 0x4e, 0x94, 0x60, 0x6f, 0x70, 0xb0, 0x57, 0xcc, 0xf5, 0x98, 0x5d,
+	0xbb, 0xaa, 0x55, 0xca, 0xd6, 0x7a, 0x08, 0x59, 0xde, 0xfa, 0x44, 0x3c, 0x23, 0x8e, 0xf6, 0x4d,
+	0xf5, 0xd5, 0x08, 0x45, 0x02, 0xbe, 0xc6, 0x01, 0xab, 0xe6, 0xcd, 0x38, 0x20, 0xef, 0x85, 0x32,
+	0x34, 0x0c, 0xa5, 0x58, 0x73, 0x0f, 0xf1, 0x62, 0x64, 0x52, 0xa7, 0x50, 0xbf, 0x3d, 0x81, 0x23,
+	0xb5, 0xbc, 0xce, 0xb5, 0xdc, 0x46, 0x1b, 0x31, 0x2d, 0xf5, 0x61, 0xb3, 0x0f, 0x7d, 0x02, 0x39,
+	0xee, 0x37, 0x2a, 0x36, 0x28, 0xd6, 0xf8, 0xd3, 0x51, 0x94, 0x24, 0x11, 0x37, 0x39, 0xe2, 0x3a,
+	0x5a, 0x8b, 0xdb, 0x1d, 0x08, 0x90, 0x63, 0xc8, 0x89, 0x5e, 0x99, 0x82, 0x8b, 0xb4, 0xe1, 0x74,
+	0x14, 0x25, 0xc5, 0x0d, 0x34, 0xc7, 0xe0, 0x98, 0x14, 0xf3, 0xc3, 0xa7, 0x50, 0x50, 0x6d, 0x30,
+	0xb1, 0x5f, 0x89, 0x4e, 0x9a, 0xbe, 0x16, 0x27, 0xce, 0xde, 0x2f, 0x47, 0xca, 0x31, 0xe4, 0x6f,
+	0x34, 0x58, 0x49, 0x34, 0x97, 0x90, 0xae, 0x5c, 0x39, 0xde, 0x31, 0xd3, 0x5f, 0x99, 0xc8, 0x93,
+	0xfa, 0x3e, 0xe0, 0xfa, 0xee, 0xa3, 0xdb, 0x71, 0x47, 0x47, 0x1a, 0x4c, 0x4f, 0x23, 0x8b, 0xdc,
+	0x8d, 0xd0, 0x99, 0x29, 0x27, 0x50, 0x1c, 0x7e, 0x25, 0x40, 0x7c, 0x41, 0xc9, 0x8f, 0x0f, 0xfa,
+	0xad, 0x04, 0x55, 0xea, 0xdd, 0xe0, 0x7a, 0x57, 0xd1, 0xca, 0x50, 0x6f, 0xc8, 0x65, 0x76, 0x34,
+	0xf4, 0xb9, 0xac, 0xbe, 0x64, 0xbc, 0x58, 0x1f, 0x66, 0xcd, 0xf1, 0xa0, 0xb1, 0x31, 0x46, 0x9f,
+	0xb6, 0x35, 0xbb, 0xcf, 0x46, 0x52, 0xcc, 0x6a, 0xa5, 0x40, 0x86, 0x92, 0x91, 0x82, 0x78, 0x3c,
+	0xd9, 0x18, 0xa3, 0xcf, 0x56, 0x20, 0xa4, 0xa2, 0x0a, 0x64, 0x08, 0x19, 0x29, 0x88, 0xc7, 0x91,
+	0x8d, 0x31, 0xfa, 0x6c, 0x05, 0xcd, 0xe1, 0x95, 0x3d, 0x80, 0x2c, 0x4f, 0x71, 0xc5, 0x95, 0x8d,
+	0x66, 0xcd, 0xfa, 0x6a, 0x84, 0x22, 0xe1, 0xd6, 0x39, 0x5c, 0x05, 0x95, 0x87, 0xbe, 0x7e, 0xce,
+	0xf8, 0x3b, 0x1a, 0x7a, 0x04, 0x19, 0x76, 0x4d, 0xd0, 0x8a, 0xba, 0x30, 0x0a, 0xa5, 0x32, 0x22,
+	0x48, 0x90, 0xb7, 0x38, 0x88, 0x81, 0xe2, 0x01, 0xee, 0xe9, 0x8a, 0x20, 0xb8, 0x8a, 0x80, 0x9e,
+	0x40, 0x71, 0xd8, 0x2c, 0x14, 0x47, 0x22, 0xd9, 0x9d, 0xd4, 0x6f, 0x25, 0xa8, 0x52, 0xc3, 0xab,
+	0x5c, 0xc3, 0x86, 0x39, 0x0c, 0xc9, 0xbb, 0x9e, 0x90, 0x71, 0xf8, 0x59, 0x7b, 0x0f, 0x8a, 0xad,
+	0xab, 0x18, 0x70, 0xeb, 0x6a, 0x12, 0xf0, 0x78, 0xab, 0xaf, 0x07, 0x95, 0x64, 0x3f, 0x0e, 0xf1,
+	0x4b, 0x31, 0xa5, 0xa5, 0xa7, 0x6f, 0x4e, 0x66, 0xc6, 0x23, 0x20, 0x1a, 0x5e, 0xd1, 0x5d, 0x27,
+	0x06, 0x7d, 0x17, 0x72, 0xc2, 0x06, 0x11, 0x4b, 0x62, 0x1d, 0x3b, 0x1d, 0x45, 0x49, 0x02, 0x70,
+	0x47, 0x63, 0x53, 0xda, 0xbd, 0xd1, 0x94, 0x76, 0x6f, 0x6c, 0x4a, 0xbc, 0x97, 0x72, 0x47, 0x6b,
+	0xfc, 0x21, 0x0d, 0x65, 0xf9, 0x44, 0xab, 0x37, 0xf6, 0x33, 0xf5, 0x71, 0x5a, 0xd2, 0x45, 0xe8,
+	0x9d, 0x94, 0x32, 0xea, 0xb7, 0x27, 0x70, 0xe2, 0xa7, 0xc5, 0x5c, 0x62, 0xcb, 0x93, 0xc9, 0x08,
+	0xf3, 0xff, 0x63, 0x58, 0x8e, 0x26, 0x6c, 0x68, 0x43, 0x85, 0x95, 0x44, 0xae, 0xa7, 0x57, 0xc7,
+	0x19, 0xf1, 0x37, 0x1d, 0x45, 0xa1, 0xd1, 0x8f, 0xd4, 0xd7, 0xda, 0x98, 0xd5, 0x93, 0x12, 0x39,
+	0xfd, 0xf6, 0x04, 0x8e, 0x84, 0xae, 0x72, 0x68, 0xb4, 0x55, 0x89, 0x40, 0x8b, 0x03, 0xf9, 0x8d,
+	0x26, 0x3e, 0x37, 0x8d, 0x25, 0x45, 0xc8, 0x48, 0x18, 0x3a, 0x96, 0xb1, 0xe9, 0x6f, 0xcc, 0x90,
+	0x90, 0x8a, 0xb7, 0xb8, 0xe2, 0x37, 0x91, 0x19, 0x53, 0x3c, 0x4c, 0xe2, 0xbe, 0xaa, 0x8f, 0xd2,
+	0xa6, 0x07, 0x7f, 0x4c, 0xff, 0x6a, 0xef, 0xf7, 0x69, 0xf4, 0x0b, 0x0d, 0x96, 0x59, 0x6e, 0x64,
+	0xc8, 0xff, 0x58, 0x31, 0x29, 0xd4, 0x3b, 0x64, 0xbb, 0x13, 0xf4, 0x9d, 0xed, 0x0b, 0x4a, 0xfb,
+	0xdb, 0x01, 0x0e, 0xe9, 0x76, 0xcf, 0x73, 0x02, 0x22, 0x25, 0xb6, 0xe9, 0x80, 0x92, 0xc0, 0xb3,
+	0xbb, 0x46, 0x3f, 0x20, 0x5f, 0x60, 0x87, 0xa2, 0x3d, 0x26, 0x18, 0xee, 0xd6, 0xeb, 0x1d, 0x52,
+	0xbb, 0xf2, 0x6c, 0x12, 0x12, 0xbf, 0x73, 0x3e, 0xa8, 0x39, 0xa4, 0xb7, 0x28, 0x54, 0x23, 0x7d,
+	0xb7, 0xb6, 0xb3, 0xa5, 0x69, 0x8d, 0x8a, 0xdd, 0x17, 0xfd, 0x33, 0x8f, 0xf8, 0xf5, 0x2f, 0x42,
+	0xe2, 0xef, 0x8e, 0x51, 0xac, 0xf7, 0x21, 0xfd, 0xee, 0xce, 0xbb, 0xa8, 0x01, 0x77, 0x2c, 0x4c,
+	0x07, 0x81, 0x6f, 0x3c, 0xbf, 0xc0, 0xbe, 0x41, 0x2f, 0xb0, 0x11, 0xe0, 0x90, 0x0c, 0x02, 0x07,
+	0x1b, 0x2e, 0xc1, 0xa1, 0xe1, 0x13, 0x6a, 0xe0, 0x2b, 0x2f, 0xa4, 0x35, 0x94, 0x83, 0xcc, 0x6f,
+	0x53, 0x5a, 0xfe, 0xe9, 0xb7, 0x1a, 0xfc, 0x4e, 0x83, 0xa5, 0x07, 0xd8, 0x0e, 0xb0, 0xbc, 0x21,
+	0xbf, 0xd1, 0x0a, 0x29, 0xf4, 0xb5, 0x26, 0x48, 0x06, 0x6f, 0x6e, 0x1b, 0xe4, 0x9c, 0xc3, 0xb1,
+	0xf6, 0xb7, 0xd1, 0xc7, 0x01, 0xeb, 0xaf, 0x7a, 0x7e, 0x87, 0xd3, 0x48, 0x1f, 0x07, 0xdc, 0x96,
+	0x77, 0x0c, 0x5c, 0xeb, 0xd4, 0x0c, 0x53, 0x4e, 0xbc, 0x77, 0xfe, 0xbe, 0x53, 0xab, 0xd5, 0xcc,
+	0xff, 0xe3, 0x52, 0xf6, 0x80, 0x5e, 0x60, 0x9f, 0x32, 0xb3, 0xb1, 0x2b, 0x70, 0xbc, 0xd0, 0x10,
+	0x2d, 0x55, 0xec, 0x1a, 0x9e, 0x30, 0x57, 0x7c, 0xc0, 0x34, 0x2e, 0xbc, 0x90, 0x92, 0xe0, 0x85,
+	0x5e, 0xda, 0x1b, 0xd0, 0x0b, 0x12, 0x78, 0x3f, 0xe5, 0xf8, 0x46, 0xea, 0xd9, 0x2a, 0xac, 0xc4,
+	0x6d, 0xbd, 0xf1, 0x2c, 0xc7, 0x0b, 0xb9, 0x7b, 0xff, 0x1e, 0x00, 0x52, 0xa8, 0xf6, 0x50, 0x66,
+	0x24, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ToDoServiceClient is the client API for ToDoService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ToDoServiceClient interface {
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	ReadAll(ctx context.Context, in *ReadAllRequest, opts ...grpc.CallOption) (*ReadAllResponse, error)
+	Undelete(ctx context.Context, in *UndeleteRequest, opts ...grpc.CallOption) (*UndeleteResponse, error)
+	Purge(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*PurgeResponse, error)
+	ListRevisions(ctx context.Context, in *ListRevisionsRequest, opts ...grpc.CallOption) (*ListRevisionsResponse, error)
+	ReadAt(ctx context.Context, in *ReadAtRequest, opts ...grpc.CallOption) (*ReadAtResponse, error)
+	Revert(ctx context.Context, in *RevertRequest, opts ...grpc.CallOption) (*RevertResponse, error)
+	Complete(ctx context.Context, in *CompleteRequest, opts ...grpc.CallOption) (*CompleteResponse, error)
+	ListOccurrences(ctx context.Context, in *ListOccurrencesRequest, opts ...grpc.CallOption) (*ListOccurrencesResponse, error)
+	StreamAll(ctx context.Context, in *StreamAllRequest, opts ...grpc.CallOption) (ToDoService_StreamAllClient, error)
+	BatchCreate(ctx context.Context, in *BatchCreateRequest, opts ...grpc.CallOption) (*BatchCreateResponse, error)
+	BatchUpdate(ctx context.Context, in *BatchUpdateRequest, opts ...grpc.CallOption) (*BatchUpdateResponse, error)
+	BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ToDoService_WatchClient, error)
+	Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error)
+	ImportICS(ctx context.Context, in *ImportICSRequest, opts ...grpc.CallOption) (*ImportICSResponse, error)
+	ExportICS(ctx context.Context, in *ExportICSRequest, opts ...grpc.CallOption) (*ExportICSResponse, error)
+	GetCalendarToken(ctx context.Context, in *GetCalendarTokenRequest, opts ...grpc.CallOption) (*GetCalendarTokenResponse, error)
+	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (ToDoService_ExportClient, error)
+	Import(ctx context.Context, opts ...grpc.CallOption) (ToDoService_ImportClient, error)
+}
+
+type toDoServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewToDoServiceClient(cc *grpc.ClientConn) ToDoServiceClient {
+	return &toDoServiceClient{cc}
+}
+
+func (c *toDoServiceClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*UpdateResponse, error) {
+	out := new(UpdateResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Update", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) ReadAll(ctx context.Context, in *ReadAllRequest, opts ...grpc.CallOption) (*ReadAllResponse, error) {
+	out := new(ReadAllResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/ReadAll", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Undelete(ctx context.Context, in *UndeleteRequest, opts ...grpc.CallOption) (*UndeleteResponse, error) {
+	out := new(UndeleteResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Undelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Purge(ctx context.Context, in *PurgeRequest, opts ...grpc.CallOption) (*PurgeResponse, error) {
+	out := new(PurgeResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Purge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) ListRevisions(ctx context.Context, in *ListRevisionsRequest, opts ...grpc.CallOption) (*ListRevisionsResponse, error) {
+	out := new(ListRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/ListRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) ReadAt(ctx context.Context, in *ReadAtRequest, opts ...grpc.CallOption) (*ReadAtResponse, error) {
+	out := new(ReadAtResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/ReadAt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Revert(ctx context.Context, in *RevertRequest, opts ...grpc.CallOption) (*RevertResponse, error) {
+	out := new(RevertResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Revert", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Complete(ctx context.Context, in *CompleteRequest, opts ...grpc.CallOption) (*CompleteResponse, error) {
+	out := new(CompleteResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Complete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) ListOccurrences(ctx context.Context, in *ListOccurrencesRequest, opts ...grpc.CallOption) (*ListOccurrencesResponse, error) {
+	out := new(ListOccurrencesResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/ListOccurrences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) StreamAll(ctx context.Context, in *StreamAllRequest, opts ...grpc.CallOption) (ToDoService_StreamAllClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ToDoService_serviceDesc.Streams[0], "/v1.ToDoService/StreamAll", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &toDoServiceStreamAllClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ToDoService_StreamAllClient interface {
+	Recv() (*StreamAllResponse, error)
+	grpc.ClientStream
+}
+
+type toDoServiceStreamAllClient struct {
+	grpc.ClientStream
+}
+
+func (x *toDoServiceStreamAllClient) Recv() (*StreamAllResponse, error) {
+	m := new(StreamAllResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *toDoServiceClient) BatchCreate(ctx context.Context, in *BatchCreateRequest, opts ...grpc.CallOption) (*BatchCreateResponse, error) {
+	out := new(BatchCreateResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/BatchCreate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) BatchUpdate(ctx context.Context, in *BatchUpdateRequest, opts ...grpc.CallOption) (*BatchUpdateResponse, error) {
+	out := new(BatchUpdateResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/BatchUpdate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error) {
+	out := new(BatchDeleteResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/BatchDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (ToDoService_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ToDoService_serviceDesc.Streams[1], "/v1.ToDoService/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &toDoServiceWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ToDoService_WatchClient interface {
+	Recv() (*WatchResponse, error)
+	grpc.ClientStream
+}
+
+type toDoServiceWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *toDoServiceWatchClient) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *toDoServiceClient) Read(ctx context.Context, in *ReadRequest, opts ...grpc.CallOption) (*ReadResponse, error) {
+	out := new(ReadResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/Read", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) ImportICS(ctx context.Context, in *ImportICSRequest, opts ...grpc.CallOption) (*ImportICSResponse, error) {
+	out := new(ImportICSResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/ImportICS", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) ExportICS(ctx context.Context, in *ExportICSRequest, opts ...grpc.CallOption) (*ExportICSResponse, error) {
+	out := new(ExportICSResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/ExportICS", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) GetCalendarToken(ctx context.Context, in *GetCalendarTokenRequest, opts ...grpc.CallOption) (*GetCalendarTokenResponse, error) {
+	out := new(GetCalendarTokenResponse)
+	err := c.cc.Invoke(ctx, "/v1.ToDoService/GetCalendarToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (ToDoService_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ToDoService_serviceDesc.Streams[2], "/v1.ToDoService/Export", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &toDoServiceExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ToDoService_ExportClient interface {
+	Recv() (*ExportResponse, error)
+	grpc.ClientStream
+}
+
+type toDoServiceExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *toDoServiceExportClient) Recv() (*ExportResponse, error) {
+	m := new(ExportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *toDoServiceClient) Import(ctx context.Context, opts ...grpc.CallOption) (ToDoService_ImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ToDoService_serviceDesc.Streams[3], "/v1.ToDoService/Import", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &toDoServiceImportClient{stream}
+	return x, nil
+}
+
+type ToDoService_ImportClient interface {
+	Send(*ImportRequest) error
+	CloseAndRecv() (*ImportResponse, error)
+	grpc.ClientStream
+}
+
+type toDoServiceImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *toDoServiceImportClient) Send(m *ImportRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *toDoServiceImportClient) CloseAndRecv() (*ImportResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ToDoServiceServer is the server API for ToDoService service.
+type ToDoServiceServer interface {
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	Update(context.Context, *UpdateRequest) (*UpdateResponse, error)
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	ReadAll(context.Context, *ReadAllRequest) (*ReadAllResponse, error)
+	Undelete(context.Context, *UndeleteRequest) (*UndeleteResponse, error)
+	Purge(context.Context, *PurgeRequest) (*PurgeResponse, error)
+	ListRevisions(context.Context, *ListRevisionsRequest) (*ListRevisionsResponse, error)
+	ReadAt(context.Context, *ReadAtRequest) (*ReadAtResponse, error)
+	Revert(context.Context, *RevertRequest) (*RevertResponse, error)
+	Complete(context.Context, *CompleteRequest) (*CompleteResponse, error)
+	ListOccurrences(context.Context, *ListOccurrencesRequest) (*ListOccurrencesResponse, error)
+	StreamAll(*StreamAllRequest, ToDoService_StreamAllServer) error
+	BatchCreate(context.Context, *BatchCreateRequest) (*BatchCreateResponse, error)
+	BatchUpdate(context.Context, *BatchUpdateRequest) (*BatchUpdateResponse, error)
+	BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error)
+	Watch(*WatchRequest, ToDoService_WatchServer) error
+	Read(context.Context, *ReadRequest) (*ReadResponse, error)
+	ImportICS(context.Context, *ImportICSRequest) (*ImportICSResponse, error)
+	ExportICS(context.Context, *ExportICSRequest) (*ExportICSResponse, error)
+	GetCalendarToken(context.Context, *GetCalendarTokenRequest) (*GetCalendarTokenResponse, error)
+	Export(*ExportRequest, ToDoService_ExportServer) error
+	Import(ToDoService_ImportServer) error
+}
+
+func RegisterToDoServiceServer(s *grpc.Server, srv ToDoServiceServer) {
+	s.RegisterService(&_ToDoService_serviceDesc, srv)
+}
+
+func _ToDoService_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).Update(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_ReadAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).ReadAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/ReadAll",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).ReadAll(ctx, req.(*ReadAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_Undelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).Undelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/Undelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).Undelete(ctx, req.(*UndeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).Purge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/Purge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).Purge(ctx, req.(*PurgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_ListRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).ListRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/ListRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).ListRevisions(ctx, req.(*ListRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_ReadAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).ReadAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/ReadAt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).ReadAt(ctx, req.(*ReadAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_Revert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).Revert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/Revert",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).Revert(ctx, req.(*RevertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_Complete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).Complete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/Complete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).Complete(ctx, req.(*CompleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_ListOccurrences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOccurrencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).ListOccurrences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/ListOccurrences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).ListOccurrences(ctx, req.(*ListOccurrencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_StreamAll_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamAllRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ToDoServiceServer).StreamAll(m, &toDoServiceStreamAllServer{stream})
+}
+
+type ToDoService_StreamAllServer interface {
+	Send(*StreamAllResponse) error
+	grpc.ServerStream
+}
+
+type toDoServiceStreamAllServer struct {
+	grpc.ServerStream
+}
+
+func (x *toDoServiceStreamAllServer) Send(m *StreamAllResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ToDoService_BatchCreate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchCreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).BatchCreate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/BatchCreate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).BatchCreate(ctx, req.(*BatchCreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_BatchUpdate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).BatchUpdate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/BatchUpdate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).BatchUpdate(ctx, req.(*BatchUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_BatchDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).BatchDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.ToDoService/BatchDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).BatchDelete(ctx, req.(*BatchDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ToDoServiceServer).Watch(m, &toDoServiceWatchServer{stream})
+}
+
+type ToDoService_WatchServer interface {
+	Send(*WatchResponse) error
+	grpc.ServerStream
+}
+
+type toDoServiceWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *toDoServiceWatchServer) Send(m *WatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ToDoService_Read_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).Read(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
@@ -734,60 +4309,107 @@ func _ToDoService_Read_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ToDoService_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateRequest)
+func _ToDoService_ImportICS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportICSRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ToDoServiceServer).Update(ctx, in)
+		return srv.(ToDoServiceServer).ImportICS(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.ToDoService/Update",
+		FullMethod: "/v1.ToDoService/ImportICS",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ToDoServiceServer).Update(ctx, req.(*UpdateRequest))
+		return srv.(ToDoServiceServer).ImportICS(ctx, req.(*ImportICSRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ToDoService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteRequest)
+func _ToDoService_ExportICS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportICSRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ToDoServiceServer).Delete(ctx, in)
+		return srv.(ToDoServiceServer).ExportICS(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.ToDoService/Delete",
+		FullMethod: "/v1.ToDoService/ExportICS",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ToDoServiceServer).Delete(ctx, req.(*DeleteRequest))
+		return srv.(ToDoServiceServer).ExportICS(ctx, req.(*ExportICSRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ToDoService_ReadAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReadAllRequest)
+func _ToDoService_GetCalendarToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCalendarTokenRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ToDoServiceServer).ReadAll(ctx, in)
+		return srv.(ToDoServiceServer).GetCalendarToken(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.ToDoService/ReadAll",
+		FullMethod: "/v1.ToDoService/GetCalendarToken",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ToDoServiceServer).ReadAll(ctx, req.(*ReadAllRequest))
+		return srv.(ToDoServiceServer).GetCalendarToken(ctx, req.(*GetCalendarTokenRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ToDoService_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ToDoServiceServer).Export(m, &toDoServiceExportServer{stream})
+}
+
+type ToDoService_ExportServer interface {
+	Send(*ExportResponse) error
+	grpc.ServerStream
+}
+
+type toDoServiceExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *toDoServiceExportServer) Send(m *ExportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ToDoService_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ToDoServiceServer).Import(&toDoServiceImportServer{stream})
+}
+
+type ToDoService_ImportServer interface {
+	SendAndClose(*ImportResponse) error
+	Recv() (*ImportRequest, error)
+	grpc.ServerStream
+}
+
+type toDoServiceImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *toDoServiceImportServer) SendAndClose(m *ImportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *toDoServiceImportServer) Recv() (*ImportRequest, error) {
+	m := new(ImportRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _ToDoService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v1.ToDoService",
 	HandlerType: (*ToDoServiceServer)(nil),
@@ -796,10 +4418,6 @@ var _ToDoService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Create",
 			Handler:    _ToDoService_Create_Handler,
 		},
-		{
-			MethodName: "Read",
-			Handler:    _ToDoService_Read_Handler,
-		},
 		{
 			MethodName: "Update",
 			Handler:    _ToDoService_Update_Handler,
@@ -812,6 +4430,246 @@ var _ToDoService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ReadAll",
 			Handler:    _ToDoService_ReadAll_Handler,
 		},
+		{
+			MethodName: "Undelete",
+			Handler:    _ToDoService_Undelete_Handler,
+		},
+		{
+			MethodName: "Purge",
+			Handler:    _ToDoService_Purge_Handler,
+		},
+		{
+			MethodName: "ListRevisions",
+			Handler:    _ToDoService_ListRevisions_Handler,
+		},
+		{
+			MethodName: "ReadAt",
+			Handler:    _ToDoService_ReadAt_Handler,
+		},
+		{
+			MethodName: "Revert",
+			Handler:    _ToDoService_Revert_Handler,
+		},
+		{
+			MethodName: "Complete",
+			Handler:    _ToDoService_Complete_Handler,
+		},
+		{
+			MethodName: "ListOccurrences",
+			Handler:    _ToDoService_ListOccurrences_Handler,
+		},
+		{
+			MethodName: "BatchCreate",
+			Handler:    _ToDoService_BatchCreate_Handler,
+		},
+		{
+			MethodName: "BatchUpdate",
+			Handler:    _ToDoService_BatchUpdate_Handler,
+		},
+		{
+			MethodName: "BatchDelete",
+			Handler:    _ToDoService_BatchDelete_Handler,
+		},
+		{
+			MethodName: "Read",
+			Handler:    _ToDoService_Read_Handler,
+		},
+		{
+			MethodName: "ImportICS",
+			Handler:    _ToDoService_ImportICS_Handler,
+		},
+		{
+			MethodName: "ExportICS",
+			Handler:    _ToDoService_ExportICS_Handler,
+		},
+		{
+			MethodName: "GetCalendarToken",
+			Handler:    _ToDoService_GetCalendarToken_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamAll",
+			Handler:       _ToDoService_StreamAll_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _ToDoService_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Export",
+			Handler:       _ToDoService_Export_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Import",
+			Handler:       _ToDoService_Import_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "todo_service.proto",
+}
+
+// WebhookServiceClient is the client API for WebhookService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type WebhookServiceClient interface {
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+}
+
+type webhookServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWebhookServiceClient(cc *grpc.ClientConn) WebhookServiceClient {
+	return &webhookServiceClient{cc}
+}
+
+func (c *webhookServiceClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*CreateWebhookResponse, error) {
+	out := new(CreateWebhookResponse)
+	err := c.cc.Invoke(ctx, "/v1.WebhookService/CreateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, "/v1.WebhookService/ListWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, "/v1.WebhookService/DeleteWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, "/v1.WebhookService/ListWebhookDeliveries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WebhookServiceServer is the server API for WebhookService service.
+type WebhookServiceServer interface {
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*CreateWebhookResponse, error)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+}
+
+func RegisterWebhookServiceServer(s *grpc.Server, srv WebhookServiceServer) {
+	s.RegisterService(&_WebhookService_serviceDesc, srv)
+}
+
+func _WebhookService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WebhookService/CreateWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WebhookService/ListWebhooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WebhookService/DeleteWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WebhookService/ListWebhookDeliveries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _WebhookService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.WebhookService",
+	HandlerType: (*WebhookServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _WebhookService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _WebhookService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _WebhookService_DeleteWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _WebhookService_ListWebhookDeliveries_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "todo_service.proto",
@@ -0,0 +1,806 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: v2/todo_service.proto
+
+package v2
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	_ "github.com/grpc-ecosystem/grpc-gateway/protoc-gen-swagger/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	field_mask "google.golang.org/genproto/protobuf/field_mask"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ToDo_ReminderStatus int32
+
+const (
+	ToDo_REMINDER_STATUS_UNSPECIFIED ToDo_ReminderStatus = 0
+	ToDo_PENDING                     ToDo_ReminderStatus = 1
+	ToDo_DELIVERED                   ToDo_ReminderStatus = 2
+	ToDo_FAILED                      ToDo_ReminderStatus = 3
+)
+
+var ToDo_ReminderStatus_name = map[int32]string{
+	0: "REMINDER_STATUS_UNSPECIFIED",
+	1: "PENDING",
+	2: "DELIVERED",
+	3: "FAILED",
+}
+
+var ToDo_ReminderStatus_value = map[string]int32{
+	"REMINDER_STATUS_UNSPECIFIED": 0,
+	"PENDING":                     1,
+	"DELIVERED":                   2,
+	"FAILED":                      3,
+}
+
+func (x ToDo_ReminderStatus) String() string {
+	return proto.EnumName(ToDo_ReminderStatus_name, int32(x))
+}
+
+func (ToDo_ReminderStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_dcdfd930270bf3d2, []int{0, 0}
+}
+
+// ToDo 与 v1 的 ToDo 保存在同一张表中，v1 的 ID 为 42 的 ToDo 在 v2 中的资源名是 todos/42
+type ToDo struct {
+	// 资源名，格式为 todos/{id}，创建时由服务器分配
+	Name        string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Title       string               `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string               `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Reminder    *timestamp.Timestamp `protobuf:"bytes,4,opt,name=reminder,proto3" json:"reminder,omitempty"`
+	// RFC 5545 的 DTSTART/RRULE/EXDATE (不支持 EXRULE 和 RDATE)，为空表示不重复
+	Recurrence string `protobuf:"bytes,5,opt,name=recurrence,proto3" json:"recurrence,omitempty"`
+	// 解释 recurrence 使用的 IANA 时区
+	TimeZone string `protobuf:"bytes,6,opt,name=timeZone,proto3" json:"timeZone,omitempty"`
+	// 以下字段只读
+	ReminderStatus       ToDo_ReminderStatus  `protobuf:"varint,7,opt,name=reminderStatus,proto3,enum=v2.ToDo_ReminderStatus" json:"reminderStatus,omitempty"`
+	ReminderDeliveryTime *timestamp.Timestamp `protobuf:"bytes,8,opt,name=reminderDeliveryTime,proto3" json:"reminderDeliveryTime,omitempty"`
+	// 不为空表示 ToDo 在回收站中
+	DeleteTime           *timestamp.Timestamp `protobuf:"bytes,9,opt,name=deleteTime,proto3" json:"deleteTime,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ToDo) Reset()         { *m = ToDo{} }
+func (m *ToDo) String() string { return proto.CompactTextString(m) }
+func (*ToDo) ProtoMessage()    {}
+func (*ToDo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dcdfd930270bf3d2, []int{0}
+}
+
+func (m *ToDo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ToDo.Unmarshal(m, b)
+}
+func (m *ToDo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ToDo.Marshal(b, m, deterministic)
+}
+func (m *ToDo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ToDo.Merge(m, src)
+}
+func (m *ToDo) XXX_Size() int {
+	return xxx_messageInfo_ToDo.Size(m)
+}
+func (m *ToDo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ToDo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ToDo proto.InternalMessageInfo
+
+func (m *ToDo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ToDo) GetTitle() string {
+	if m != nil {
+		return m.Title
+	}
+	return ""
+}
+
+func (m *ToDo) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *ToDo) GetReminder() *timestamp.Timestamp {
+	if m != nil {
+		return m.Reminder
+	}
+	return nil
+}
+
+func (m *ToDo) GetRecurrence() string {
+	if m != nil {
+		return m.Recurrence
+	}
+	return ""
+}
+
+func (m *ToDo) GetTimeZone() string {
+	if m != nil {
+		return m.TimeZone
+	}
+	return ""
+}
+
+func (m *ToDo) GetReminderStatus() ToDo_ReminderStatus {
+	if m != nil {
+		return m.ReminderStatus
+	}
+	return ToDo_REMINDER_STATUS_UNSPECIFIED
+}
+
+func (m *ToDo) GetReminderDeliveryTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.ReminderDeliveryTime
+	}
+	return nil
+}
+
+func (m *ToDo) GetDeleteTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.DeleteTime
+	}
+	return nil
+}
+
+type GetToDoRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetToDoRequest) Reset()         { *m = GetToDoRequest{} }
+func (m *GetToDoRequest) String() string { return proto.CompactTextString(m) }
+func (*GetToDoRequest) ProtoMessage()    {}
+func (*GetToDoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dcdfd930270bf3d2, []int{1}
+}
+
+func (m *GetToDoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetToDoRequest.Unmarshal(m, b)
+}
+func (m *GetToDoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetToDoRequest.Marshal(b, m, deterministic)
+}
+func (m *GetToDoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetToDoRequest.Merge(m, src)
+}
+func (m *GetToDoRequest) XXX_Size() int {
+	return xxx_messageInfo_GetToDoRequest.Size(m)
+}
+func (m *GetToDoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetToDoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetToDoRequest proto.InternalMessageInfo
+
+func (m *GetToDoRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type ListToDosRequest struct {
+	// 每页最多返回的数量，0 表示默认的 50，最大 1000
+	PageSize int32 `protobuf:"varint,1,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	// 上一页返回的 nextPageToken
+	PageToken string `protobuf:"bytes,2,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	// 是否包括回收站中的 ToDo
+	ShowDeleted          bool     `protobuf:"varint,3,opt,name=showDeleted,proto3" json:"showDeleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListToDosRequest) Reset()         { *m = ListToDosRequest{} }
+func (m *ListToDosRequest) String() string { return proto.CompactTextString(m) }
+func (*ListToDosRequest) ProtoMessage()    {}
+func (*ListToDosRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dcdfd930270bf3d2, []int{2}
+}
+
+func (m *ListToDosRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListToDosRequest.Unmarshal(m, b)
+}
+func (m *ListToDosRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListToDosRequest.Marshal(b, m, deterministic)
+}
+func (m *ListToDosRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListToDosRequest.Merge(m, src)
+}
+func (m *ListToDosRequest) XXX_Size() int {
+	return xxx_messageInfo_ListToDosRequest.Size(m)
+}
+func (m *ListToDosRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListToDosRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListToDosRequest proto.InternalMessageInfo
+
+func (m *ListToDosRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListToDosRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListToDosRequest) GetShowDeleted() bool {
+	if m != nil {
+		return m.ShowDeleted
+	}
+	return false
+}
+
+type ListToDosResponse struct {
+	ToDos []*ToDo `protobuf:"bytes,1,rep,name=toDos,proto3" json:"toDos,omitempty"`
+	// 为空表示没有下一页
+	NextPageToken        string   `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListToDosResponse) Reset()         { *m = ListToDosResponse{} }
+func (m *ListToDosResponse) String() string { return proto.CompactTextString(m) }
+func (*ListToDosResponse) ProtoMessage()    {}
+func (*ListToDosResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dcdfd930270bf3d2, []int{3}
+}
+
+func (m *ListToDosResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListToDosResponse.Unmarshal(m, b)
+}
+func (m *ListToDosResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListToDosResponse.Marshal(b, m, deterministic)
+}
+func (m *ListToDosResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListToDosResponse.Merge(m, src)
+}
+func (m *ListToDosResponse) XXX_Size() int {
+	return xxx_messageInfo_ListToDosResponse.Size(m)
+}
+func (m *ListToDosResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListToDosResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListToDosResponse proto.InternalMessageInfo
+
+func (m *ListToDosResponse) GetToDos() []*ToDo {
+	if m != nil {
+		return m.ToDos
+	}
+	return nil
+}
+
+func (m *ListToDosResponse) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+type CreateToDoRequest struct {
+	ToDo *ToDo `protobuf:"bytes,1,opt,name=toDo,proto3" json:"toDo,omitempty"`
+	// 幂等键，与 v1 CreateRequest.requestId 共用
+	RequestId            string   `protobuf:"bytes,2,opt,name=requestId,proto3" json:"requestId,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateToDoRequest) Reset()         { *m = CreateToDoRequest{} }
+func (m *CreateToDoRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateToDoRequest) ProtoMessage()    {}
+func (*CreateToDoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dcdfd930270bf3d2, []int{4}
+}
+
+func (m *CreateToDoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateToDoRequest.Unmarshal(m, b)
+}
+func (m *CreateToDoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateToDoRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateToDoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateToDoRequest.Merge(m, src)
+}
+func (m *CreateToDoRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateToDoRequest.Size(m)
+}
+func (m *CreateToDoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateToDoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateToDoRequest proto.InternalMessageInfo
+
+func (m *CreateToDoRequest) GetToDo() *ToDo {
+	if m != nil {
+		return m.ToDo
+	}
+	return nil
+}
+
+func (m *CreateToDoRequest) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+type UpdateToDoRequest struct {
+	// name 指定要修改的 ToDo
+	ToDo *ToDo `protobuf:"bytes,1,opt,name=toDo,proto3" json:"toDo,omitempty"`
+	// 要修改的字段，为空时修改 toDo 中所有不为空的字段，"*" 表示替换所有可修改的字段
+	// 通过 REST 调用时网关按请求体中出现的字段生成 updateMask
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,2,opt,name=updateMask,proto3" json:"updateMask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *UpdateToDoRequest) Reset()         { *m = UpdateToDoRequest{} }
+func (m *UpdateToDoRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateToDoRequest) ProtoMessage()    {}
+func (*UpdateToDoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dcdfd930270bf3d2, []int{5}
+}
+
+func (m *UpdateToDoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateToDoRequest.Unmarshal(m, b)
+}
+func (m *UpdateToDoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateToDoRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateToDoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateToDoRequest.Merge(m, src)
+}
+func (m *UpdateToDoRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateToDoRequest.Size(m)
+}
+func (m *UpdateToDoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateToDoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateToDoRequest proto.InternalMessageInfo
+
+func (m *UpdateToDoRequest) GetToDo() *ToDo {
+	if m != nil {
+		return m.ToDo
+	}
+	return nil
+}
+
+func (m *UpdateToDoRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+type DeleteToDoRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteToDoRequest) Reset()         { *m = DeleteToDoRequest{} }
+func (m *DeleteToDoRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteToDoRequest) ProtoMessage()    {}
+func (*DeleteToDoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dcdfd930270bf3d2, []int{6}
+}
+
+func (m *DeleteToDoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteToDoRequest.Unmarshal(m, b)
+}
+func (m *DeleteToDoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteToDoRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteToDoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteToDoRequest.Merge(m, src)
+}
+func (m *DeleteToDoRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteToDoRequest.Size(m)
+}
+func (m *DeleteToDoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteToDoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteToDoRequest proto.InternalMessageInfo
+
+func (m *DeleteToDoRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type UndeleteToDoRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UndeleteToDoRequest) Reset()         { *m = UndeleteToDoRequest{} }
+func (m *UndeleteToDoRequest) String() string { return proto.CompactTextString(m) }
+func (*UndeleteToDoRequest) ProtoMessage()    {}
+func (*UndeleteToDoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_dcdfd930270bf3d2, []int{7}
+}
+
+func (m *UndeleteToDoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UndeleteToDoRequest.Unmarshal(m, b)
+}
+func (m *UndeleteToDoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UndeleteToDoRequest.Marshal(b, m, deterministic)
+}
+func (m *UndeleteToDoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UndeleteToDoRequest.Merge(m, src)
+}
+func (m *UndeleteToDoRequest) XXX_Size() int {
+	return xxx_messageInfo_UndeleteToDoRequest.Size(m)
+}
+func (m *UndeleteToDoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UndeleteToDoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UndeleteToDoRequest proto.InternalMessageInfo
+
+func (m *UndeleteToDoRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("v2.ToDo_ReminderStatus", ToDo_ReminderStatus_name, ToDo_ReminderStatus_value)
+	proto.RegisterType((*ToDo)(nil), "v2.ToDo")
+	proto.RegisterType((*GetToDoRequest)(nil), "v2.GetToDoRequest")
+	proto.RegisterType((*ListToDosRequest)(nil), "v2.ListToDosRequest")
+	proto.RegisterType((*ListToDosResponse)(nil), "v2.ListToDosResponse")
+	proto.RegisterType((*CreateToDoRequest)(nil), "v2.CreateToDoRequest")
+	proto.RegisterType((*UpdateToDoRequest)(nil), "v2.UpdateToDoRequest")
+	proto.RegisterType((*DeleteToDoRequest)(nil), "v2.DeleteToDoRequest")
+	proto.RegisterType((*UndeleteToDoRequest)(nil), "v2.UndeleteToDoRequest")
+}
+
+func init() { proto.RegisterFile("v2/todo_service.proto", fileDescriptor_dcdfd930270bf3d2) }
+
+var fileDescriptor_dcdfd930270bf3d2 = []byte{
+	// 966 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x95, 0x4f, 0x6f, 0xe3, 0x44,
+	0x18, 0xc6, 0xd7, 0x49, 0xff, 0x24, 0x6f, 0xb6, 0x25, 0x19, 0xba, 0x6a, 0x94, 0xad, 0xb6, 0x96,
+	0xb5, 0x40, 0xa9, 0x36, 0x36, 0xf2, 0x4a, 0x48, 0x04, 0x21, 0xd4, 0x6d, 0xd2, 0x55, 0x44, 0xb7,
+	0x54, 0x4e, 0x8a, 0xd4, 0xbd, 0x54, 0xae, 0xfd, 0xc6, 0xf1, 0x36, 0xf1, 0x98, 0x99, 0x71, 0xf6,
+	0x0f, 0xe2, 0xb2, 0x37, 0xae, 0x70, 0xe1, 0x23, 0x20, 0xf1, 0x71, 0xf8, 0x08, 0x70, 0xe6, 0xc4,
+	0x81, 0x23, 0x9a, 0xb1, 0x93, 0x3a, 0x49, 0x51, 0x77, 0x4f, 0x89, 0x9f, 0x79, 0xde, 0x9f, 0xe7,
+	0x9d, 0xe7, 0xcd, 0x04, 0xee, 0x4d, 0x6c, 0x4b, 0x50, 0x9f, 0x5e, 0x70, 0x64, 0x93, 0xd0, 0x43,
+	0x33, 0x66, 0x54, 0x50, 0x52, 0x98, 0xd8, 0x8d, 0xdd, 0x80, 0xd2, 0x60, 0x84, 0x96, 0x52, 0x2e,
+	0x93, 0x81, 0x25, 0xc2, 0x31, 0x72, 0xe1, 0x8e, 0xe3, 0xd4, 0xd4, 0xd0, 0x17, 0x0d, 0x83, 0x10,
+	0x47, 0xfe, 0xc5, 0xd8, 0xe5, 0x57, 0x99, 0x63, 0x27, 0x73, 0xb8, 0x71, 0x68, 0xb9, 0x51, 0x44,
+	0x85, 0x2b, 0x42, 0x1a, 0xf1, 0x6c, 0xf5, 0x91, 0xfa, 0xf0, 0x9a, 0x01, 0x46, 0x4d, 0xfe, 0xd2,
+	0x0d, 0x02, 0x64, 0x16, 0x8d, 0x95, 0x63, 0xd9, 0x6d, 0xfc, 0x5d, 0x84, 0x95, 0x3e, 0x6d, 0x53,
+	0x42, 0x60, 0x25, 0x72, 0xc7, 0x58, 0xd7, 0x74, 0x6d, 0xaf, 0xec, 0xa8, 0xef, 0x64, 0x0b, 0x56,
+	0x45, 0x28, 0x46, 0x58, 0x2f, 0x28, 0x31, 0x7d, 0x20, 0x3a, 0x54, 0x7c, 0xe4, 0x1e, 0x0b, 0x15,
+	0xb4, 0x5e, 0x54, 0x6b, 0x79, 0x89, 0x7c, 0x0e, 0x25, 0x86, 0xe3, 0x30, 0xf2, 0x91, 0xd5, 0x57,
+	0x74, 0x6d, 0xaf, 0x62, 0x37, 0xcc, 0x74, 0xcf, 0xe6, 0xb4, 0x2b, 0xb3, 0x3f, 0x6d, 0xdb, 0x99,
+	0x79, 0xc9, 0x03, 0x00, 0x86, 0x5e, 0xc2, 0x18, 0x46, 0x1e, 0xd6, 0x57, 0x15, 0x38, 0xa7, 0x90,
+	0x06, 0x94, 0xe4, 0x69, 0x3d, 0xa7, 0x11, 0xd6, 0xd7, 0xd4, 0xea, 0xec, 0x99, 0x7c, 0x0d, 0x9b,
+	0x53, 0x4e, 0x4f, 0xb8, 0x22, 0xe1, 0xf5, 0x75, 0x5d, 0xdb, 0xdb, 0xb4, 0xb7, 0xcd, 0x89, 0x6d,
+	0xca, 0x0e, 0x4d, 0x67, 0x6e, 0xd9, 0x59, 0xb0, 0x93, 0x13, 0xd8, 0x9a, 0x2a, 0x6d, 0x1c, 0x85,
+	0x13, 0x64, 0xaf, 0xe5, 0x1e, 0xeb, 0xa5, 0x5b, 0x1b, 0xb8, 0xb1, 0x8e, 0xb4, 0x00, 0x7c, 0x1c,
+	0xa1, 0x40, 0x45, 0x29, 0xdf, 0x4a, 0xc9, 0xb9, 0x8d, 0x73, 0xd8, 0x9c, 0xdf, 0x2d, 0xd9, 0x85,
+	0xfb, 0x4e, 0xe7, 0x59, 0xf7, 0xa4, 0xdd, 0x71, 0x2e, 0x7a, 0xfd, 0x83, 0xfe, 0x59, 0xef, 0xe2,
+	0xec, 0xa4, 0x77, 0xda, 0x39, 0xec, 0x1e, 0x75, 0x3b, 0xed, 0xea, 0x1d, 0x52, 0x81, 0xf5, 0xd3,
+	0xce, 0x49, 0xbb, 0x7b, 0xf2, 0xb4, 0xaa, 0x91, 0x0d, 0x28, 0xb7, 0x3b, 0xc7, 0xdd, 0xef, 0x3a,
+	0x4e, 0xa7, 0x5d, 0x2d, 0x10, 0x80, 0xb5, 0xa3, 0x83, 0xee, 0x71, 0xa7, 0x5d, 0x2d, 0x1a, 0x0f,
+	0x61, 0xf3, 0x29, 0x0a, 0x79, 0x20, 0x0e, 0x7e, 0x9f, 0x20, 0x17, 0x37, 0x25, 0x6f, 0x44, 0x50,
+	0x3d, 0x0e, 0xb9, 0xb2, 0xf1, 0xa9, 0xaf, 0x01, 0xa5, 0xd8, 0x0d, 0xb0, 0x17, 0xbe, 0x49, 0xbd,
+	0xab, 0xce, 0xec, 0x99, 0xec, 0x40, 0x59, 0x7e, 0xef, 0xd3, 0x2b, 0x8c, 0xb2, 0x69, 0xb9, 0x16,
+	0xe4, 0xc4, 0xf0, 0x21, 0x7d, 0xd9, 0x56, 0x0d, 0xfa, 0x6a, 0x62, 0x4a, 0x4e, 0x5e, 0x32, 0xce,
+	0xa1, 0x96, 0x7b, 0x1f, 0x8f, 0x69, 0xc4, 0x91, 0x3c, 0x80, 0x55, 0x21, 0x85, 0xba, 0xa6, 0x17,
+	0xf7, 0x2a, 0x76, 0x69, 0x9a, 0xa4, 0x93, 0xca, 0xe4, 0x21, 0x6c, 0x44, 0xf8, 0x4a, 0x9c, 0x2e,
+	0xbc, 0x78, 0x5e, 0x34, 0xbe, 0x85, 0xda, 0x21, 0x43, 0x57, 0x60, 0xbe, 0xe7, 0x1d, 0x58, 0x91,
+	0x0c, 0xd5, 0x47, 0x9e, 0xac, 0x54, 0xd9, 0x0d, 0x4b, 0x8d, 0x5d, 0x7f, 0xda, 0xcd, 0x4c, 0x30,
+	0xc6, 0x50, 0x3b, 0x8b, 0xfd, 0xf7, 0x02, 0xb6, 0x00, 0x12, 0x55, 0xf2, 0xcc, 0xe5, 0x57, 0xf5,
+	0xc2, 0xff, 0xcc, 0xc2, 0x91, 0xfc, 0xa1, 0x4b, 0x87, 0x93, 0x73, 0x1b, 0x9f, 0x40, 0x2d, 0x3d,
+	0xa5, 0xdb, 0x32, 0xfb, 0x14, 0x3e, 0x3c, 0x8b, 0xfc, 0x77, 0xb1, 0xda, 0x7f, 0x16, 0xa1, 0x22,
+	0x3d, 0xbd, 0xf4, 0x7a, 0x22, 0x87, 0xb0, 0x9e, 0x0d, 0x05, 0x21, 0x72, 0xeb, 0xf3, 0x13, 0xd2,
+	0x98, 0xb5, 0x63, 0x34, 0xde, 0xfe, 0xf1, 0xd7, 0x2f, 0x85, 0x2d, 0x42, 0xac, 0x89, 0x6d, 0xfd,
+	0x20, 0x59, 0x5f, 0xc9, 0x7b, 0x8e, 0x5b, 0xfb, 0x3f, 0x92, 0x6f, 0xa0, 0x3c, 0xcb, 0x90, 0x6c,
+	0xc9, 0x92, 0xc5, 0x11, 0x6a, 0xdc, 0x5b, 0x50, 0xd3, 0xa0, 0x8d, 0x9a, 0xa2, 0x56, 0x48, 0xd9,
+	0xca, 0xee, 0x4d, 0x4e, 0x8e, 0x00, 0xae, 0x53, 0x23, 0xaa, 0x6e, 0x29, 0xc5, 0xdc, 0xbe, 0xb6,
+	0x15, 0xa1, 0x66, 0x5c, 0x13, 0x5a, 0xe9, 0xc9, 0xf7, 0x01, 0xae, 0xc3, 0x4a, 0x39, 0x4b, 0xe1,
+	0xe5, 0x38, 0x1f, 0x29, 0xce, 0xae, 0xbd, 0xad, 0xfa, 0x93, 0x04, 0x73, 0xae, 0xc9, 0x8c, 0xda,
+	0x05, 0xb8, 0xce, 0x24, 0xa5, 0x2e, 0x65, 0xb4, 0x7c, 0x6a, 0xfb, 0x37, 0x9d, 0xda, 0x39, 0xdc,
+	0xcd, 0xa7, 0x46, 0xd4, 0x7d, 0x75, 0x43, 0x8e, 0x39, 0xdc, 0xc7, 0x0a, 0xa7, 0x1b, 0xf7, 0x97,
+	0x71, 0xad, 0x24, 0xab, 0x6c, 0x69, 0xfb, 0x4f, 0xfe, 0x2d, 0xfc, 0x7c, 0xf0, 0x4f, 0x81, 0xfc,
+	0xa4, 0xc1, 0x5d, 0x59, 0xa7, 0x67, 0x7f, 0x46, 0x86, 0x00, 0x2b, 0xa0, 0xcd, 0x80, 0xc5, 0x5e,
+	0x73, 0x28, 0x44, 0xdc, 0x64, 0xc8, 0x45, 0x73, 0x1c, 0x7a, 0x8c, 0x66, 0x8e, 0xa6, 0x48, 0x04,
+	0x65, 0xa1, 0x3b, 0xd2, 0x63, 0x46, 0x5f, 0xa0, 0x27, 0xc8, 0x81, 0x34, 0xf2, 0x96, 0x65, 0x05,
+	0xd4, 0x7c, 0x15, 0xba, 0x94, 0xd3, 0x28, 0x18, 0x24, 0xa6, 0x47, 0xc7, 0xef, 0x8a, 0xb2, 0x8b,
+	0xb6, 0xf9, 0xd9, 0xbe, 0xa6, 0xd9, 0x55, 0x37, 0x8e, 0x47, 0xa1, 0xa7, 0xfe, 0x85, 0xac, 0x17,
+	0x9c, 0x46, 0xad, 0x25, 0xe5, 0xf9, 0xef, 0x1a, 0xfc, 0xa6, 0x41, 0xe5, 0x09, 0xba, 0x0c, 0x59,
+	0x7a, 0x87, 0xfc, 0xaa, 0x95, 0x0a, 0xe4, 0xad, 0x96, 0x4a, 0xba, 0x90, 0x9a, 0x4e, 0x07, 0xba,
+	0x18, 0xa2, 0x9e, 0x70, 0x64, 0x7a, 0x8c, 0x6c, 0x40, 0xd9, 0x38, 0x8c, 0x02, 0xa5, 0xd1, 0x18,
+	0x99, 0x02, 0x3e, 0xd2, 0xd1, 0x0c, 0x4c, 0xdd, 0xc8, 0x0a, 0x1f, 0x0f, 0xbe, 0xf0, 0x4c, 0xd3,
+	0x34, 0xbe, 0x54, 0x2e, 0x37, 0x11, 0x43, 0x8c, 0x84, 0x7c, 0x37, 0xfa, 0x29, 0x27, 0xe4, 0x3a,
+	0x43, 0x8f, 0x32, 0x1f, 0x7d, 0x3d, 0x8c, 0x94, 0xcb, 0x1b, 0xba, 0x51, 0x80, 0xfa, 0x30, 0xe4,
+	0x82, 0xb2, 0xd7, 0x8d, 0x8d, 0x83, 0x44, 0x0c, 0x29, 0x0b, 0xdf, 0x28, 0xbe, 0x5e, 0xb8, 0xac,
+	0xc1, 0x07, 0xf3, 0x7b, 0xbd, 0x73, 0xb9, 0xa6, 0x7e, 0xd4, 0x8f, 0xff, 0x1b, 0x00, 0xc2, 0x17,
+	0xe2, 0x17, 0x09, 0x08, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// ToDoServiceClient is the client API for ToDoService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ToDoServiceClient interface {
+	GetToDo(ctx context.Context, in *GetToDoRequest, opts ...grpc.CallOption) (*ToDo, error)
+	ListToDos(ctx context.Context, in *ListToDosRequest, opts ...grpc.CallOption) (*ListToDosResponse, error)
+	CreateToDo(ctx context.Context, in *CreateToDoRequest, opts ...grpc.CallOption) (*ToDo, error)
+	UpdateToDo(ctx context.Context, in *UpdateToDoRequest, opts ...grpc.CallOption) (*ToDo, error)
+	// DeleteToDo 把 ToDo 移到回收站，返回删除后的 ToDo
+	DeleteToDo(ctx context.Context, in *DeleteToDoRequest, opts ...grpc.CallOption) (*ToDo, error)
+	UndeleteToDo(ctx context.Context, in *UndeleteToDoRequest, opts ...grpc.CallOption) (*ToDo, error)
+}
+
+type toDoServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewToDoServiceClient(cc *grpc.ClientConn) ToDoServiceClient {
+	return &toDoServiceClient{cc}
+}
+
+func (c *toDoServiceClient) GetToDo(ctx context.Context, in *GetToDoRequest, opts ...grpc.CallOption) (*ToDo, error) {
+	out := new(ToDo)
+	err := c.cc.Invoke(ctx, "/v2.ToDoService/GetToDo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) ListToDos(ctx context.Context, in *ListToDosRequest, opts ...grpc.CallOption) (*ListToDosResponse, error) {
+	out := new(ListToDosResponse)
+	err := c.cc.Invoke(ctx, "/v2.ToDoService/ListToDos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) CreateToDo(ctx context.Context, in *CreateToDoRequest, opts ...grpc.CallOption) (*ToDo, error) {
+	out := new(ToDo)
+	err := c.cc.Invoke(ctx, "/v2.ToDoService/CreateToDo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) UpdateToDo(ctx context.Context, in *UpdateToDoRequest, opts ...grpc.CallOption) (*ToDo, error) {
+	out := new(ToDo)
+	err := c.cc.Invoke(ctx, "/v2.ToDoService/UpdateToDo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) DeleteToDo(ctx context.Context, in *DeleteToDoRequest, opts ...grpc.CallOption) (*ToDo, error) {
+	out := new(ToDo)
+	err := c.cc.Invoke(ctx, "/v2.ToDoService/DeleteToDo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *toDoServiceClient) UndeleteToDo(ctx context.Context, in *UndeleteToDoRequest, opts ...grpc.CallOption) (*ToDo, error) {
+	out := new(ToDo)
+	err := c.cc.Invoke(ctx, "/v2.ToDoService/UndeleteToDo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ToDoServiceServer is the server API for ToDoService service.
+type ToDoServiceServer interface {
+	GetToDo(context.Context, *GetToDoRequest) (*ToDo, error)
+	ListToDos(context.Context, *ListToDosRequest) (*ListToDosResponse, error)
+	CreateToDo(context.Context, *CreateToDoRequest) (*ToDo, error)
+	UpdateToDo(context.Context, *UpdateToDoRequest) (*ToDo, error)
+	// DeleteToDo 把 ToDo 移到回收站，返回删除后的 ToDo
+	DeleteToDo(context.Context, *DeleteToDoRequest) (*ToDo, error)
+	UndeleteToDo(context.Context, *UndeleteToDoRequest) (*ToDo, error)
+}
+
+func RegisterToDoServiceServer(s *grpc.Server, srv ToDoServiceServer) {
+	s.RegisterService(&_ToDoService_serviceDesc, srv)
+}
+
+func _ToDoService_GetToDo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetToDoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).GetToDo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2.ToDoService/GetToDo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).GetToDo(ctx, req.(*GetToDoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_ListToDos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListToDosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).ListToDos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2.ToDoService/ListToDos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).ListToDos(ctx, req.(*ListToDosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_CreateToDo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateToDoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).CreateToDo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2.ToDoService/CreateToDo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).CreateToDo(ctx, req.(*CreateToDoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_UpdateToDo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateToDoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).UpdateToDo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2.ToDoService/UpdateToDo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).UpdateToDo(ctx, req.(*UpdateToDoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_DeleteToDo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteToDoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).DeleteToDo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2.ToDoService/DeleteToDo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).DeleteToDo(ctx, req.(*DeleteToDoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ToDoService_UndeleteToDo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteToDoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ToDoServiceServer).UndeleteToDo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2.ToDoService/UndeleteToDo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ToDoServiceServer).UndeleteToDo(ctx, req.(*UndeleteToDoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ToDoService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v2.ToDoService",
+	HandlerType: (*ToDoServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetToDo",
+			Handler:    _ToDoService_GetToDo_Handler,
+		},
+		{
+			MethodName: "ListToDos",
+			Handler:    _ToDoService_ListToDos_Handler,
+		},
+		{
+			MethodName: "CreateToDo",
+			Handler:    _ToDoService_CreateToDo_Handler,
+		},
+		{
+			MethodName: "UpdateToDo",
+			Handler:    _ToDoService_UpdateToDo_Handler,
+		},
+		{
+			MethodName: "DeleteToDo",
+			Handler:    _ToDoService_DeleteToDo_Handler,
+		},
+		{
+			MethodName: "UndeleteToDo",
+			Handler:    _ToDoService_UndeleteToDo_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "v2/todo_service.proto",
+}
@@ -0,0 +1,129 @@
+// Package auth 用 Bearer 令牌认证 gRPC 调用方，认证后的身份保存在请求的 context 中
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authorizationKey 是客户端发送令牌使用的 metadata key，gateway 把 Authorization 头转发为该 key
+const authorizationKey = "authorization"
+
+type principalKey struct{}
+
+// NewContext 返回保存了认证后身份的 context
+func NewContext(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext 返回认证后的身份，没有通过认证时 ok 为 false
+func FromContext(ctx context.Context) (principal string, ok bool) {
+	principal, ok = ctx.Value(principalKey{}).(string)
+	return principal, ok
+}
+
+// Tokens 保存每个令牌对应的用户，只保存令牌的 SHA-256 摘要
+type Tokens struct {
+	users map[[sha256.Size]byte]string
+}
+
+// NewTokens 根据用户到令牌的映射创建 Tokens
+func NewTokens(tokens map[string]string) (*Tokens, error) {
+	t := &Tokens{users: make(map[[sha256.Size]byte]string, len(tokens))}
+	for user, token := range tokens {
+		if len(user) == 0 || len(token) == 0 {
+			return nil, fmt.Errorf("user and token must not be empty")
+		}
+		sum := sha256.Sum256([]byte(token))
+		if other, ok := t.users[sum]; ok {
+			return nil, fmt.Errorf("users %s and %s have the same token", other, user)
+		}
+		t.users[sum] = user
+	}
+	return t, nil
+}
+
+// LoadTokens 从 JSON 文件读取用户到令牌的映射，例如 {"alice": "3f9c..."}
+// path 为空时没有任何令牌，所有调用方都是匿名的
+func LoadTokens(path string) (*Tokens, error) {
+	tokens := make(map[string]string)
+	if len(path) > 0 {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &tokens); err != nil {
+			return nil, fmt.Errorf("invalid token file %s: %v", path, err)
+		}
+	}
+	return NewTokens(tokens)
+}
+
+// authenticate 检查 ctx 中的令牌，通过认证时返回保存了身份的 context
+// 没有令牌的调用方是匿名的，令牌无效时返回 Unauthenticated
+func (t *Tokens) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	v := md.Get(authorizationKey)
+	if len(v) == 0 {
+		return ctx, nil
+	}
+
+	const prefix = "bearer "
+	if len(v[0]) <= len(prefix) || !strings.EqualFold(v[0][:len(prefix)], prefix) {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a Bearer token")
+	}
+	user, ok := t.users[sha256.Sum256([]byte(v[0][len(prefix):]))]
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return NewContext(ctx, user), nil
+}
+
+// UnaryServerInterceptor 返回认证一元调用的拦截器
+func (t *Tokens) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := t.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 返回认证流式调用的拦截器
+func (t *Tokens) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := t.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// ServerOptions 返回安装认证拦截器的 gRPC 服务器选项
+func (t *Tokens) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(t.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(t.StreamServerInterceptor()),
+	}
+}
+
+// serverStream 把认证后的 context 传给流式调用
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tokens, err := NewTokens(map[string]string{"alice": "alice-token", "bob": "bob-token"})
+	if err != nil {
+		t.Fatalf("NewTokens() error = %v", err)
+	}
+	interceptor := tokens.UnaryServerInterceptor()
+
+	tests := []struct {
+		name          string
+		md            metadata.MD
+		wantPrincipal string
+		wantCode      codes.Code
+	}{
+		{"no token", metadata.MD{}, "", codes.OK},
+		{"valid token", metadata.Pairs("authorization", "Bearer bob-token"), "bob", codes.OK},
+		{"lower case scheme", metadata.Pairs("authorization", "bearer alice-token"), "alice", codes.OK},
+		{"invalid token", metadata.Pairs("authorization", "Bearer mallory-token"), "", codes.Unauthenticated},
+		{"not a bearer token", metadata.Pairs("authorization", "Basic YWxpY2U6"), "", codes.Unauthenticated},
+		// 客户端自己声明的身份不会被采用
+		{"forwarded principal", metadata.Pairs("x-principal", "alice"), "", codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				got, _ = FromContext(ctx)
+				return nil, nil
+			}
+			_, err := interceptor(metadata.NewIncomingContext(context.Background(), tt.md), nil, &grpc.UnaryServerInfo{}, handler)
+			if status.Code(err) != tt.wantCode || got != tt.wantPrincipal {
+				t.Errorf("principal = %q, error = %v, want %q, %v", got, err, tt.wantPrincipal, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestLoadTokens(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "tokens.json")
+	if err := ioutil.WriteFile(path, []byte(`{"alice": "same", "bob": "same"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTokens(path); err == nil {
+		t.Error("LoadTokens() with a shared token error = nil, want error")
+	}
+
+	tokens, err := LoadTokens("")
+	if err != nil || len(tokens.users) != 0 {
+		t.Errorf("LoadTokens(\"\") = %v, %v, want no tokens", tokens, err)
+	}
+}
@@ -0,0 +1,294 @@
+// Package grpc 是调用 ToDoService 的 gRPC 客户端
+//
+// 与直接 grpc.Dial 相比，它在多个服务器之间轮询 (round_robin)，
+// 按 service config 中的重试策略重试幂等方法，为没有 deadline 的调用设置超时，
+// 保持连接活跃，并在每个请求中加入用户和令牌。
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/balancer/roundrobin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// DefaultTimeout 是没有 deadline 的调用默认的超时时间
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxAttempts 是幂等方法默认的最多尝试次数 (包括第一次)
+	DefaultMaxAttempts = 3
+	// DefaultKeepalive 是连接空闲多久后发送 keepalive ping，不能小于服务器允许的最小间隔 (10 秒)
+	DefaultKeepalive = 30 * time.Second
+
+	// 重试的退避时间
+	initialBackoff = 100 * time.Millisecond
+	maxBackoff     = 2 * time.Second
+
+	// serviceName 是 ToDoService 的完整服务名
+	serviceName = "v1.ToDoService"
+)
+
+// idempotentMethods 是重复调用结果相同、可以安全重试的方法
+//
+// Create 只有带 requestId 时才是幂等的，Complete 重复调用会跳过下一次，都不在其中
+var idempotentMethods = []string{
+	"Read", "ReadAll", "Update", "Delete", "ListRevisions", "ReadAt",
+	"ListOccurrences", "GetCalendarToken", "ExportICS",
+}
+
+// Client 是 v1.ToDoServiceClient，用完后需要调用 Close
+type Client struct {
+	v1.ToDoServiceClient
+	conn *grpc.ClientConn
+}
+
+// Close 关闭到服务器的连接
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Conn 返回底层连接，可以用来创建同一服务器上其它服务的客户端
+func (c *Client) Conn() *grpc.ClientConn {
+	return c.conn
+}
+
+type options struct {
+	token       string
+	timeout     time.Duration
+	maxAttempts int
+	keepalive   time.Duration
+	creds       credentials.TransportCredentials
+	dialOptions []grpc.DialOption
+}
+
+// Option 设置 Client 的可选参数
+type Option func(*options)
+
+// WithToken 在每个请求的 authorization metadata 中以 Bearer 令牌发送 token
+func WithToken(token string) Option {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+// WithTimeout 设置没有 deadline 的调用的超时时间，0 表示不设置; 流式调用不受影响
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithMaxAttempts 设置幂等方法最多尝试的次数，1 表示不重试
+func WithMaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// WithKeepalive 设置连接空闲多久后发送 keepalive ping，0 表示不发送
+func WithKeepalive(d time.Duration) Option {
+	return func(o *options) {
+		o.keepalive = d
+	}
+}
+
+// WithTransportCredentials 使用 TLS 等传输层认证，默认不加密
+func WithTransportCredentials(creds credentials.TransportCredentials) Option {
+	return func(o *options) {
+		o.creds = creds
+	}
+}
+
+// WithDialOptions 追加其它的 grpc.DialOption
+func WithDialOptions(opts ...grpc.DialOption) Option {
+	return func(o *options) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+// Dial 连接 target 上的服务器
+//
+// target 可以是 host:port、逗号分隔的多个 host:port，或 dns:///host:port;
+// 多个地址或 DNS 解析出的多个 IP 之间轮询。
+func Dial(ctx context.Context, target string, opts ...Option) (*Client, error) {
+	o := options{timeout: DefaultTimeout, maxAttempts: DefaultMaxAttempts, keepalive: DefaultKeepalive}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if len(strings.TrimSpace(target)) == 0 {
+		return nil, fmt.Errorf("empty target")
+	}
+	if !strings.Contains(target, ":///") {
+		target = staticScheme + ":///" + target
+	}
+
+	sc, err := serviceConfig(o.maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	dialOptions := []grpc.DialOption{grpc.WithDefaultServiceConfig(sc)}
+	if o.creds != nil {
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(o.creds))
+	} else {
+		dialOptions = append(dialOptions, grpc.WithInsecure())
+	}
+	if len(o.token) > 0 {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(&callCredentials{
+			token:  o.token,
+			secure: o.creds != nil,
+		}))
+	}
+	if o.keepalive > 0 {
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                o.keepalive,
+			Timeout:             o.keepalive / 3,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	// 超时在最外层，覆盖所有的重试
+	interceptors := []grpc.UnaryClientInterceptor{timeoutInterceptor(o.timeout)}
+	if !retrySupported() && o.maxAttempts > 1 {
+		interceptors = append(interceptors, retryInterceptor(o.maxAttempts))
+	}
+	dialOptions = append(dialOptions, grpc.WithUnaryInterceptor(chain(interceptors)))
+	dialOptions = append(dialOptions, o.dialOptions...)
+
+	conn, err := grpc.DialContext(ctx, target, dialOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{ToDoServiceClient: v1.NewToDoServiceClient(conn), conn: conn}, nil
+}
+
+// serviceConfig 生成轮询和重试幂等方法的 service config
+func serviceConfig(maxAttempts int) (string, error) {
+	type name struct {
+		Service string `json:"service"`
+		Method  string `json:"method"`
+	}
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+	type methodConfig struct {
+		Name        []name       `json:"name"`
+		RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+	}
+
+	mc := methodConfig{}
+	for _, m := range idempotentMethods {
+		mc.Name = append(mc.Name, name{Service: serviceName, Method: m})
+	}
+	// service config 要求 maxAttempts 至少为 2
+	if maxAttempts > 1 {
+		mc.RetryPolicy = &retryPolicy{
+			MaxAttempts:          maxAttempts,
+			InitialBackoff:       fmt.Sprintf("%gs", initialBackoff.Seconds()),
+			MaxBackoff:           fmt.Sprintf("%gs", maxBackoff.Seconds()),
+			BackoffMultiplier:    2,
+			RetryableStatusCodes: []string{"UNAVAILABLE"},
+		}
+	}
+
+	data, err := json.Marshal(struct {
+		LoadBalancingPolicy string         `json:"loadBalancingPolicy"`
+		MethodConfig        []methodConfig `json:"methodConfig"`
+	}{roundrobin.Name, []methodConfig{mc}})
+	return string(data), err
+}
+
+// retrySupported 返回 grpc 是否会执行 service config 中的重试策略
+//
+// 当前版本的 grpc 只有设置了环境变量 GRPC_GO_RETRY=on 时才会重试，否则由 retryInterceptor 按同样的策略重试
+func retrySupported() bool {
+	return strings.EqualFold(os.Getenv("GRPC_GO_RETRY"), "on")
+}
+
+// callCredentials 在每个请求的 metadata 中加入令牌，服务器根据令牌认证用户
+type callCredentials struct {
+	token  string
+	secure bool
+}
+
+func (c *callCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+// RequireTransportSecurity 只有使用 TLS 时才要求加密，服务默认不加密
+func (c *callCredentials) RequireTransportSecurity() bool {
+	return c.secure
+}
+
+// chain 把多个拦截器按顺序串联为一个，当前版本的 grpc 只支持设置一个
+func chain(interceptors []grpc.UnaryClientInterceptor) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		next := invoker
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, invoke := interceptors[i], next
+			next = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				return interceptor(ctx, method, req, reply, cc, invoke, opts...)
+			}
+		}
+		return next(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// timeoutInterceptor 为没有 deadline 的调用设置超时
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// retryInterceptor 按 service config 的策略重试幂等方法: 只重试 Unavailable，退避时间带随机抖动
+func retryInterceptor(maxAttempts int) grpc.UnaryClientInterceptor {
+	retryable := make(map[string]bool)
+	for _, m := range idempotentMethods {
+		retryable["/"+serviceName+"/"+m] = true
+	}
+
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !retryable[method] {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		backoff := initialBackoff
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt >= maxAttempts || status.Code(err) != codes.Unavailable {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(time.Duration(rand.Int63n(int64(backoff)))):
+			}
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+	}
+}
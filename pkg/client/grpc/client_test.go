@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+// fakeServer 的 Read 前 failures 次返回 Unavailable，Complete 总是返回 Unavailable
+type fakeServer struct {
+	v1.ToDoServiceServer
+
+	mu       sync.Mutex
+	calls    int
+	failures int
+	tokens   []string
+}
+
+func (s *fakeServer) Read(ctx context.Context, req *v1.ReadRequest) (*v1.ReadResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.tokens = append(s.tokens, md.Get("authorization")...)
+	if s.failures > 0 {
+		s.failures--
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+	return &v1.ReadResponse{Api: "v1", ToDo: &v1.ToDo{Id: req.Id}}, nil
+}
+
+func (s *fakeServer) Complete(context.Context, *v1.CompleteRequest) (*v1.CompleteResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	return nil, status.Error(codes.Unavailable, "try again")
+}
+
+func startServer(t *testing.T, s *fakeServer) (string, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	v1.RegisterToDoServiceServer(server, s)
+	go server.Serve(lis)
+	return lis.Addr().String(), server.Stop
+}
+
+func TestDial(t *testing.T) {
+	a, b := &fakeServer{failures: 1}, &fakeServer{failures: 1}
+	addrA, stopA := startServer(t, a)
+	defer stopA()
+	addrB, stopB := startServer(t, b)
+	defer stopB()
+
+	ctx := context.Background()
+	c, err := Dial(ctx, addrA+","+addrB, WithToken("alice-token"), WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer c.Close()
+
+	for i := int64(1); i <= 20; i++ {
+		resp, err := c.Read(ctx, &v1.ReadRequest{Api: "v1", Id: i})
+		if err != nil {
+			t.Fatalf("Client.Read() error = %v", err)
+		}
+		if resp.ToDo.Id != i {
+			t.Errorf("Client.Read() = %v, want ToDo %d", resp, i)
+		}
+	}
+
+	// 两个服务器都收到了请求，每个的第一次失败都被重试
+	if a.calls+b.calls != 22 || a.calls < 2 || b.calls < 2 {
+		t.Errorf("server calls = %d and %d, want 22 calls spread over both servers", a.calls, b.calls)
+	}
+	if a.tokens[0] != "Bearer alice-token" {
+		t.Errorf("authorization = %v, want Bearer alice-token", a.tokens)
+	}
+
+	// Complete 不是幂等的，不重试
+	calls := a.calls + b.calls
+	if _, err := c.Complete(ctx, &v1.CompleteRequest{Api: "v1", Id: 1}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Client.Complete() error = %v, want Unavailable", err)
+	}
+	if got := a.calls + b.calls - calls; got != 1 {
+		t.Errorf("Client.Complete() made %d calls, want 1", got)
+	}
+}
+
+func TestServiceConfig(t *testing.T) {
+	sc, err := serviceConfig(1)
+	if err != nil {
+		t.Fatalf("serviceConfig() error = %v", err)
+	}
+	want := `{"loadBalancingPolicy":"round_robin","methodConfig":[{"name":[{"service":"v1.ToDoService","method":"Read"},`
+	if len(sc) < len(want) || sc[:len(want)] != want {
+		t.Errorf("serviceConfig() = %s, want prefix %s", sc, want)
+	}
+}
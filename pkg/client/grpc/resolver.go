@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// staticScheme 是固定地址列表的 resolver scheme，例如 static:///10.0.0.1:9090,10.0.0.2:9090
+const staticScheme = "static"
+
+func init() {
+	resolver.Register(staticBuilder{})
+}
+
+// staticBuilder 把 target 中逗号分隔的地址作为服务器列表，由 round_robin 在它们之间分配请求
+type staticBuilder struct{}
+
+func (staticBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOption) (resolver.Resolver, error) {
+	var addrs []resolver.Address
+	for _, a := range strings.Split(target.Endpoint, ",") {
+		if a = strings.TrimSpace(a); len(a) > 0 {
+			addrs = append(addrs, resolver.Address{Addr: a})
+		}
+	}
+	cc.UpdateState(resolver.State{Addresses: addrs})
+	return staticResolver{}, nil
+}
+
+func (staticBuilder) Scheme() string {
+	return staticScheme
+}
+
+// staticResolver 的地址不会变化，不需要重新解析
+type staticResolver struct{}
+
+func (staticResolver) ResolveNow(resolver.ResolveNowOption) {}
+
+func (staticResolver) Close() {}
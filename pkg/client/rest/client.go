@@ -0,0 +1,274 @@
+// Package rest 是通过 REST 网关调用 ToDoService 的 Go 客户端
+//
+// Client 的方法与 v1.ToDoServiceClient 的签名相同，调用方可以在 gRPC 和 REST 之间切换;
+// grpc.CallOption 参数会被忽略。网关返回的错误被转换回 gRPC status 错误，
+// 可以用 status.FromError 取得错误码和详情。
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	// 注册错误详情的类型，解析 details 时需要
+	_ "google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// DefaultRetries 是请求失败后默认的重试次数
+	DefaultRetries = 2
+	// DefaultBackoff 是第一次重试前默认的等待时间，之后每次加倍
+	DefaultBackoff = 100 * time.Millisecond
+
+	// maxBackoff 是两次重试之间最长的等待时间
+	maxBackoff = 5 * time.Second
+)
+
+// Client 通过 REST 网关调用 ToDoService，可以被多个 goroutine 同时使用
+type Client struct {
+	base    string
+	http    *http.Client
+	header  http.Header
+	retries int
+	backoff time.Duration
+}
+
+// Option 设置 Client 的可选参数
+type Option func(*Client)
+
+// WithHTTPClient 使用指定的 http.Client 发送请求，默认为 http.DefaultClient
+func WithHTTPClient(c *http.Client) Option {
+	return func(client *Client) {
+		client.http = c
+	}
+}
+
+// WithHeader 在每个请求中加入 HTTP 头
+func WithHeader(key, value string) Option {
+	return func(client *Client) {
+		client.header.Set(key, value)
+	}
+}
+
+// WithToken 在每个请求中以 Bearer 令牌发送 token，服务器根据令牌认证用户
+func WithToken(token string) Option {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithRetries 设置重试次数和第一次重试前的等待时间，retries 为 0 时不重试
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(client *Client) {
+		client.retries = retries
+		client.backoff = backoff
+	}
+}
+
+// New 创建访问 baseURL (例如 http://localhost:8080) 上网关的客户端
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %v", baseURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return nil, fmt.Errorf("invalid base URL %q, must be http(s)://host[:port]", baseURL)
+	}
+
+	c := &Client{
+		base:    strings.TrimSuffix(baseURL, "/"),
+		http:    http.DefaultClient,
+		header:  make(http.Header),
+		retries: DefaultRetries,
+		backoff: DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// do 发送请求并把 JSON 响应解析到 out
+//
+// idempotent 为 true 时，连接失败和 503 (Unavailable) 会按指数退避重试，直到 ctx 结束
+func (c *Client) do(ctx context.Context, method, path string, idempotent bool, in, out proto.Message) error {
+	var body []byte
+	if in != nil {
+		s, err := (&jsonpb.Marshaler{}).MarshalToString(in)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to marshal request: %v", err)
+		}
+		body = []byte(s)
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, method, path, body, out)
+		if err == nil || !idempotent || attempt >= c.retries || status.Code(err) != codes.Unavailable {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// send 发送一次请求
+func (c *Client) send(ctx context.Context, method, path string, body []byte, out proto.Message) error {
+	req, err := http.NewRequest(method, c.base+path, bytes.NewReader(body))
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	req = req.WithContext(ctx)
+	for k, v := range c.header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		return statusError(resp.StatusCode, data)
+	}
+	if err := (&jsonpb.Unmarshaler{AllowUnknownFields: true}).Unmarshal(bytes.NewReader(data), out); err != nil {
+		return status.Errorf(codes.Internal, "failed to unmarshal response: %v", err)
+	}
+	return nil
+}
+
+// statusError 把网关的错误响应 {"error": {"code": ..., "message": ..., "details": [...]}} 转换为 gRPC status 错误
+func statusError(statusCode int, body []byte) error {
+	var e struct {
+		Error struct {
+			Code    int32             `json:"code"`
+			Message string            `json:"message"`
+			Details []json.RawMessage `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil || (e.Error.Code == 0 && len(e.Error.Message) == 0) {
+		// 不是网关的错误格式，例如前面代理返回的错误页
+		return status.Errorf(httpCode(statusCode), "HTTP %d: %s", statusCode, strings.TrimSpace(string(body)))
+	}
+
+	s := &spb.Status{Code: e.Error.Code, Message: e.Error.Message}
+	for _, d := range e.Error.Details {
+		detail := &any.Any{}
+		if err := jsonpb.Unmarshal(bytes.NewReader(d), detail); err != nil {
+			continue
+		}
+		s.Details = append(s.Details, detail)
+	}
+	return status.ErrorProto(s)
+}
+
+// httpCode 把 HTTP 状态码转换为 gRPC 错误码
+func httpCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	}
+	return codes.Unknown
+}
+
+// Create 新建 ToDo; 只有设置了 requestId 时才会重试，服务器用它去重
+func (c *Client) Create(ctx context.Context, in *v1.CreateRequest, _ ...grpc.CallOption) (*v1.CreateResponse, error) {
+	out := &v1.CreateResponse{}
+	if err := c.do(ctx, http.MethodPost, "/v1/todo", len(in.RequestId) > 0, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Read 读取 ToDo
+func (c *Client) Read(ctx context.Context, in *v1.ReadRequest, _ ...grpc.CallOption) (*v1.ReadResponse, error) {
+	out := &v1.ReadResponse{}
+	path := fmt.Sprintf("/v1/todo/%d?%s", in.Id, url.Values{"showDeleted": {strconv.FormatBool(in.ShowDeleted)}}.Encode())
+	if err := c.do(ctx, http.MethodGet, path, true, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReadAll 读取所有 ToDo
+func (c *Client) ReadAll(ctx context.Context, in *v1.ReadAllRequest, _ ...grpc.CallOption) (*v1.ReadAllResponse, error) {
+	out := &v1.ReadAllResponse{}
+	path := "/v1/todo/all?" + url.Values{"showDeleted": {strconv.FormatBool(in.ShowDeleted)}}.Encode()
+	if err := c.do(ctx, http.MethodGet, path, true, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Update 更新 ToDo
+func (c *Client) Update(ctx context.Context, in *v1.UpdateRequest, _ ...grpc.CallOption) (*v1.UpdateResponse, error) {
+	out := &v1.UpdateResponse{}
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/v1/todo/%d", in.ToDo.GetId()), true, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete 把 ToDo 移入回收站
+func (c *Client) Delete(ctx context.Context, in *v1.DeleteRequest, _ ...grpc.CallOption) (*v1.DeleteResponse, error) {
+	out := &v1.DeleteResponse{}
+	if err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/v1/todo/%d", in.Id), true, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Complete 完成 ToDo 当前的一次; 重复调用会跳过下一次，所以不重试
+func (c *Client) Complete(ctx context.Context, in *v1.CompleteRequest, _ ...grpc.CallOption) (*v1.CompleteResponse, error) {
+	out := &v1.CompleteResponse{}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/v1/todo/%d:complete", in.Id), false, in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,98 @@
+package rest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+func TestClient_Read(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"code":14,"status":"UNAVAILABLE","message":"connection refused"}}`))
+			return
+		}
+		if r.URL.Path != "/v1/todo/1" || r.URL.Query().Get("showDeleted") != "true" || r.Header.Get("Authorization") != "Bearer alice-token" {
+			t.Errorf("unexpected request %s %s %v", r.Method, r.URL, r.Header)
+		}
+		w.Write([]byte(`{"api":"v1","toDo":{"id":"1","title":"title","reminder":"2019-05-06T07:00:00Z","unknownField":1}}`))
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, WithToken("alice-token"), WithRetries(1, time.Millisecond))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	got, err := c.Read(context.Background(), &v1.ReadRequest{Api: "v1", Id: 1, ShowDeleted: true})
+	if err != nil {
+		t.Fatalf("Client.Read() error = %v", err)
+	}
+	if calls != 2 || got.ToDo.Id != 1 || got.ToDo.Title != "title" || got.ToDo.Reminder.Seconds != 1557126000 {
+		t.Errorf("Client.Read() = %v after %d calls, want ToDo 1 after a retry", got, calls)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		switch r.Method {
+		case http.MethodPost:
+			// Create 没有 requestId，不应重试
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"code":14,"message":"try again"}}`))
+		case http.MethodPut:
+			if string(body) != `{"api":"v1","toDo":{"id":"2"}}` {
+				t.Errorf("Update body = %s", body)
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":3,"status":"INVALID_ARGUMENT","message":"invalid ToDo","details":[` +
+				`{"@type":"type.googleapis.com/google.rpc.BadRequest","fieldViolations":[{"field":"toDo.title","description":"must not be empty"}]}]}}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("<html>bad gateway</html>"))
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL+"/", WithRetries(0, 0))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := context.Background()
+
+	_, err = c.Create(ctx, &v1.CreateRequest{Api: "v1", ToDo: &v1.ToDo{Title: "title"}})
+	if status.Code(err) != codes.Unavailable || calls != 1 {
+		t.Errorf("Client.Create() error = %v after %d calls, want Unavailable without retry", err, calls)
+	}
+
+	_, err = c.Update(ctx, &v1.UpdateRequest{Api: "v1", ToDo: &v1.ToDo{Id: 2}})
+	s := status.Convert(err)
+	if s.Code() != codes.InvalidArgument || s.Message() != "invalid ToDo" || len(s.Details()) != 1 {
+		t.Fatalf("Client.Update() error = %v, want InvalidArgument with one detail", err)
+	}
+	if d, ok := s.Details()[0].(*errdetails.BadRequest); !ok || d.FieldViolations[0].Field != "toDo.title" {
+		t.Errorf("Client.Update() error details = %v, want field violation of toDo.title", s.Details())
+	}
+
+	if _, err = c.Delete(ctx, &v1.DeleteRequest{Api: "v1", Id: 3}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Client.Delete() error = %v, want Unavailable", err)
+	}
+
+	if _, err := New("localhost:8080"); err == nil {
+		t.Errorf("New() error = nil, want error for URL without scheme")
+	}
+}
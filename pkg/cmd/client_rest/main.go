@@ -1,133 +0,0 @@
-package main
-
-import (
-	"encoding/json"
-	"flag"
-	"fmt"
-	"io/ioutil"
-	"log"
-	"net/http"
-	"strings"
-	"time"
-)
-
-func main() {
-	address := flag.String("server", "http://localhost:8080", "HTTP gateway url, e.g. http://localhost:8080")
-	flag.Parse()
-
-	t := time.Now().In(time.UTC)
-	prefix := t.Format(time.RFC3339Nano)
-
-	var body string
-
-	// Create
-	resp, err := http.Post(*address+"/v1/todo", "application/json", strings.NewReader(fmt.Sprintf(`
-		{
-			"api":"v1",
-			"toDo": {
-				"title":"title (%s)",
-				"description":"description (%s)",
-				"reminder":"%s"
-			}
-		}
-	`, prefix, prefix, prefix)))
-	if err != nil {
-		log.Fatalf("failed to call Create method: %v", err)
-	}
-
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
-	resp.Body.Close()
-
-	if err != nil {
-		body = fmt.Sprintf("failed read Create response body: %v", err)
-	} else {
-		body = string(bodyBytes)
-	}
-	log.Printf("Create response: Code=%d, Body=%s\n\n", resp.StatusCode, body)
-
-	// 解析创建的 ToDo 的 ID
-	var created struct {
-		API string `json:"api"`
-		ID  string `json:"id"`
-	}
-	err = json.Unmarshal(bodyBytes, &created)
-	if err != nil {
-		log.Fatalf("failed to unmarshall JSON response of Create method: %v", err)
-	}
-
-	// Read
-	resp, err = http.Get(fmt.Sprintf("%s%s/%s", *address, "/v1/dodo", created.ID))
-	if err != nil {
-		log.Fatalf("failed to call Read method: %v", err)
-	}
-	bodyBytes, err = ioutil.ReadAll(resp.Body)
-	resp.Body.Close()
-
-	if err != nil {
-		body = fmt.Sprintf("failed to read Read Response body: %v", err)
-	} else {
-		body = string(bodyBytes)
-	}
-	log.Printf("Read response: Code=%d, Body=%s\n\n", resp.StatusCode, body)
-
-	// Update
-	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s%s/%s", *address, "/v1/todo", created.ID), strings.NewReader(fmt.Sprintf(`
-		{
-			"api":"v1",
-			"toDo": {
-				"title":"title (%s)",
-				"description":"description (%s) + updated",
-				"reminder":"%s"
-			}
-		}
-	`, prefix, prefix, prefix)))
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err = http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("failed to call Update method: %v", err)
-	}
-
-	bodyBytes, err = ioutil.ReadAll(resp.Body)
-	resp.Body.Close()
-
-	if err != nil {
-		body = fmt.Sprintf("failed read Update response body: %v", err)
-	} else {
-		body = string(bodyBytes)
-	}
-	log.Printf("Update response: Code=%d, Body=%s\n\n", resp.StatusCode, body)
-
-	// ReadAll
-	resp, err = http.Get(*address + "/v1/todo/all")
-	if err != nil {
-		log.Fatalf("failed to call ReadAll method: %v", err)
-	}
-
-	bodyBytes, err = ioutil.ReadAll(resp.Body)
-	resp.Body.Close()
-
-	if err != nil {
-		body = fmt.Sprintf("failed read ReadAll response body: %v", err)
-	} else {
-		body = string(bodyBytes)
-	}
-	log.Printf("ReadAll response: Code=%d, Body=%s", resp.StatusCode, body)
-
-	// Delete
-	req, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%s%s/%s", *address, "/v1/todo", created.ID), nil)
-	resp, err = http.DefaultClient.Do(req)
-	if err != nil {
-		log.Fatalf("failed to call Delete method: %v", err)
-	}
-
-	bodyBytes, err = ioutil.ReadAll(resp.Body)
-	resp.Body.Close()
-
-	if err != nil {
-		body = fmt.Sprintf("failed read Delete response body: %v", err)
-	} else {
-		body = string(bodyBytes)
-	}
-	log.Printf("delete response: Code=%d, Body=%s\n\n", resp.StatusCode, body)
-}
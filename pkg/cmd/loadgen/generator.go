@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	apiVersion = "v1"
+
+	// maxRate 是 -rate 的上限，请求的间隔不能小于 1ns
+	maxRate = float64(time.Second)
+)
+
+// client 是压测调用的 ToDoService 方法，pkg/client 中的 gRPC 和 REST 客户端都实现了它
+type client interface {
+	Create(ctx context.Context, in *v1.CreateRequest, opts ...grpc.CallOption) (*v1.CreateResponse, error)
+	Read(ctx context.Context, in *v1.ReadRequest, opts ...grpc.CallOption) (*v1.ReadResponse, error)
+	ReadAll(ctx context.Context, in *v1.ReadAllRequest, opts ...grpc.CallOption) (*v1.ReadAllResponse, error)
+	Update(ctx context.Context, in *v1.UpdateRequest, opts ...grpc.CallOption) (*v1.UpdateResponse, error)
+	Delete(ctx context.Context, in *v1.DeleteRequest, opts ...grpc.CallOption) (*v1.DeleteResponse, error)
+	Complete(ctx context.Context, in *v1.CompleteRequest, opts ...grpc.CallOption) (*v1.CompleteResponse, error)
+}
+
+// load 描述压测的负载
+type load struct {
+	mix mix
+	// 同时发送请求的 worker 数
+	concurrency int
+	// 每秒发送的请求数，0 表示每个 worker 收到响应后立即发送下一个请求，不能超过 maxRate
+	rate float64
+	// 压测持续的时间和最多发送的请求数，先到者结束压测; 0 表示不限制
+	duration time.Duration
+	requests int
+	// 每个请求的超时时间
+	timeout time.Duration
+}
+
+// generator 按负载调用服务，并记录每次调用的结果
+type generator struct {
+	client client
+	load   load
+	ids    idPool
+	seq    int64
+	stats  *stats
+}
+
+// run 执行压测直到 ctx 结束、达到持续时间或请求数，返回统计结果
+func run(ctx context.Context, c client, l load) *stats {
+	g := &generator{client: c, load: l, stats: newStats()}
+	if l.duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.duration)
+		defer cancel()
+	}
+
+	jobs := make(chan time.Time)
+	go g.dispatch(ctx, jobs)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < l.concurrency; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for intended := range jobs {
+				g.call(r, intended)
+			}
+		}(start.UnixNano() + int64(i))
+	}
+	wg.Wait()
+	g.stats.elapsed = time.Since(start)
+	return g.stats
+}
+
+// dispatch 按速率分发请求的计划发送时间，结束时关闭 jobs; rate 为 0 时计划发送时间为零值
+// 计划发送时间按固定的时间表计算，所有 worker 都忙时积压的请求在 worker 空闲后立即发送，不会推迟之后的计划
+func (g *generator) dispatch(ctx context.Context, jobs chan<- time.Time) {
+	defer close(jobs)
+
+	start := time.Now()
+	for n := 0; g.load.requests <= 0 || n < g.load.requests; n++ {
+		var intended time.Time
+		if g.load.rate > 0 {
+			intended = start.Add(time.Duration(float64(n) * float64(time.Second) / g.load.rate))
+			if d := time.Until(intended); d > 0 {
+				timer := time.NewTimer(d)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case jobs <- intended:
+		}
+	}
+}
+
+// call 随机选择一个操作并调用; 需要已有 ToDo 的操作在还没有 ToDo 时改为 create
+// 延迟从计划发送时间开始计算，包括因为 worker 都忙而等待的时间 (避免 coordinated omission)
+func (g *generator) call(r *rand.Rand, intended time.Time) {
+	op := g.load.mix.pick(r)
+	id := int64(0)
+	if op != opCreate && op != opReadAll {
+		if op == opDelete {
+			id = g.ids.take(r)
+		} else {
+			id = g.ids.get(r)
+		}
+		if id == 0 {
+			op = opCreate
+		}
+	}
+
+	// 正在进行的请求不随压测结束而取消，只受自己的超时限制
+	ctx, cancel := context.WithTimeout(context.Background(), g.load.timeout)
+	defer cancel()
+
+	start := intended
+	if start.IsZero() {
+		start = time.Now()
+	}
+	err := g.invoke(ctx, op, id)
+	g.stats.record(op, time.Since(start), status.Code(err))
+}
+
+func (g *generator) invoke(ctx context.Context, op string, id int64) error {
+	switch op {
+	case opCreate:
+		resp, err := g.client.Create(ctx, &v1.CreateRequest{Api: apiVersion, ToDo: g.newToDo(0)})
+		if err == nil {
+			g.ids.add(resp.Id)
+		}
+		return err
+	case opRead:
+		_, err := g.client.Read(ctx, &v1.ReadRequest{Api: apiVersion, Id: id})
+		return err
+	case opReadAll:
+		_, err := g.client.ReadAll(ctx, &v1.ReadAllRequest{Api: apiVersion})
+		return err
+	case opUpdate:
+		_, err := g.client.Update(ctx, &v1.UpdateRequest{Api: apiVersion, ToDo: g.newToDo(id)})
+		return err
+	case opDelete:
+		_, err := g.client.Delete(ctx, &v1.DeleteRequest{Api: apiVersion, Id: id})
+		return err
+	case opComplete:
+		_, err := g.client.Complete(ctx, &v1.CompleteRequest{Api: apiVersion, Id: id})
+		return err
+	}
+	return fmt.Errorf("unknown operation %q", op)
+}
+
+// newToDo 生成一个一小时后提醒的 ToDo
+func (g *generator) newToDo(id int64) *v1.ToDo {
+	n := atomic.AddInt64(&g.seq, 1)
+	reminder, _ := ptypes.TimestampProto(time.Now().Add(time.Hour))
+	return &v1.ToDo{
+		Id:          id,
+		Title:       fmt.Sprintf("loadgen %d", n),
+		Description: "created by loadgen",
+		Reminder:    reminder,
+	}
+}
+
+// idPool 是压测中创建的、还没有删除的 ToDo
+type idPool struct {
+	mu  sync.Mutex
+	ids []int64
+}
+
+func (p *idPool) add(id int64) {
+	p.mu.Lock()
+	p.ids = append(p.ids, id)
+	p.mu.Unlock()
+}
+
+// get 随机返回一个 ID，没有时返回 0
+func (p *idPool) get(r *rand.Rand) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.ids) == 0 {
+		return 0
+	}
+	return p.ids[r.Intn(len(p.ids))]
+}
+
+// take 随机取出一个 ID，没有时返回 0
+func (p *idPool) take(r *rand.Rand) int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.ids) == 0 {
+		return 0
+	}
+	i := r.Intn(len(p.ids))
+	id := p.ids[i]
+	p.ids[i] = p.ids[len(p.ids)-1]
+	p.ids = p.ids[:len(p.ids)-1]
+	return id
+}
@@ -0,0 +1,146 @@
+// loadgen 按配置的操作比例和速率压测 ToDo 服务，输出吞吐量、延迟分位数和错误统计
+//
+// 例如以 50 req/s 压测 30 秒:
+//
+//	go run ./pkg/cmd/loadgen -server=localhost:9090 -mix=create=2,read=5,update=2,delete=1 -rate=50 -duration=30s
+//
+// 指定 -rate 时延迟从计划发送时间开始计算，服务变慢造成的请求积压也计入延迟。
+//
+// 在 CI 中可以用 -max-error-rate 让错误过多时以非 0 退出码结束。
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	grpcclient "go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/client/grpc"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/client/rest"
+)
+
+const (
+	transportGRPC = "grpc"
+	transportREST = "rest"
+
+	outputText = "text"
+	outputJSON = "json"
+)
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Ctrl-C 提前结束压测，仍然输出已有的结果
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	os.Exit(runMain(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// runMain 解析参数、执行压测并返回进程的退出码
+func runMain(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	transport := fs.String("transport", transportGRPC, "Transport: grpc or rest")
+	server := fs.String("server", "", "gRPC target (host:port, comma separated list or dns:///host:port) or REST gateway URL (default localhost:9090 or http://localhost:8080)")
+	token := fs.String("token", "", "Bearer token sent with every request")
+	mixFlag := fs.String("mix", "create=2,read=5,readall=1,update=2,delete=1", "Weights of operations: "+fmt.Sprint(operations))
+	concurrency := fs.Int("concurrency", 10, "Number of concurrent workers")
+	rate := fs.Float64("rate", 0, "Target requests per second over all workers, at most 1e9; 0 sends as fast as the workers can. Latency is measured from the scheduled send time")
+	duration := fs.Duration("duration", 10*time.Second, "How long to run, 0 runs until -requests is reached")
+	requests := fs.Int("requests", 0, "Stop after this many requests, 0 means no limit")
+	timeout := fs.Duration("timeout", 5*time.Second, "Timeout of each request")
+	output := fs.String("output", outputText, "Report format: text or json")
+	maxErrorRate := fs.Float64("max-error-rate", 1, "Exit with status 1 if the fraction of failed requests exceeds this value")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	m, err := parseMix(*mixFlag)
+	if err != nil {
+		fmt.Fprintf(stderr, "loadgen: %v\n", err)
+		return 2
+	}
+	switch {
+	case *concurrency <= 0:
+		err = fmt.Errorf("concurrency must be positive")
+	case !(*rate >= 0 && *rate <= maxRate):
+		err = fmt.Errorf("rate must be between 0 and %g", maxRate)
+	case *duration <= 0 && *requests <= 0:
+		err = fmt.Errorf("either duration or requests must be positive")
+	case *timeout <= 0:
+		err = fmt.Errorf("timeout must be positive")
+	case *output != outputText && *output != outputJSON:
+		err = fmt.Errorf("invalid output %q, must be text or json", *output)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "loadgen: %v\n", err)
+		return 2
+	}
+
+	var c client
+	switch *transport {
+	case transportGRPC:
+		if len(*server) == 0 {
+			*server = "localhost:9090"
+		}
+		// 压测需要看到每次调用的真实结果，不自动重试
+		gc, err := grpcclient.Dial(ctx, *server, grpcclient.WithToken(*token),
+			grpcclient.WithMaxAttempts(1), grpcclient.WithTimeout(0))
+		if err != nil {
+			fmt.Fprintf(stderr, "loadgen: failed to connect to %s: %v\n", *server, err)
+			return 1
+		}
+		defer gc.Close()
+		c = gc
+	case transportREST:
+		if len(*server) == 0 {
+			*server = "http://localhost:8080"
+		}
+		opts := []rest.Option{rest.WithRetries(0, 0)}
+		if len(*token) > 0 {
+			opts = append(opts, rest.WithToken(*token))
+		}
+		if c, err = rest.New(*server, opts...); err != nil {
+			fmt.Fprintf(stderr, "loadgen: %v\n", err)
+			return 2
+		}
+	default:
+		fmt.Fprintf(stderr, "loadgen: invalid transport %q, must be grpc or rest\n", *transport)
+		return 2
+	}
+
+	l := load{mix: m, concurrency: *concurrency, rate: *rate, duration: *duration, requests: *requests, timeout: *timeout}
+	r := run(ctx, c, l).report()
+	r.Transport, r.Server, r.Mix, r.Concurrency, r.TargetRate = *transport, *server, m.String(), *concurrency, *rate
+
+	if *output == outputJSON {
+		err = r.writeJSON(stdout)
+	} else {
+		err = r.writeText(stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "loadgen: failed to write report: %v\n", err)
+		return 1
+	}
+
+	if r.Requests == 0 {
+		fmt.Fprintln(stderr, "loadgen: no requests were sent")
+		return 1
+	}
+	if r.errorRate() > *maxErrorRate {
+		fmt.Fprintf(stderr, "loadgen: %d of %d requests failed\n", r.Errors, r.Requests)
+		return 1
+	}
+	return 0
+}
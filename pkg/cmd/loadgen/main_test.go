@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+// memoryServer 是保存在内存中的 ToDoService，Complete 总是失败
+type memoryServer struct {
+	v1.ToDoServiceServer
+
+	mu    sync.Mutex
+	next  int64
+	todos map[int64]*v1.ToDo
+}
+
+func (s *memoryServer) Create(_ context.Context, req *v1.CreateRequest) (*v1.CreateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	s.todos[s.next] = req.ToDo
+	return &v1.CreateResponse{Api: apiVersion, Id: s.next}, nil
+}
+
+func (s *memoryServer) Read(_ context.Context, req *v1.ReadRequest) (*v1.ReadResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	todo, ok := s.todos[req.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "ToDo with ID='%d' is not found", req.Id)
+	}
+	return &v1.ReadResponse{Api: apiVersion, ToDo: todo}, nil
+}
+
+func (s *memoryServer) Delete(_ context.Context, req *v1.DeleteRequest) (*v1.DeleteResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.todos, req.Id)
+	return &v1.DeleteResponse{Api: apiVersion, Deleted: 1}, nil
+}
+
+func (s *memoryServer) Complete(context.Context, *v1.CompleteRequest) (*v1.CompleteResponse, error) {
+	return nil, status.Error(codes.FailedPrecondition, "not recurring")
+}
+
+func TestRunMain(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	v1.RegisterToDoServiceServer(server, &memoryServer{todos: make(map[int64]*v1.ToDo)})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-server=" + lis.Addr().String(), "-mix=create=3,read=3,delete=1,complete=1", "-requests=200", "-duration=0",
+		"-concurrency=1", "-output=json", "-max-error-rate=0.5"}
+	if code := runMain(context.Background(), args, &stdout, &stderr); code != 0 {
+		t.Fatalf("runMain() = %d, stderr: %s", code, stderr.String())
+	}
+
+	var r report
+	if err := json.Unmarshal(stdout.Bytes(), &r); err != nil {
+		t.Fatalf("invalid JSON report %s: %v", stdout.String(), err)
+	}
+	if r.Requests != 200 || r.Throughput <= 0 || r.Latency.Max < r.Latency.P50 {
+		t.Errorf("report = %+v, want 200 requests with throughput and latency", r)
+	}
+	// 只有一个 worker，读取的 ToDo 不会被同时删除，只有 Complete 失败
+	var completes int
+	for _, op := range r.Operations {
+		if op.Operation == opComplete {
+			completes = op.Requests
+		}
+	}
+	if r.Errors != completes || r.ErrorCodes["FailedPrecondition"] != completes {
+		t.Errorf("errors = %d %v, want %d FailedPrecondition errors of complete", r.Errors, r.ErrorCodes, completes)
+	}
+
+	// 错误率超过限制时退出码为 1
+	args = []string{"-server=" + lis.Addr().String(), "-mix=create,complete", "-requests=50", "-duration=0", "-max-error-rate=0"}
+	stdout.Reset()
+	if code := runMain(context.Background(), args, &stdout, &stderr); code != 1 {
+		t.Errorf("runMain() = %d, want 1 when requests fail", code)
+	}
+}
+
+func TestRate(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	v1.RegisterToDoServiceServer(server, &memoryServer{todos: make(map[int64]*v1.ToDo)})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-server=" + lis.Addr().String(), "-mix=create", "-rate=100", "-duration=300ms"}
+	start := time.Now()
+	if code := runMain(context.Background(), args, &stdout, &stderr); code != 0 {
+		t.Fatalf("runMain() = %d, stderr: %s", code, stderr.String())
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("runMain() took %v, want about 300ms", elapsed)
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("OPERATION")) {
+		t.Errorf("report = %s, want text table", stdout.String())
+	}
+}
+
+func TestParseMix(t *testing.T) {
+	m, err := parseMix("Read=3, create ,delete=0")
+	if err != nil {
+		t.Fatalf("parseMix() error = %v", err)
+	}
+	if got := m.String(); got != "create=1,read=3" {
+		t.Errorf("parseMix() = %s, want create=1,read=3", got)
+	}
+
+	for _, s := range []string{"", "delete=0", "fly=1", "read=-1", "read=1,read=2"} {
+		if _, err := parseMix(s); err == nil {
+			t.Errorf("parseMix(%q) error = nil, want error", s)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	if got := percentile(sorted, 50); got != 50*time.Millisecond {
+		t.Errorf("percentile(50) = %v, want 50ms", got)
+	}
+	if got := percentile(sorted, 99); got != 99*time.Millisecond {
+		t.Errorf("percentile(99) = %v, want 99ms", got)
+	}
+	if got := percentile(sorted[:1], 90); got != time.Millisecond {
+		t.Errorf("percentile(90) of one = %v, want 1ms", got)
+	}
+}
+
+// slowClient 的每次调用都需要 delay
+type slowClient struct {
+	client
+	delay time.Duration
+}
+
+func (c slowClient) Create(context.Context, *v1.CreateRequest, ...grpc.CallOption) (*v1.CreateResponse, error) {
+	time.Sleep(c.delay)
+	return &v1.CreateResponse{Api: apiVersion, Id: 1}, nil
+}
+
+func TestRunMeasuresFromIntendedTime(t *testing.T) {
+	m, err := parseMix("create")
+	if err != nil {
+		t.Fatalf("parseMix() error = %v", err)
+	}
+
+	// 计划每 10ms 发送一个请求，但唯一的 worker 每个请求需要 50ms，积压的等待时间要计入延迟
+	l := load{mix: m, concurrency: 1, rate: 100, requests: 10, timeout: time.Second}
+	r := run(context.Background(), slowClient{delay: 50 * time.Millisecond}, l).report()
+	if r.Requests != 10 || r.Latency.Max < 400 {
+		t.Errorf("report = %+v, want 10 requests with max latency of at least 400ms", r)
+	}
+}
+
+func TestRunMainInvalidRate(t *testing.T) {
+	for _, rate := range []string{"-1", "2e9", "NaN"} {
+		var stdout, stderr bytes.Buffer
+		if code := runMain(context.Background(), []string{"-rate=" + rate}, &stdout, &stderr); code != 2 {
+			t.Errorf("runMain(-rate=%s) = %d, want 2", rate, code)
+		}
+	}
+}
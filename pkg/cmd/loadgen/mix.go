@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 压测支持的操作
+const (
+	opCreate   = "create"
+	opRead     = "read"
+	opReadAll  = "readall"
+	opUpdate   = "update"
+	opDelete   = "delete"
+	opComplete = "complete"
+)
+
+var operations = []string{opCreate, opRead, opReadAll, opUpdate, opDelete, opComplete}
+
+// mix 是各种操作的权重，按权重随机选择每次调用的操作
+type mix struct {
+	ops     []string
+	weights []int
+	total   int
+}
+
+// parseMix 解析 "create=5,read=3,readall=1" 格式的权重，没有出现的操作权重为 0
+func parseMix(s string) (mix, error) {
+	var m mix
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); len(part) == 0 {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		op := strings.ToLower(strings.TrimSpace(kv[0]))
+		if !validOp(op) {
+			return m, fmt.Errorf("unknown operation %q, must be one of %s", kv[0], strings.Join(operations, ", "))
+		}
+		if seen[op] {
+			return m, fmt.Errorf("operation %q given twice", op)
+		}
+		seen[op] = true
+
+		weight := 1
+		if len(kv) == 2 {
+			w, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+			if err != nil || w < 0 {
+				return m, fmt.Errorf("invalid weight %q of %s", kv[1], op)
+			}
+			weight = w
+		}
+		if weight > 0 {
+			m.ops = append(m.ops, op)
+			m.weights = append(m.weights, weight)
+			m.total += weight
+		}
+	}
+	if m.total == 0 {
+		return m, fmt.Errorf("mix %q has no operation with a positive weight", s)
+	}
+	return m, nil
+}
+
+func validOp(op string) bool {
+	for _, o := range operations {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// pick 按权重随机选择一个操作
+func (m mix) pick(r *rand.Rand) string {
+	n := r.Intn(m.total)
+	for i, w := range m.weights {
+		if n < w {
+			return m.ops[i]
+		}
+		n -= w
+	}
+	return m.ops[len(m.ops)-1]
+}
+
+func (m mix) String() string {
+	parts := make([]string, len(m.ops))
+	for i, op := range m.ops {
+		parts[i] = fmt.Sprintf("%s=%d", op, m.weights[i])
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
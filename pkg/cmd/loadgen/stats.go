@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// stats 记录每个操作的延迟和错误
+type stats struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]map[codes.Code]int
+	elapsed   time.Duration
+}
+
+func newStats() *stats {
+	return &stats{
+		latencies: make(map[string][]time.Duration),
+		errors:    make(map[string]map[codes.Code]int),
+	}
+}
+
+func (s *stats) record(op string, latency time.Duration, code codes.Code) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies[op] = append(s.latencies[op], latency)
+	if code != codes.OK {
+		if s.errors[op] == nil {
+			s.errors[op] = make(map[codes.Code]int)
+		}
+		s.errors[op][code]++
+	}
+}
+
+// report 是压测结果，JSON 格式输出时的结构
+type report struct {
+	Transport   string  `json:"transport"`
+	Server      string  `json:"server"`
+	Mix         string  `json:"mix"`
+	Concurrency int     `json:"concurrency"`
+	TargetRate  float64 `json:"targetRate,omitempty"`
+	Seconds     float64 `json:"seconds"`
+
+	summary
+	Operations []operationReport `json:"operations"`
+}
+
+type operationReport struct {
+	Operation string `json:"operation"`
+	summary
+}
+
+// summary 是一组请求的吞吐量、延迟和错误
+type summary struct {
+	Requests   int     `json:"requests"`
+	Errors     int     `json:"errors"`
+	Throughput float64 `json:"throughput"`
+	Latency    latency `json:"latencyMs"`
+	// ErrorCodes 是按 gRPC 错误码统计的错误数
+	ErrorCodes map[string]int `json:"errorCodes,omitempty"`
+}
+
+// latency 是以毫秒为单位的延迟分布
+type latency struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// report 汇总统计结果
+func (s *stats) report() report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var r report
+	r.Seconds = s.elapsed.Seconds()
+
+	var all []time.Duration
+	allErrors := make(map[codes.Code]int)
+	for _, op := range operations {
+		latencies := s.latencies[op]
+		if len(latencies) == 0 {
+			continue
+		}
+		all = append(all, latencies...)
+		for code, n := range s.errors[op] {
+			allErrors[code] += n
+		}
+		r.Operations = append(r.Operations, operationReport{Operation: op, summary: summarize(latencies, s.errors[op], s.elapsed)})
+	}
+	r.summary = summarize(all, allErrors, s.elapsed)
+	return r
+}
+
+func summarize(latencies []time.Duration, errors map[codes.Code]int, elapsed time.Duration) summary {
+	s := summary{Requests: len(latencies)}
+	if elapsed > 0 {
+		s.Throughput = round(float64(len(latencies)) / elapsed.Seconds())
+	}
+	for code, n := range errors {
+		if s.ErrorCodes == nil {
+			s.ErrorCodes = make(map[string]int)
+		}
+		s.ErrorCodes[code.String()] = n
+		s.Errors += n
+	}
+	if len(latencies) == 0 {
+		return s
+	}
+
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, l := range sorted {
+		total += l
+	}
+	s.Latency = latency{
+		Mean: ms(total / time.Duration(len(sorted))),
+		P50:  ms(percentile(sorted, 50)),
+		P90:  ms(percentile(sorted, 90)),
+		P99:  ms(percentile(sorted, 99)),
+		Max:  ms(sorted[len(sorted)-1]),
+	}
+	return s
+}
+
+// percentile 返回已排序延迟的第 p 百分位数 (nearest-rank)
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+func ms(d time.Duration) float64 {
+	return round(float64(d) / float64(time.Millisecond))
+}
+
+// round 保留 3 位小数
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
+
+// errorRate 返回失败请求的比例
+func (r report) errorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+func (r report) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+func (r report) writeText(w io.Writer) error {
+	fmt.Fprintf(w, "%s %s, mix %s, concurrency %d", r.Transport, r.Server, r.Mix, r.Concurrency)
+	if r.TargetRate > 0 {
+		fmt.Fprintf(w, ", target %g req/s", r.TargetRate)
+	}
+	fmt.Fprintf(w, "\n%d requests in %.2fs, %.1f req/s, %d errors\n\n", r.Requests, r.Seconds, r.Throughput, r.Errors)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "OPERATION\tREQUESTS\tERRORS\tREQ/S\tMEAN\tP50\tP90\tP99\tMAX\t")
+	rows := append(r.Operations, operationReport{Operation: "total", summary: r.summary})
+	for _, op := range rows {
+		l := op.Latency
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%.2fms\t%.2fms\t%.2fms\t%.2fms\t%.2fms\t\n",
+			op.Operation, op.Requests, op.Errors, op.Throughput, l.Mean, l.P50, l.P90, l.P99, l.Max)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.ErrorCodes) > 0 {
+		fmt.Fprintln(w, "\nerrors:")
+		for _, op := range r.Operations {
+			codes := make([]string, 0, len(op.ErrorCodes))
+			for code := range op.ErrorCodes {
+				codes = append(codes, code)
+			}
+			sort.Strings(codes)
+			for _, code := range codes {
+				fmt.Fprintf(w, "  %s %s: %d\n", op.Operation, code, op.ErrorCodes[code])
+			}
+		}
+	}
+	return nil
+}
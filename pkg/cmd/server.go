@@ -6,10 +6,16 @@ import (
 	"flag"
 	"fmt"
 	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/protocol/rest"
+	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	api "go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/auth"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/notify"
 	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/protocol/grpc"
 	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/service/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/service/v2"
 )
 
 type Config struct {
@@ -20,6 +26,35 @@ type Config struct {
 	DatastoreDBUser     string
 	DatastoreDBPassword string
 	DatastoreDBSchema   string
+
+	// Create 请求幂等键的有效期
+	IdempotencyWindow time.Duration
+	// 回收站中的 ToDo 被永久删除之前保留的时间
+	TrashRetention time.Duration
+	// 每个 webhook 请求的超时时间
+	WebhookTimeout time.Duration
+	// 用户到 Bearer 令牌映射的 JSON 文件，为空时所有调用方都是匿名的
+	AuthTokens string
+	// 签发日历订阅令牌的密钥，必须同时配置 AuthTokens，为空时禁用日历订阅
+	CalendarSecret string
+	// 客户端访问 HTTP 网关使用的地址，例如 https://todo.example.com，用于 OpenAPI 文档
+	PublicURL string
+	// 除同源之外允许连接 WebSocket 事件端点的 Origin，逗号分隔
+	AllowedOrigins string
+
+	// 发送提醒邮件的 SMTP 服务器 host:port，为空时不发送邮件
+	SMTPAddr     string
+	SMTPFrom     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPStartTLS bool
+	SMTPTimeout  time.Duration
+	// 邮件标题和正文的 text/template 模板
+	SMTPSubject string
+	SMTPBody    string
+	// 用户到邮件地址映射的 JSON 文件，以及没有配置地址的用户使用的地址
+	SMTPAddressBook string
+	SMTPDefaultTo   string
 }
 
 func RunServer() error {
@@ -29,11 +64,28 @@ func RunServer() error {
 
 	flag.StringVar(&cfg.GRPCPort, "grpc-port", "", "gRPC port to bind")
 	flag.StringVar(&cfg.HttpPort, "http-port", "", "http port to bind")
+	flag.StringVar(&cfg.PublicURL, "public-url", "", "URL clients use to reach the HTTP gateway, written to the OpenAPI spec; empty uses the URL the spec is opened from")
+	flag.StringVar(&cfg.AllowedOrigins, "allowed-origins", "", "Comma separated origins, e.g. https://app.example.com, allowed to open the events WebSocket besides the gateway's own origin; * allows any")
 	flag.StringVar(&cfg.DatastoreDBHost, "db-host", "", "Database host")
 	flag.StringVar(&cfg.DatastoreDBPort, "db-port", "", "Database port")
 	flag.StringVar(&cfg.DatastoreDBUser, "db-user", "", "Database user")
 	flag.StringVar(&cfg.DatastoreDBPassword, "db-password", "", "Database password")
 	flag.StringVar(&cfg.DatastoreDBSchema, "db-schema", "", "Database schema")
+	flag.DurationVar(&cfg.TrashRetention, "trash-retention", v1.DefaultTrashRetention, "How long deleted ToDo are kept in trash before being purged")
+	flag.DurationVar(&cfg.IdempotencyWindow, "idempotency-window", v1.DefaultIdempotencyWindow, "How long Create idempotency keys are remembered")
+	flag.DurationVar(&cfg.WebhookTimeout, "webhook-timeout", 10*time.Second, "Timeout of each webhook request")
+	flag.StringVar(&cfg.AuthTokens, "auth-tokens", "", "JSON file mapping users to Bearer tokens, empty treats every caller as anonymous")
+	flag.StringVar(&cfg.CalendarSecret, "calendar-secret", "", "Secret signing calendar feed tokens, requires -auth-tokens; empty disables the feed")
+	flag.StringVar(&cfg.SMTPAddr, "smtp-addr", "", "SMTP server host:port for reminder emails, empty disables email")
+	flag.StringVar(&cfg.SMTPFrom, "smtp-from", "", "Sender address of reminder emails")
+	flag.StringVar(&cfg.SMTPUsername, "smtp-username", "", "SMTP username, empty disables authentication")
+	flag.StringVar(&cfg.SMTPPassword, "smtp-password", "", "SMTP password")
+	flag.BoolVar(&cfg.SMTPStartTLS, "smtp-starttls", true, "Require STARTTLS before authentication and sending")
+	flag.DurationVar(&cfg.SMTPTimeout, "smtp-timeout", notify.DefaultTimeout, "Timeout of sending each reminder email")
+	flag.StringVar(&cfg.SMTPSubject, "smtp-subject", notify.DefaultSubjectTemplate, "Template of reminder email subject")
+	flag.StringVar(&cfg.SMTPBody, "smtp-body", notify.DefaultBodyTemplate, "Template of reminder email body")
+	flag.StringVar(&cfg.SMTPAddressBook, "smtp-address-book", "", "JSON file mapping users to email addresses")
+	flag.StringVar(&cfg.SMTPDefaultTo, "smtp-default-to", "", "Email address for users not in the address book, empty skips them")
 
 	flag.Parse()
 
@@ -44,7 +96,19 @@ func RunServer() error {
 		return fmt.Errorf("invalid TCP port for HTTP gateway: '%s'", cfg.HttpPort)
 	}
 
-	param := "parseTime=true"
+	tokens, err := auth.LoadTokens(cfg.AuthTokens)
+	if err != nil {
+		return err
+	}
+
+	// 日历订阅令牌只签发给通过认证的用户，没有认证时不能启用日历订阅
+	if len(cfg.CalendarSecret) > 0 && len(cfg.AuthTokens) == 0 {
+		return fmt.Errorf("-calendar-secret requires -auth-tokens")
+	}
+
+	// 数据库中的时间统一按 UTC 存储和读取，不依赖 MySQL 服务器的默认时区:
+	// loc 是驱动解释时间的时区，time_zone 是会话的时区，CURRENT_TIMESTAMP 默认值和 timestamp 列的转换都使用它
+	param := "parseTime=true&loc=UTC&time_zone=%27%2B00:00%27"
 
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?%s",
 		cfg.DatastoreDBUser,
@@ -61,12 +125,76 @@ func RunServer() error {
 
 	defer db.Close()
 
-	v1API := v1.NewToDoServiceServer(db)
+	hub := v1.NewHub(v1.DefaultHubHistory)
+	v1API := v1.NewToDoServiceServer(db, hub, cfg.IdempotencyWindow, cfg.CalendarSecret)
+	webhookAPI := v1.NewWebhookServiceServer(db)
+	// v2 通过 v1 读写同一份数据
+	v2API := v2.NewToDoServiceServer(v1API)
+
+	// 后台清理回收站和过期的幂等键
+	go v1.RunPurger(ctx, db, hub, cfg.TrashRetention)
+	go v1.RunIdempotencyCleaner(ctx, db, cfg.IdempotencyWindow)
+
+	// 后台发送到期的提醒
+	notifiers := notify.Multi{notify.Log{}}
+	if len(cfg.SMTPAddr) > 0 {
+		email, err := newSMTPNotifier(cfg, db)
+		if err != nil {
+			return err
+		}
+		notifiers = append(notifiers, email)
+	}
+	go v1.RunReminders(ctx, db, hub, notifiers)
+
+	// 后台发送 webhook 请求
+	go v1.RunWebhooks(ctx, db, v1.NewWebhookClient(cfg.WebhookTimeout))
 
 	// 启动 http gateway
 	go func() {
-		_ = rest.RunServer(ctx, cfg.GRPCPort, cfg.HttpPort)
+		_ = rest.RunServer(ctx, cfg.GRPCPort, cfg.HttpPort, cfg.PublicURL, splitList(cfg.AllowedOrigins))
 	}()
 
-	return grpc.RunServer(ctx, v1API, cfg.GRPCPort)
+	return grpc.RunServer(ctx, v1API, v2API, webhookAPI, cfg.GRPCPort, tokens.ServerOptions()...)
+}
+
+// newSMTPNotifier 创建把提醒发送到 ToDo 创建者邮箱的 notify.SMTP
+func newSMTPNotifier(cfg Config, db *sql.DB) (*notify.SMTP, error) {
+	subject, body, err := notify.ParseTemplates(cfg.SMTPSubject, cfg.SMTPBody)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := notify.LoadAddressBook(cfg.SMTPAddressBook, cfg.SMTPDefaultTo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &notify.SMTP{
+		Addr:     cfg.SMTPAddr,
+		From:     cfg.SMTPFrom,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		StartTLS: cfg.SMTPStartTLS,
+		Timeout:  cfg.SMTPTimeout,
+		Subject:  subject,
+		Body:     body,
+		Recipient: func(ctx context.Context, todo *api.ToDo) (string, error) {
+			user, err := v1.Creator(ctx, db, todo.Id)
+			if err != nil {
+				return "", err
+			}
+			return book.Address(user), nil
+		},
+	}, nil
+}
+
+// splitList 把逗号分隔的参数拆分为列表，忽略空项
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			list = append(list, v)
+		}
+	}
+	return list
 }
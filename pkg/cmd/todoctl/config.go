@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+const (
+	transportGRPC = "grpc"
+	transportREST = "rest"
+
+	// 各种传输方式默认的服务器地址
+	defaultGRPCServer = "localhost:9090"
+	defaultRESTServer = "http://localhost:8080"
+
+	// configEnv 是指定配置文件路径的环境变量
+	configEnv = "TODOCTL_CONFIG"
+)
+
+// config 是 todoctl 的配置，命令行参数覆盖配置文件中的值
+//
+// 配置文件是 YAML 格式，例如:
+//
+//	server: localhost:9090
+//	transport: grpc
+//	token: secret
+//	output: table
+//	timeout: 10s
+type config struct {
+	// Server 是 gRPC 的 host:port 或 REST 网关的 URL
+	Server string `json:"server"`
+	// Transport 是 grpc 或 rest
+	Transport string `json:"transport"`
+	// Token 以 Bearer 令牌发送给服务器，服务器根据令牌认证用户并记录在变更历史中
+	Token string `json:"token"`
+	// Output 是 table、json 或 yaml
+	Output string `json:"output"`
+	// Timeout 是每个请求的超时时间，例如 10s
+	Timeout string `json:"timeout"`
+}
+
+// defaultConfigPath 返回默认的配置文件路径: $TODOCTL_CONFIG 或 ~/.todoctl.yaml
+func defaultConfigPath() string {
+	if p := os.Getenv(configEnv); len(p) > 0 {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".todoctl.yaml")
+}
+
+// loadConfig 读取配置文件; 文件不存在且 required 为 false 时返回空配置
+func loadConfig(path string, required bool) (config, error) {
+	var cfg config
+	if len(path) == 0 {
+		return cfg, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !required {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// merge 用 override 中不为空的值覆盖 cfg
+func (cfg config) merge(override config) config {
+	for _, f := range []struct{ dst, src *string }{
+		{&cfg.Server, &override.Server},
+		{&cfg.Transport, &override.Transport},
+		{&cfg.Token, &override.Token},
+		{&cfg.Output, &override.Output},
+		{&cfg.Timeout, &override.Timeout},
+	} {
+		if len(*f.src) > 0 {
+			*f.dst = *f.src
+		}
+	}
+	return cfg
+}
+
+// validate 检查配置并填充默认值
+func (cfg *config) validate() (time.Duration, error) {
+	switch cfg.Transport {
+	case "":
+		cfg.Transport = transportGRPC
+	case transportGRPC, transportREST:
+	default:
+		return 0, fmt.Errorf("invalid transport %q, must be grpc or rest", cfg.Transport)
+	}
+
+	if len(cfg.Server) == 0 {
+		cfg.Server = defaultGRPCServer
+		if cfg.Transport == transportREST {
+			cfg.Server = defaultRESTServer
+		}
+	}
+
+	switch cfg.Output {
+	case "":
+		cfg.Output = outputTable
+	case outputTable, outputJSON, outputYAML:
+	default:
+		return 0, fmt.Errorf("invalid output %q, must be table, json or yaml", cfg.Output)
+	}
+
+	if len(cfg.Timeout) == 0 {
+		return 10 * time.Second, nil
+	}
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q", cfg.Timeout)
+	}
+	return timeout, nil
+}
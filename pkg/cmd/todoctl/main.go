@@ -0,0 +1,359 @@
+// todoctl 是 ToDo 服务的命令行客户端，可以通过 gRPC 或 REST 网关调用服务
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const apiVersion = "v1"
+
+const usage = `todoctl manages ToDo items over gRPC or the REST gateway.
+
+Usage:
+  todoctl [global flags] <command> [flags] [arguments]
+
+Commands:
+  create               create a ToDo
+  get <id>             show a ToDo
+  list                 list ToDo
+  update <id>          change the given fields of a ToDo
+  delete <id>...       move ToDo to trash
+  complete <id>        complete the current occurrence of a ToDo
+
+Run "todoctl <command> -h" for the flags of a command.
+
+Global flags:
+`
+
+// errUsage 表示参数错误，已经输出了用法
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run 执行命令并返回进程的退出码
+func run(args []string, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("todoctl", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		global.PrintDefaults()
+	}
+
+	configPath := global.String("config", defaultConfigPath(), "Config file with server address and credentials (YAML), defaults to $"+configEnv+" or ~/.todoctl.yaml")
+	var flags config
+	global.StringVar(&flags.Server, "server", "", "gRPC server host:port or REST gateway URL (default "+defaultGRPCServer+" or "+defaultRESTServer+")")
+	global.StringVar(&flags.Transport, "transport", "", "Transport: grpc or rest (default grpc)")
+	global.StringVar(&flags.Token, "token", "", "Bearer token sent with every request")
+	global.StringVar(&flags.Output, "output", "", "Output format: table, json or yaml (default table)")
+	global.StringVar(&flags.Timeout, "timeout", "", "Timeout of each request (default 10s)")
+
+	if err := global.Parse(args); err != nil {
+		return 2
+	}
+	if global.NArg() == 0 {
+		global.Usage()
+		return 2
+	}
+
+	// 显式指定的配置文件必须存在
+	var explicitConfig bool
+	global.Visit(func(f *flag.Flag) { explicitConfig = explicitConfig || f.Name == "config" })
+	if _, ok := os.LookupEnv(configEnv); ok {
+		explicitConfig = true
+	}
+
+	cfg, err := loadConfig(*configPath, explicitConfig)
+	if err != nil {
+		fmt.Fprintf(stderr, "todoctl: %v\n", err)
+		return 1
+	}
+	cfg = cfg.merge(flags)
+	timeout, err := cfg.validate()
+	if err != nil {
+		fmt.Fprintf(stderr, "todoctl: %v\n", err)
+		return 2
+	}
+
+	name, cmdArgs := global.Arg(0), global.Args()[1:]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "todoctl: unknown command %q\n\n", name)
+		global.Usage()
+		return 2
+	}
+
+	c, closeClient, err := dial(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "todoctl: failed to connect to %s: %v\n", cfg.Server, err)
+		return 1
+	}
+	defer closeClient()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	fs := flag.NewFlagSet("todoctl "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	err = cmd(ctx, &env{client: c, out: printer{w: stdout, format: cfg.Output}, flags: fs, stderr: stderr}, cmdArgs)
+	switch {
+	case err == nil:
+		return 0
+	case err == errUsage || err == flag.ErrHelp:
+		return 2
+	}
+
+	if s, ok := status.FromError(err); ok {
+		fmt.Fprintf(stderr, "todoctl: %s: %s\n", s.Code(), s.Message())
+	} else {
+		fmt.Fprintf(stderr, "todoctl: %v\n", err)
+	}
+	return 1
+}
+
+// env 是命令执行时的环境
+type env struct {
+	client client
+	out    printer
+	flags  *flag.FlagSet
+	stderr io.Writer
+}
+
+// usageError 输出命令的用法并返回 errUsage
+func (e *env) usageError(format string, a ...interface{}) error {
+	fmt.Fprintf(e.stderr, "%s: %s\n", e.flags.Name(), fmt.Sprintf(format, a...))
+	e.flags.Usage()
+	return errUsage
+}
+
+type command func(ctx context.Context, e *env, args []string) error
+
+var commands = map[string]command{
+	"create":   createCommand,
+	"get":      getCommand,
+	"list":     listCommand,
+	"update":   updateCommand,
+	"delete":   deleteCommand,
+	"complete": completeCommand,
+}
+
+// toDoFlags 是 create 和 update 共用的 ToDo 字段参数
+type toDoFlags struct {
+	title, description, reminder, timeZone, recurrence *string
+}
+
+func addToDoFlags(fs *flag.FlagSet) *toDoFlags {
+	return &toDoFlags{
+		title:       fs.String("title", "", "Title"),
+		description: fs.String("description", "", "Description"),
+		reminder:    fs.String("reminder", "", "Reminder: RFC 3339 time, \"2006-01-02 15:04\" in -time-zone, or a duration from now such as +2h"),
+		timeZone:    fs.String("time-zone", "", "IANA time zone of the reminder, such as Europe/Berlin (default UTC)"),
+		recurrence:  fs.String("recurrence", "", "RFC 5545 recurrence rule, such as RRULE:FREQ=WEEKLY;BYDAY=MO"),
+	}
+}
+
+// apply 把命令行中出现的参数写入 todo，空字符串也会覆盖原来的值
+func (f *toDoFlags) apply(fs *flag.FlagSet, todo *v1.ToDo, now time.Time) error {
+	set := make(map[string]bool)
+	fs.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+
+	if set["title"] {
+		todo.Title = *f.title
+	}
+	if set["description"] {
+		todo.Description = *f.description
+	}
+	if set["time-zone"] {
+		todo.TimeZone = *f.timeZone
+	}
+	if set["recurrence"] {
+		todo.Recurrence = *f.recurrence
+	}
+	if set["reminder"] {
+		reminder, err := parseReminder(*f.reminder, todo.TimeZone, now)
+		if err != nil {
+			return err
+		}
+		if todo.Reminder, err = ptypes.TimestampProto(reminder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseReminder 解析 -reminder 参数: RFC 3339 时间、zone 时区的 "2006-01-02 15:04" 本地时间或以 + 开头的相对时间
+func parseReminder(s, zone string, now time.Time) (time.Time, error) {
+	if strings.HasPrefix(s, "+") {
+		d, err := time.ParseDuration(s[1:])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid reminder %q: %v", s, err)
+		}
+		return now.Add(d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time zone %q", zone)
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid reminder %q, must be RFC 3339, \"2006-01-02 15:04\" or +duration", s)
+	}
+	return t, nil
+}
+
+// parseIDs 解析位置参数中的 ID，n 为 -1 时接受一个或多个 ID
+func (e *env) parseIDs(n int) ([]int64, error) {
+	args := e.flags.Args()
+	if (n < 0 && len(args) == 0) || (n >= 0 && len(args) != n) {
+		return nil, e.usageError("expected %s", map[bool]string{true: "one or more IDs", false: "one ID"}[n < 0])
+	}
+	ids := make([]int64, len(args))
+	for i, a := range args {
+		id, err := strconv.ParseInt(a, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, e.usageError("invalid ID %q", a)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func createCommand(ctx context.Context, e *env, args []string) error {
+	f := addToDoFlags(e.flags)
+	requestID := e.flags.String("request-id", "", "Idempotency key, retrying with the same key does not create a second ToDo")
+	if err := e.flags.Parse(args); err != nil {
+		return err
+	}
+	if e.flags.NArg() > 0 {
+		return e.usageError("unexpected arguments %v", e.flags.Args())
+	}
+
+	todo := &v1.ToDo{}
+	if err := f.apply(e.flags, todo, time.Now()); err != nil {
+		return e.usageError("%v", err)
+	}
+
+	resp, err := e.client.Create(ctx, &v1.CreateRequest{Api: apiVersion, ToDo: todo, RequestId: *requestID})
+	if err != nil {
+		return err
+	}
+	todo.Id = resp.Id
+	return e.out.toDos(false, todo)
+}
+
+func getCommand(ctx context.Context, e *env, args []string) error {
+	showDeleted := e.flags.Bool("show-deleted", false, "Also show a ToDo in trash")
+	if err := e.flags.Parse(args); err != nil {
+		return err
+	}
+	ids, err := e.parseIDs(1)
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Read(ctx, &v1.ReadRequest{Api: apiVersion, Id: ids[0], ShowDeleted: *showDeleted})
+	if err != nil {
+		return err
+	}
+	return e.out.toDos(false, resp.ToDo)
+}
+
+func listCommand(ctx context.Context, e *env, args []string) error {
+	showDeleted := e.flags.Bool("show-deleted", false, "Also list ToDo in trash")
+	if err := e.flags.Parse(args); err != nil {
+		return err
+	}
+	if e.flags.NArg() > 0 {
+		return e.usageError("unexpected arguments %v", e.flags.Args())
+	}
+
+	resp, err := e.client.ReadAll(ctx, &v1.ReadAllRequest{Api: apiVersion, ShowDeleted: *showDeleted})
+	if err != nil {
+		return err
+	}
+	return e.out.toDos(true, resp.ToDos...)
+}
+
+// updateCommand 读取 ToDo，修改命令行中出现的字段后写回
+func updateCommand(ctx context.Context, e *env, args []string) error {
+	f := addToDoFlags(e.flags)
+	if err := e.flags.Parse(args); err != nil {
+		return err
+	}
+	ids, err := e.parseIDs(1)
+	if err != nil {
+		return err
+	}
+	if e.flags.NFlag() == 0 {
+		return e.usageError("nothing to update")
+	}
+
+	read, err := e.client.Read(ctx, &v1.ReadRequest{Api: apiVersion, Id: ids[0]})
+	if err != nil {
+		return err
+	}
+	todo := proto.Clone(read.ToDo).(*v1.ToDo)
+	if err := f.apply(e.flags, todo, time.Now()); err != nil {
+		return e.usageError("%v", err)
+	}
+
+	if _, err := e.client.Update(ctx, &v1.UpdateRequest{Api: apiVersion, ToDo: todo}); err != nil {
+		return err
+	}
+	return e.out.toDos(false, todo)
+}
+
+func deleteCommand(ctx context.Context, e *env, args []string) error {
+	if err := e.flags.Parse(args); err != nil {
+		return err
+	}
+	ids, err := e.parseIDs(-1)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		resp, err := e.client.Delete(ctx, &v1.DeleteRequest{Api: apiVersion, Id: id})
+		if err != nil {
+			return fmt.Errorf("failed to delete ToDo %d: %v", id, err)
+		}
+		if err := e.out.message(resp, "deleted", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func completeCommand(ctx context.Context, e *env, args []string) error {
+	if err := e.flags.Parse(args); err != nil {
+		return err
+	}
+	ids, err := e.parseIDs(1)
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Complete(ctx, &v1.CompleteRequest{Api: apiVersion, Id: ids[0]})
+	if err != nil {
+		return err
+	}
+	return e.out.toDos(false, resp.ToDo)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseReminder(t *testing.T) {
+	now := time.Date(2019, 5, 6, 7, 0, 0, 0, time.UTC)
+	tests := []struct {
+		s, zone string
+		want    time.Time
+		wantErr bool
+	}{
+		{s: "+90m", want: now.Add(90 * time.Minute)},
+		{s: "2019-05-07T08:00:00+02:00", want: time.Date(2019, 5, 7, 6, 0, 0, 0, time.UTC)},
+		{s: "2019-05-07 08:00", zone: "Europe/Berlin", want: time.Date(2019, 5, 7, 6, 0, 0, 0, time.UTC)},
+		{s: "2019-05-07 08:00", want: time.Date(2019, 5, 7, 8, 0, 0, 0, time.UTC)},
+		{s: "tomorrow", wantErr: true},
+		{s: "+soon", wantErr: true},
+		{s: "2019-05-07 08:00", zone: "Mars/Olympus", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseReminder(tt.s, tt.zone, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseReminder(%q, %q) error = %v, wantErr %v", tt.s, tt.zone, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !got.Equal(tt.want) {
+			t.Errorf("parseReminder(%q, %q) = %v, want %v", tt.s, tt.zone, got, tt.want)
+		}
+	}
+}
+
+func TestRunREST(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("Authorization")+" "+string(body))
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/todo/5":
+			w.Write([]byte(`{"api":"v1","toDo":{"id":"5","title":"old","description":"keep","reminder":"2019-05-06T07:00:00Z"}}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/todo/5":
+			w.Write([]byte(`{"api":"v1","updated":"1"}`))
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":5,"message":"ToDo with ID='6' is not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-config=", "-transport=rest", "-server=" + server.URL, "-token=alice-token", "-output=json", "update", "-title=new", "5"}
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("run(%v) = %d, stderr: %s", args, code, stderr.String())
+	}
+	if len(requests) != 2 || !strings.HasPrefix(requests[1], "PUT /v1/todo/5 Bearer alice-token ") ||
+		!strings.Contains(requests[1], `"title":"new"`) || !strings.Contains(requests[1], `"description":"keep"`) {
+		t.Errorf("requests = %q, want read then update with only the title changed", requests)
+	}
+	if !strings.Contains(stdout.String(), `"title": "new"`) {
+		t.Errorf("stdout = %s, want the updated ToDo", stdout.String())
+	}
+
+	stderr.Reset()
+	args = []string{"-config=", "-transport=rest", "-server=" + server.URL, "delete", "6"}
+	if code := run(args, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "NotFound") {
+		t.Errorf("run(%v) = %d, stderr: %s, want NotFound error", args, code, stderr.String())
+	}
+
+	if code := run([]string{"-config=", "frobnicate"}, &stdout, &stderr); code != 2 {
+		t.Errorf("run(frobnicate) = %d, want 2", code)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+
+	// tableTimeLayout 是表格中时间的格式
+	tableTimeLayout = "2006-01-02 15:04 MST"
+)
+
+// printer 按配置的格式输出结果
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// toDos 输出 ToDo; JSON/YAML 中单个 ToDo 输出为对象，多个 ToDo (list) 输出为数组
+func (p printer) toDos(list bool, todos ...*v1.ToDo) error {
+	if p.format == outputTable {
+		tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tTITLE\tREMINDER\tSTATUS\tRECURRING\tDELETED")
+		for _, todo := range todos {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", todo.Id, todo.Title, formatTime(todo.Reminder, todo.TimeZone),
+				todo.ReminderStatus, strconv.FormatBool(len(todo.Recurrence) > 0), formatTime(todo.DeleteTime, todo.TimeZone))
+		}
+		return tw.Flush()
+	}
+
+	if !list && len(todos) == 1 {
+		return p.message(todos[0])
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	m := jsonpb.Marshaler{Indent: "  "}
+	for i, todo := range todos {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+		if err := m.Marshal(&buf, todo); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("\n]")
+	return p.json(buf.Bytes())
+}
+
+// message 以 JSON 或 YAML 输出 m，table 格式时输出 summary
+func (p printer) message(m proto.Message, summary ...interface{}) error {
+	if p.format == outputTable {
+		_, err := fmt.Fprintln(p.w, summary...)
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := (&jsonpb.Marshaler{Indent: "  "}).Marshal(&buf, m); err != nil {
+		return err
+	}
+	return p.json(buf.Bytes())
+}
+
+// json 输出 JSON 数据，YAML 格式时先转换为 YAML
+func (p printer) json(data []byte) error {
+	if p.format == outputYAML {
+		y, err := yaml.JSONToYAML(data)
+		if err != nil {
+			return err
+		}
+		_, err = p.w.Write(y)
+		return err
+	}
+	_, err := fmt.Fprintf(p.w, "%s\n", data)
+	return err
+}
+
+// formatTime 把时间格式化为 ToDo 时区的本地时间，nil 输出为空
+func formatTime(ts *timestamp.Timestamp, zone string) string {
+	if ts == nil {
+		return ""
+	}
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return ""
+	}
+	if loc, err := time.LoadLocation(zone); err == nil {
+		t = t.In(loc)
+	}
+	return t.Format(tableTimeLayout)
+}
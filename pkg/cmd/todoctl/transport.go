@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	grpcclient "go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/client/grpc"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/client/rest"
+)
+
+// client 是 todoctl 使用的 ToDoService 方法，grpcclient.Client 和 rest.Client 都实现了它
+type client interface {
+	Create(ctx context.Context, in *v1.CreateRequest, opts ...grpc.CallOption) (*v1.CreateResponse, error)
+	Read(ctx context.Context, in *v1.ReadRequest, opts ...grpc.CallOption) (*v1.ReadResponse, error)
+	ReadAll(ctx context.Context, in *v1.ReadAllRequest, opts ...grpc.CallOption) (*v1.ReadAllResponse, error)
+	Update(ctx context.Context, in *v1.UpdateRequest, opts ...grpc.CallOption) (*v1.UpdateResponse, error)
+	Delete(ctx context.Context, in *v1.DeleteRequest, opts ...grpc.CallOption) (*v1.DeleteResponse, error)
+	Complete(ctx context.Context, in *v1.CompleteRequest, opts ...grpc.CallOption) (*v1.CompleteResponse, error)
+}
+
+// dial 按配置连接服务器，返回的 close 函数释放连接
+func dial(cfg config) (client, func(), error) {
+	if cfg.Transport == transportREST {
+		var opts []rest.Option
+		if len(cfg.Token) > 0 {
+			opts = append(opts, rest.WithToken(cfg.Token))
+		}
+		c, err := rest.New(cfg.Server, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, func() {}, nil
+	}
+
+	c, err := grpcclient.Dial(context.Background(), cfg.Server,
+		grpcclient.WithToken(cfg.Token))
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, func() { c.Close() }, nil
+}
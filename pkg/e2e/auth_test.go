@@ -0,0 +1,59 @@
+package e2e
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+// TestAuthentication 检查变更历史只记录通过令牌认证的用户，客户端自己声明的身份被忽略
+func TestAuthentication(t *testing.T) {
+	e := newEnv(t)
+	defer e.server.Close()
+
+	post := func(header map[string]string) (int, string) {
+		req, _ := http.NewRequest(http.MethodPost, e.server.URL+"/v1/todo", strings.NewReader(`{"api":"v1","toDo":{"title":"milk","reminder":"2030-01-07T09:00:00Z"}}`))
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := e.http.Do(req)
+		if err != nil {
+			t.Fatalf("POST /v1/todo error = %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	// 伪造的身份头不被采用
+	if code, body := post(map[string]string{"Grpc-Metadata-X-Principal": User}); code != http.StatusOK {
+		t.Fatalf("POST /v1/todo = %d %s, want 200", code, body)
+	}
+	if code, body := post(map[string]string{"Authorization": "Bearer " + Token}); code != http.StatusOK {
+		t.Fatalf("POST /v1/todo = %d %s, want 200", code, body)
+	}
+	if code, body := post(map[string]string{"Authorization": "Bearer wrong-token"}); code != http.StatusUnauthorized {
+		t.Errorf("POST /v1/todo with an invalid token = %d %s, want 401", code, body)
+	}
+
+	ctx := context.Background()
+	for id, want := range map[int64]string{1: "anonymous", 2: User} {
+		resp, err := e.todo.ListRevisions(ctx, &v1.ListRevisionsRequest{Api: "v1", Id: id})
+		if err != nil || len(resp.Revisions) != 1 || resp.Revisions[0].Principal != want {
+			t.Errorf("ListRevisions(%d) = %v, %v, want one revision by %s", id, resp, err, want)
+		}
+	}
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer wrong-token")
+	if _, err := e.todo.Read(ctx, &v1.ReadRequest{Api: "v1", Id: 1}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Read() with an invalid token error = %v, want Unauthenticated", err)
+	}
+}
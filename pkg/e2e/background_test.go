@@ -0,0 +1,122 @@
+package e2e
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	service "go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/service/v1"
+)
+
+// notifierFunc 把函数适配为 notify.Notifier
+type notifierFunc func(ctx context.Context, todo *v1.ToDo) error
+
+func (f notifierFunc) Notify(ctx context.Context, todo *v1.ToDo) error {
+	return f(ctx, todo)
+}
+
+// waitFor 每 10ms 检查一次 cond，5 秒内不成立时测试失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+// TestReminderDelivery 检查后台任务认领 (FOR UPDATE SKIP LOCKED)、发送并记录到期的提醒
+func TestReminderDelivery(t *testing.T) {
+	s, err := Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	reminder, _ := ptypes.TimestampProto(time.Now().Add(time.Hour))
+	created, err := s.ToDoClient().Create(ctx, &v1.CreateRequest{Api: "v1", ToDo: &v1.ToDo{Title: "call", Reminder: reminder}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	// 创建时提醒时间不能早于当前时间，直接修改数据库模拟提醒到期
+	if _, err := s.DB.Exec("UPDATE ToDo SET `Reminder`=? WHERE `ID`=?", time.Now().Add(-time.Minute), created.Id); err != nil {
+		t.Fatalf("failed to update reminder: %v", err)
+	}
+
+	notified := make(chan int64, 1)
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go service.RunReminders(runCtx, s.DB, s.Hub, notifierFunc(func(_ context.Context, todo *v1.ToDo) error {
+		notified <- todo.Id
+		return nil
+	}))
+
+	select {
+	case id := <-notified:
+		if id != created.Id {
+			t.Errorf("notified ToDo %d, want %d", id, created.Id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the reminder")
+	}
+
+	waitFor(t, "the reminder to be recorded as delivered", func() bool {
+		resp, err := s.ToDoClient().Read(ctx, &v1.ReadRequest{Api: "v1", Id: created.Id})
+		return err == nil && resp.ToDo.ReminderStatus == v1.ToDo_DELIVERED && resp.ToDo.ReminderDeliveryTime != nil
+	})
+}
+
+// TestWebhookDelivery 检查变更在同一个事务中加入发送队列，后台任务认领 (FOR UPDATE OF d SKIP LOCKED) 并发送
+func TestWebhookDelivery(t *testing.T) {
+	s, err := Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer s.Close()
+
+	bodies := make(chan string, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies <- string(body)
+	}))
+	defer receiver.Close()
+
+	// CreateWebhook 拒绝本机地址，直接写入数据库
+	res, err := s.DB.Exec("INSERT INTO Webhook(`URL`, `Events`, `Secret`) VALUES (?,?,?)", receiver.URL, "CREATED", "secret")
+	if err != nil {
+		t.Fatalf("failed to insert webhook: %v", err)
+	}
+	webhookID, _ := res.LastInsertId()
+
+	ctx := context.Background()
+	reminder, _ := ptypes.TimestampProto(time.Now().Add(time.Hour))
+	if _, err := s.ToDoClient().Create(ctx, &v1.CreateRequest{Api: "v1", ToDo: &v1.ToDo{Title: "webhook", Reminder: reminder}}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go service.RunWebhooks(runCtx, s.DB, receiver.Client())
+
+	select {
+	case body := <-bodies:
+		if !strings.Contains(body, `"CREATED"`) || !strings.Contains(body, `"webhook"`) {
+			t.Errorf("webhook body = %s, want the CREATED event", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the webhook request")
+	}
+
+	waitFor(t, "the delivery to be recorded", func() bool {
+		resp, err := s.WebhookClient().ListWebhookDeliveries(ctx, &v1.ListWebhookDeliveriesRequest{Api: "v1", WebhookId: webhookID})
+		return err == nil && len(resp.Deliveries) == 1 && resp.Deliveries[0].Status == v1.WebhookDelivery_DELIVERED
+	})
+}
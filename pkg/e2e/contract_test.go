@@ -0,0 +1,794 @@
+package e2e
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+// volatileFields 是与调用时间或随机数有关的字段，比较前替换为固定值
+var volatileFields = map[string]bool{
+	"deleteTime":           true,
+	"changeTime":           true,
+	"time":                 true,
+	"createTime":           true,
+	"nextAttemptTime":      true,
+	"reminderDeliveryTime": true,
+	"secret":               true,
+}
+
+// env 是一次场景运行的环境，gRPC 和 REST 各使用一个独立的服务器
+type env struct {
+	server  *Server
+	todo    v1.ToDoServiceClient
+	webhook v1.WebhookServiceClient
+	http    *http.Client
+	// ids 保存场景中创建的 ToDo 和 webhook 的 ID，供后面的步骤使用
+	ids map[string]int64
+}
+
+// step 是场景中的一步，gRPC 和 REST 执行相同的操作，结果应该相同
+type step struct {
+	name string
+	grpc func(ctx context.Context, e *env) (interface{}, error)
+	rest func(ctx context.Context, e *env) (interface{}, error)
+}
+
+var reminder = time.Date(2030, 1, 7, 9, 0, 0, 0, time.UTC)
+
+func timestamp(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+func toDo(title string) *v1.ToDo {
+	r, _ := ptypes.TimestampProto(reminder)
+	return &v1.ToDo{Title: title, Description: "description of " + title, Reminder: r, TimeZone: "Europe/Berlin"}
+}
+
+// steps 覆盖 ToDoService 和 WebhookService 的所有方法
+var steps = []step{
+	{
+		name: "Create",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			resp, err := e.todo.Create(ctx, &v1.CreateRequest{Api: "v1", ToDo: toDo("milk"), RequestId: "req-1"})
+			if err == nil {
+				e.ids["milk"] = resp.Id
+			}
+			return resp, err
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.CreateResponse{}
+			err := e.do(ctx, http.MethodPost, "/v1/todo", &v1.CreateRequest{Api: "v1", ToDo: toDo("milk"), RequestId: "req-1"}, resp)
+			if err == nil {
+				e.ids["milk"] = resp.Id
+			}
+			return resp, err
+		},
+	},
+	{
+		name: "Create with the same request ID",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.Create(ctx, &v1.CreateRequest{Api: "v1", ToDo: toDo("milk"), RequestId: "req-1"})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.CreateResponse{}
+			return resp, e.do(ctx, http.MethodPost, "/v1/todo", &v1.CreateRequest{Api: "v1", ToDo: toDo("milk"), RequestId: "req-1"}, resp)
+		},
+	},
+	{
+		name: "Create recurring",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			todo := toDo("standup")
+			todo.Recurrence = "RRULE:FREQ=DAILY;COUNT=5"
+			resp, err := e.todo.Create(ctx, &v1.CreateRequest{Api: "v1", ToDo: todo})
+			if err == nil {
+				e.ids["standup"] = resp.Id
+			}
+			return resp, err
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			todo := toDo("standup")
+			todo.Recurrence = "RRULE:FREQ=DAILY;COUNT=5"
+			resp := &v1.CreateResponse{}
+			err := e.do(ctx, http.MethodPost, "/v1/todo", &v1.CreateRequest{Api: "v1", ToDo: todo}, resp)
+			if err == nil {
+				e.ids["standup"] = resp.Id
+			}
+			return resp, err
+		},
+	},
+	{
+		name: "Create with unsupported API version",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.Create(ctx, &v1.CreateRequest{Api: "v0", ToDo: toDo("old")})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.CreateResponse{}
+			return resp, e.do(ctx, http.MethodPost, "/v1/todo", &v1.CreateRequest{Api: "v0", ToDo: toDo("old")}, resp)
+		},
+	},
+	{
+		name: "Read",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.Read(ctx, &v1.ReadRequest{Api: "v1", Id: e.ids["milk"]})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.ReadResponse{}
+			return resp, e.do(ctx, http.MethodGet, fmt.Sprintf("/v1/todo/%d?api=v1", e.ids["milk"]), nil, resp)
+		},
+	},
+	{
+		name: "Read missing",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.Read(ctx, &v1.ReadRequest{Api: "v1", Id: 999})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.ReadResponse{}
+			return resp, e.do(ctx, http.MethodGet, "/v1/todo/999?api=v1", nil, resp)
+		},
+	},
+	{
+		name: "Update",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			todo := toDo("oat milk")
+			todo.Id = e.ids["milk"]
+			return e.todo.Update(ctx, &v1.UpdateRequest{Api: "v1", ToDo: todo})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			todo := toDo("oat milk")
+			todo.Id = e.ids["milk"]
+			resp := &v1.UpdateResponse{}
+			return resp, e.do(ctx, http.MethodPut, fmt.Sprintf("/v1/todo/%d", todo.Id), &v1.UpdateRequest{Api: "v1", ToDo: todo}, resp)
+		},
+	},
+	{
+		name: "Update with PATCH",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			todo := toDo("soy milk")
+			todo.Id = e.ids["milk"]
+			return e.todo.Update(ctx, &v1.UpdateRequest{Api: "v1", ToDo: todo})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			todo := toDo("soy milk")
+			todo.Id = e.ids["milk"]
+			resp := &v1.UpdateResponse{}
+			return resp, e.do(ctx, http.MethodPatch, fmt.Sprintf("/v1/todo/%d", todo.Id), &v1.UpdateRequest{Api: "v1", ToDo: todo}, resp)
+		},
+	},
+	{
+		name: "ReadAll",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.ReadAll(ctx, &v1.ReadAllRequest{Api: "v1"})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.ReadAllResponse{}
+			return resp, e.do(ctx, http.MethodGet, "/v1/todo/all?api=v1", nil, resp)
+		},
+	},
+	{
+		name: "StreamAll",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			stream, err := e.todo.StreamAll(ctx, &v1.StreamAllRequest{Api: "v1"})
+			if err != nil {
+				return nil, err
+			}
+			var all []proto.Message
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					return all, nil
+				}
+				if err != nil {
+					return all, err
+				}
+				all = append(all, resp)
+			}
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.stream(ctx, "/v1/todo/stream?api=v1", -1, func() proto.Message { return &v1.StreamAllResponse{} })
+		},
+	},
+	{
+		name: "ListOccurrences",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.ListOccurrences(ctx, &v1.ListOccurrencesRequest{Api: "v1", Id: e.ids["standup"], Count: 3})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.ListOccurrencesResponse{}
+			return resp, e.do(ctx, http.MethodGet, fmt.Sprintf("/v1/todo/%d/occurrences?api=v1&count=3", e.ids["standup"]), nil, resp)
+		},
+	},
+	{
+		name: "ListOccurrences of a rule",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			start, _ := ptypes.TimestampProto(reminder)
+			return e.todo.ListOccurrences(ctx, &v1.ListOccurrencesRequest{Api: "v1", Recurrence: "RRULE:FREQ=WEEKLY", Start: start, Count: 2, TimeZone: "Asia/Shanghai"})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			start, _ := ptypes.TimestampProto(reminder)
+			resp := &v1.ListOccurrencesResponse{}
+			return resp, e.do(ctx, http.MethodPost, "/v1/todo:occurrences",
+				&v1.ListOccurrencesRequest{Api: "v1", Recurrence: "RRULE:FREQ=WEEKLY", Start: start, Count: 2, TimeZone: "Asia/Shanghai"}, resp)
+		},
+	},
+	{
+		name: "Complete",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.Complete(ctx, &v1.CompleteRequest{Api: "v1", Id: e.ids["standup"]})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.CompleteResponse{}
+			return resp, e.do(ctx, http.MethodPost, fmt.Sprintf("/v1/todo/%d:complete", e.ids["standup"]), &v1.CompleteRequest{Api: "v1"}, resp)
+		},
+	},
+	{
+		name: "ListRevisions",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.ListRevisions(ctx, &v1.ListRevisionsRequest{Api: "v1", Id: e.ids["milk"]})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.ListRevisionsResponse{}
+			return resp, e.do(ctx, http.MethodGet, fmt.Sprintf("/v1/todo/%d/revisions?api=v1", e.ids["milk"]), nil, resp)
+		},
+	},
+	{
+		name: "ReadAt",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			at, _ := ptypes.TimestampProto(time.Now().Add(time.Hour))
+			return e.todo.ReadAt(ctx, &v1.ReadAtRequest{Api: "v1", Id: e.ids["milk"], Time: at})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			q := url.Values{"api": {"v1"}, "time": {timestamp(time.Now().Add(time.Hour))}}
+			resp := &v1.ReadAtResponse{}
+			return resp, e.do(ctx, http.MethodGet, fmt.Sprintf("/v1/todo/%d:readAt?%s", e.ids["milk"], q.Encode()), nil, resp)
+		},
+	},
+	{
+		name: "Revert",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.Revert(ctx, &v1.RevertRequest{Api: "v1", Id: e.ids["milk"], Revision: 1})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.RevertResponse{}
+			return resp, e.do(ctx, http.MethodPost, fmt.Sprintf("/v1/todo/%d:revert", e.ids["milk"]), &v1.RevertRequest{Api: "v1", Revision: 1}, resp)
+		},
+	},
+	{
+		name: "Delete",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.Delete(ctx, &v1.DeleteRequest{Api: "v1", Id: e.ids["milk"]})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.DeleteResponse{}
+			return resp, e.do(ctx, http.MethodDelete, fmt.Sprintf("/v1/todo/%d?api=v1", e.ids["milk"]), nil, resp)
+		},
+	},
+	{
+		name: "Read deleted",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.Read(ctx, &v1.ReadRequest{Api: "v1", Id: e.ids["milk"], ShowDeleted: true})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.ReadResponse{}
+			return resp, e.do(ctx, http.MethodGet, fmt.Sprintf("/v1/todo/%d?api=v1&showDeleted=true", e.ids["milk"]), nil, resp)
+		},
+	},
+	{
+		name: "Undelete",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.Undelete(ctx, &v1.UndeleteRequest{Api: "v1", Id: e.ids["milk"]})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.UndeleteResponse{}
+			return resp, e.do(ctx, http.MethodPost, fmt.Sprintf("/v1/todo/%d:undelete", e.ids["milk"]), &v1.UndeleteRequest{Api: "v1"}, resp)
+		},
+	},
+	{
+		name: "Purge not deleted",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.Purge(ctx, &v1.PurgeRequest{Api: "v1", Id: e.ids["milk"]})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.PurgeResponse{}
+			return resp, e.do(ctx, http.MethodPost, fmt.Sprintf("/v1/todo/%d:purge", e.ids["milk"]), &v1.PurgeRequest{Api: "v1"}, resp)
+		},
+	},
+	{
+		name: "BatchCreate",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.BatchCreate(ctx, &v1.BatchCreateRequest{Api: "v1", ToDos: []*v1.ToDo{toDo("bread"), toDo("eggs"), {}}, BestEffort: true})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.BatchCreateResponse{}
+			return resp, e.do(ctx, http.MethodPost, "/v1/todo:batchCreate",
+				&v1.BatchCreateRequest{Api: "v1", ToDos: []*v1.ToDo{toDo("bread"), toDo("eggs"), {}}, BestEffort: true}, resp)
+		},
+	},
+	{
+		name: "BatchUpdate",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.BatchUpdate(ctx, &v1.BatchUpdateRequest{Api: "v1", ToDos: batchUpdate(e)})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.BatchUpdateResponse{}
+			return resp, e.do(ctx, http.MethodPost, "/v1/todo:batchUpdate", &v1.BatchUpdateRequest{Api: "v1", ToDos: batchUpdate(e)}, resp)
+		},
+	},
+	{
+		name: "BatchDelete",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.BatchDelete(ctx, &v1.BatchDeleteRequest{Api: "v1", Ids: []int64{e.ids["milk"] + 2, 999}, BestEffort: true})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.BatchDeleteResponse{}
+			return resp, e.do(ctx, http.MethodPost, "/v1/todo:batchDelete",
+				&v1.BatchDeleteRequest{Api: "v1", Ids: []int64{e.ids["milk"] + 2, 999}, BestEffort: true}, resp)
+		},
+	},
+	{
+		name: "ImportICS",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.ImportICS(ctx, &v1.ImportICSRequest{Api: "v1", Calendar: calendar})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.ImportICSResponse{}
+			return resp, e.do(ctx, http.MethodPost, "/v1/todo:importIcs", &v1.ImportICSRequest{Api: "v1", Calendar: calendar}, resp)
+		},
+	},
+	{
+		name: "GetCalendarToken",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.todo.GetCalendarToken(ctx, &v1.GetCalendarTokenRequest{Api: "v1"})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.GetCalendarTokenResponse{}
+			return resp, e.do(ctx, http.MethodGet, "/v1/todo:calendarToken?api=v1", nil, resp)
+		},
+	},
+	{
+		name: "ExportICS",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			token, err := e.todo.GetCalendarToken(ctx, &v1.GetCalendarTokenRequest{Api: "v1"})
+			if err != nil {
+				return nil, err
+			}
+			resp, err := e.todo.ExportICS(ctx, &v1.ExportICSRequest{Api: "v1", User: token.User, Token: token.Token})
+			if err != nil {
+				return nil, err
+			}
+			return resp.Calendar, nil
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			token := &v1.GetCalendarTokenResponse{}
+			if err := e.do(ctx, http.MethodGet, "/v1/todo:calendarToken?api=v1", nil, token); err != nil {
+				return nil, err
+			}
+			q := url.Values{"user": {token.User}, "token": {token.Token}}
+			return e.download(ctx, http.MethodGet, "/v1/todo/calendar.ics?"+q.Encode(), nil)
+		},
+	},
+	{
+		name: "Export",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			stream, err := e.todo.Export(ctx, &v1.ExportRequest{Api: "v1", Format: v1.DataFormat_CSV})
+			if err != nil {
+				return nil, err
+			}
+			var data bytes.Buffer
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					return data.String(), nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				data.Write(resp.Data)
+			}
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.download(ctx, http.MethodGet, "/v1/todo:export?format=csv", nil)
+		},
+	},
+	{
+		name: "Import",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			stream, err := e.todo.Import(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if err := stream.Send(&v1.ImportRequest{Api: "v1", Format: v1.DataFormat_JSONL, Data: []byte(importData)}); err != nil {
+				return nil, err
+			}
+			return stream.CloseAndRecv()
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			data, err := e.download(ctx, http.MethodPost, "/v1/todo:import?format=jsonl", strings.NewReader(importData))
+			if err != nil {
+				return nil, err
+			}
+			resp := &v1.ImportResponse{}
+			return resp, jsonpb.UnmarshalString(data, resp)
+		},
+	},
+	{
+		name: "Watch",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			stream, err := e.todo.Watch(ctx, &v1.WatchRequest{Api: "v1", SinceSequence: 1})
+			if err != nil {
+				return nil, err
+			}
+			var events []proto.Message
+			for len(events) < 3 {
+				resp, err := stream.Recv()
+				if err != nil {
+					return events, err
+				}
+				events = append(events, resp)
+			}
+			return events, nil
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.stream(ctx, "/v1/todo/watch?api=v1&sinceSequence=1", 3, func() proto.Message { return &v1.WatchResponse{} })
+		},
+	},
+	{
+		name: "CreateWebhook",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			resp, err := e.webhook.CreateWebhook(ctx, &v1.CreateWebhookRequest{Api: "v1", Webhook: webhook})
+			if err == nil {
+				e.ids["webhook"] = resp.Id
+			}
+			return resp, err
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.CreateWebhookResponse{}
+			err := e.do(ctx, http.MethodPost, "/v1/webhook", &v1.CreateWebhookRequest{Api: "v1", Webhook: webhook}, resp)
+			if err == nil {
+				e.ids["webhook"] = resp.Id
+			}
+			return resp, err
+		},
+	},
+	{
+		name: "ListWebhooks",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.webhook.ListWebhooks(ctx, &v1.ListWebhooksRequest{Api: "v1"})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.ListWebhooksResponse{}
+			return resp, e.do(ctx, http.MethodGet, "/v1/webhook?api=v1", nil, resp)
+		},
+	},
+	{
+		name: "ListWebhookDeliveries",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.webhook.ListWebhookDeliveries(ctx, &v1.ListWebhookDeliveriesRequest{Api: "v1", WebhookId: e.ids["webhook"], Limit: 10})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.ListWebhookDeliveriesResponse{}
+			return resp, e.do(ctx, http.MethodGet, fmt.Sprintf("/v1/webhook/%d/deliveries?api=v1&limit=10", e.ids["webhook"]), nil, resp)
+		},
+	},
+	{
+		name: "DeleteWebhook",
+		grpc: func(ctx context.Context, e *env) (interface{}, error) {
+			return e.webhook.DeleteWebhook(ctx, &v1.DeleteWebhookRequest{Api: "v1", Id: e.ids["webhook"]})
+		},
+		rest: func(ctx context.Context, e *env) (interface{}, error) {
+			resp := &v1.DeleteWebhookResponse{}
+			return resp, e.do(ctx, http.MethodDelete, fmt.Sprintf("/v1/webhook/%d?api=v1", e.ids["webhook"]), nil, resp)
+		},
+	},
+}
+
+const calendar = "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//e2e//EN\r\n" +
+	"BEGIN:VTODO\r\nUID:dentist@e2e\r\nSUMMARY:dentist\r\nDUE:20300110T080000Z\r\nEND:VTODO\r\n" +
+	"END:VCALENDAR\r\n"
+
+const importData = `{"externalId":"x-1","title":"imported","reminder":"2030-01-08T09:00:00Z"}
+{"externalId":"x-1","title":"imported again","reminder":"2030-01-08T09:00:00Z"}
+{"externalId":"x-2","title":""}
+`
+
+var webhook = &v1.Webhook{Url: "https://example.com/hook", Events: []v1.ToDoEvent_Type{v1.ToDoEvent_CREATED}}
+
+// batchUpdate 修改 BatchCreate 创建的第一个 ToDo 和一个不存在的 ToDo
+func batchUpdate(e *env) []*v1.ToDo {
+	bread, missing := toDo("rye bread"), toDo("nothing")
+	bread.Id, missing.Id = e.ids["milk"]+2, 999
+	return []*v1.ToDo{bread, missing}
+}
+
+// TestContract 分别通过 gRPC 和 REST 网关执行同样的场景，比较每一步的结果和错误码
+func TestContract(t *testing.T) {
+	grpcEnv, restEnv := newEnv(t), newEnv(t)
+	defer grpcEnv.server.Close()
+	defer restEnv.server.Close()
+
+	for _, s := range steps {
+		ctx, cancel := context.WithTimeout(metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+Token), 10*time.Second)
+		gotGRPC, errGRPC := s.grpc(ctx, grpcEnv)
+		gotREST, errREST := s.rest(ctx, restEnv)
+		cancel()
+
+		g, r := normalize(t, gotGRPC, errGRPC), normalize(t, gotREST, errREST)
+		if !reflect.DeepEqual(g, r) {
+			t.Errorf("%s:\ngRPC = %s\nREST = %s", s.name, dump(g), dump(r))
+		}
+	}
+}
+
+// TestSteps 检查场景中的关键结果，避免两边同样出错时 TestContract 仍然通过
+func TestSteps(t *testing.T) {
+	e := newEnv(t)
+	defer e.server.Close()
+
+	results := make(map[string]interface{})
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+Token)
+	for _, s := range steps {
+		got, err := s.rest(ctx, e)
+		results[s.name] = normalize(t, got, err)
+	}
+
+	for name, want := range map[string]string{
+		"Create with the same request ID":     fmt.Sprintf(`{"api":"v1","id":"%d"}`, e.ids["milk"]),
+		"Create with unsupported API version": `"error: Unimplemented"`,
+		"Read missing":                        `"error: NotFound"`,
+		"Purge not deleted":                   `"error: NotFound"`,
+		"Delete":                              `{"api":"v1","deleted":"1"}`,
+		"Undelete":                            `{"api":"v1","undeleted":"1"}`,
+	} {
+		if got := dump(results[name]); got != want {
+			t.Errorf("%s = %s, want %s", name, got, want)
+		}
+	}
+}
+
+func newEnv(t *testing.T) *env {
+	s, err := Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	return &env{server: s, todo: s.ToDoClient(), webhook: s.WebhookClient(), http: &http.Client{}, ids: make(map[string]int64)}
+}
+
+// newRequest 创建发送给网关的请求，带上与 gRPC 调用相同的用户
+func (e *env) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, e.server.URL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+Token)
+	return req.WithContext(ctx), nil
+}
+
+// do 调用网关的 JSON 接口，错误响应转换为 gRPC status 错误
+func (e *env) do(ctx context.Context, method, path string, in, out proto.Message) error {
+	var body io.Reader
+	if in != nil {
+		data, err := (&jsonpb.Marshaler{}).MarshalToString(in)
+		if err != nil {
+			return err
+		}
+		body = strings.NewReader(data)
+	}
+	data, err := e.download(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	return jsonpb.UnmarshalString(data, out)
+}
+
+// download 调用网关并返回响应体
+func (e *env) download(ctx context.Context, method, path string, body io.Reader) (string, error) {
+	req, err := e.newRequest(ctx, method, path, body)
+	if err != nil {
+		return "", err
+	}
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", gatewayError(resp.StatusCode, data)
+	}
+	return string(data), nil
+}
+
+// stream 读取网关流式接口输出的前 n 个结果，n 为 -1 时读取全部
+func (e *env) stream(ctx context.Context, path string, n int, newMessage func() proto.Message) ([]proto.Message, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := e.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var messages []proto.Message
+	scanner := bufio.NewScanner(resp.Body)
+	for (n < 0 || len(messages) < n) && scanner.Scan() {
+		var line struct {
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return messages, err
+		}
+		if line.Error != nil {
+			return messages, gatewayError(http.StatusOK, scanner.Bytes())
+		}
+		m := newMessage()
+		if err := jsonpb.UnmarshalString(string(line.Result), m); err != nil {
+			return messages, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, scanner.Err()
+}
+
+// gatewayError 把网关的错误响应转换为 gRPC status 错误
+func gatewayError(statusCode int, body []byte) error {
+	var e struct {
+		Error struct {
+			Code    int32  `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil || e.Error.Code == 0 {
+		return fmt.Errorf("HTTP %d: %s", statusCode, body)
+	}
+	return status.Error(codes.Code(e.Error.Code), e.Error.Message)
+}
+
+// normalize 把结果转换为可以比较的 JSON 值: 去掉与时间有关的字段，错误只保留错误码
+func normalize(t *testing.T, v interface{}, err error) interface{} {
+	if err != nil {
+		s, ok := status.FromError(err)
+		if !ok {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return "error: " + s.Code().String()
+	}
+
+	switch v := v.(type) {
+	case string:
+		// DTSTAMP 是生成日历的时间
+		var lines []string
+		for _, l := range strings.Split(v, "\r\n") {
+			if !strings.HasPrefix(l, "DTSTAMP:") {
+				lines = append(lines, l)
+			}
+		}
+		return strings.Join(lines, "\n")
+	case []proto.Message:
+		var all []interface{}
+		for _, m := range v {
+			all = append(all, normalize(t, m, nil))
+		}
+		return all
+	case proto.Message:
+		data, err := (&jsonpb.Marshaler{}).MarshalToString(v)
+		if err != nil {
+			t.Fatalf("failed to marshal %v: %v", v, err)
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(data), &value); err != nil {
+			t.Fatalf("failed to unmarshal %s: %v", data, err)
+		}
+		return clearVolatile(value)
+	}
+	t.Fatalf("unexpected result %T", v)
+	return nil
+}
+
+func clearVolatile(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if volatileFields[k] {
+				v[k] = "*"
+			} else {
+				v[k] = clearVolatile(field)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = clearVolatile(v[i])
+		}
+	}
+	return v
+}
+
+func dump(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// TestReadRoutes 检查 /v1/todo/all 不会被当作 Read 的 ID，以及已废弃的 /v1/dodo/{id} 仍然可用
+func TestReadRoutes(t *testing.T) {
+	e := newEnv(t)
+	defer e.server.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+Token)
+	created, err := e.todo.Create(ctx, &v1.CreateRequest{Api: "v1", ToDo: toDo("milk")})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	all := &v1.ReadAllResponse{}
+	if err := e.do(ctx, http.MethodGet, "/v1/todo/all?api=v1", nil, all); err != nil || len(all.ToDos) != 1 {
+		t.Errorf("GET /v1/todo/all = %v, %v, want ReadAll response", all, err)
+	}
+	if err := e.do(ctx, http.MethodGet, "/v1/todo/milk?api=v1", nil, &v1.ReadResponse{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GET /v1/todo/milk error = %v, want InvalidArgument", err)
+	}
+
+	counter := func() int64 {
+		v, _ := expvar.Get("rest_deprecated_requests").(*expvar.Map).Get("GET /v1/dodo/{id}").(*expvar.Int)
+		if v == nil {
+			return 0
+		}
+		return v.Value()
+	}
+	before := counter()
+
+	path := fmt.Sprintf("/v1/dodo/%d", created.Id)
+	req, err := e.newRequest(ctx, http.MethodGet, path+"?api=v1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := e.http.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	read := &v1.ReadResponse{}
+	if err := jsonpb.Unmarshal(resp.Body, read); err != nil || resp.StatusCode != http.StatusOK || read.ToDo.GetTitle() != "milk" {
+		t.Errorf("GET %s = %d %v, %v, want the ToDo", path, resp.StatusCode, read, err)
+	}
+	if resp.Header.Get("Deprecation") == "" {
+		t.Errorf("GET %s has no Deprecation header", path)
+	}
+	if want := fmt.Sprintf(`</v1/todo/%d>; rel="successor-version"`, created.Id); resp.Header.Get("Link") != want {
+		t.Errorf("GET %s Link = %q, want %q", path, resp.Header.Get("Link"), want)
+	}
+	if got := counter(); got != before+1 {
+		t.Errorf("deprecated requests = %d, want %d", got, before+1)
+	}
+}
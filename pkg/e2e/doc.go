@@ -0,0 +1,7 @@
+// Package e2e 在进程内启动完整的 ToDo 服务，用于端到端测试
+//
+// gRPC 服务器监听在 bufconn 上，REST 网关通过 httptest 启动并经由同一个 bufconn 调用 gRPC 服务，
+// 数据保存在临时的 SQLite 数据库中，表结构在测试时由 sql/schema.sql 转换得到。
+//
+// 测试代码都在 _test.go 文件中，依赖 cgo 的 SQLite 驱动只在 go test 时编译，go build ./... 不需要 cgo。
+package e2e
@@ -0,0 +1,119 @@
+package e2e
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"net/http/httptest"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v2"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/auth"
+	grpcserver "go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/protocol/grpc"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/protocol/rest"
+	service "go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/service/v1"
+	servicev2 "go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/service/v2"
+)
+
+const (
+	// CalendarSecret 是测试服务器签发日历订阅令牌的密钥
+	CalendarSecret = "e2e-calendar-secret"
+
+	// User 是测试服务器认证的用户，客户端以 Bearer 令牌发送 Token
+	User  = "alice"
+	Token = "e2e-alice-token"
+
+	bufSize = 1024 * 1024
+)
+
+// Server 是进程内的 ToDo 服务
+type Server struct {
+	// DB 是服务使用的数据库
+	DB *sql.DB
+	// Conn 是到 gRPC 服务器的连接
+	Conn *grpc.ClientConn
+	// URL 是 REST 网关的地址，例如 http://127.0.0.1:12345
+	URL string
+	// Hub 是服务发布 ToDo 事件的 Hub，后台任务也使用它
+	Hub *service.Hub
+
+	cancel  context.CancelFunc
+	cleanup []func()
+}
+
+// Start 启动 gRPC 服务器和 REST 网关，用完后需要调用 Close
+func Start() (*Server, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{cancel: cancel}
+
+	db, closeDB, err := OpenStore()
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.DB = db
+	s.cleanup = append(s.cleanup, closeDB)
+
+	tokens, err := auth.NewTokens(map[string]string{User: Token})
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	s.Hub = service.NewHub(service.DefaultHubHistory)
+	v1API := service.NewToDoServiceServer(db, s.Hub, service.DefaultIdempotencyWindow, CalendarSecret)
+	server := grpcserver.NewServer(v1API, servicev2.NewToDoServiceServer(v1API), service.NewWebhookServiceServer(db), tokens.ServerOptions()...)
+	lis := bufconn.Listen(bufSize)
+	go server.Serve(lis)
+	s.cleanup = append(s.cleanup, server.Stop)
+
+	conn, err := grpc.DialContext(ctx, "bufconn",
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.Conn = conn
+	s.cleanup = append(s.cleanup, func() { conn.Close() })
+
+	handler, err := rest.NewHandler(ctx, conn, "", nil)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	gateway := httptest.NewServer(handler)
+	s.URL = gateway.URL
+	s.cleanup = append(s.cleanup, gateway.Close)
+
+	return s, nil
+}
+
+// ToDoClient 返回 ToDoService 的 gRPC 客户端
+func (s *Server) ToDoClient() v1.ToDoServiceClient {
+	return v1.NewToDoServiceClient(s.Conn)
+}
+
+// ToDoV2Client 返回 v2 ToDoService 的 gRPC 客户端
+func (s *Server) ToDoV2Client() v2.ToDoServiceClient {
+	return v2.NewToDoServiceClient(s.Conn)
+}
+
+// WebhookClient 返回 WebhookService 的 gRPC 客户端
+func (s *Server) WebhookClient() v1.WebhookServiceClient {
+	return v1.NewWebhookServiceClient(s.Conn)
+}
+
+// Close 按启动的相反顺序停止网关、gRPC 服务器并删除数据库
+func (s *Server) Close() {
+	s.cancel()
+	for i := len(s.cleanup) - 1; i >= 0; i-- {
+		s.cleanup[i]()
+	}
+	s.cleanup = nil
+}
@@ -0,0 +1,212 @@
+package e2e
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// driverName 是把 MySQL 语句改写为 SQLite 语句的数据库驱动
+const driverName = "sqlite3-mysql"
+
+func init() {
+	sql.Register(driverName, &mysqlDialect{&sqlite3.SQLiteDriver{}})
+}
+
+// schemaFile 是服务使用的 MySQL 表结构，OpenStore 把它转换为 SQLite 的表结构
+const schemaFile = "../../sql/schema.sql"
+
+var (
+	createTable   = regexp.MustCompile("^CREATE TABLE (`\\w+`) \\($")
+	tableOptions  = regexp.MustCompile(`^\) ENGINE=.*$`)
+	autoIncrement = regexp.MustCompile("^(`\\w+`) bigint\\(20\\) NOT NULL AUTO_INCREMENT$")
+	primaryKey    = regexp.MustCompile("^PRIMARY KEY \\((`\\w+`)\\)$")
+	indexKey      = regexp.MustCompile("^(UNIQUE )?KEY `(\\w+)` (\\(.*\\))$")
+	// SQLite 驱动只把声明类型正好是 timestamp 的列读取为 time.Time
+	timestampType = regexp.MustCompile(`\btimestamp\(\d+\)`)
+)
+
+// sqliteSchema 把 schema.sql 中的 CREATE TABLE 语句转换为 SQLite 语句
+// 自增的 bigint 主键改为 integer PRIMARY KEY AUTOINCREMENT，KEY 和 UNIQUE KEY 改为单独的 CREATE INDEX，
+// 去掉 timestamp 的精度和表选项
+func sqliteSchema(mysql string) ([]string, error) {
+	var lines []string
+	for _, line := range strings.Split(mysql, "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 && !strings.HasPrefix(line, "--") {
+			lines = append(lines, line)
+		}
+	}
+
+	var stmts []string
+	for _, stmt := range strings.Split(strings.Join(lines, "\n"), ";") {
+		lines := strings.Split(strings.TrimSpace(stmt), "\n")
+		if len(lines) == 1 && len(lines[0]) == 0 {
+			continue
+		}
+		m := createTable.FindStringSubmatch(lines[0])
+		if m == nil || len(lines) < 3 || !tableOptions.MatchString(lines[len(lines)-1]) {
+			return nil, fmt.Errorf("unsupported statement in %s: %s", schemaFile, lines[0])
+		}
+		table := m[1]
+
+		var columns, indexes []string
+		var autoKey string
+		for _, line := range lines[1 : len(lines)-1] {
+			line = strings.TrimSuffix(line, ",")
+			if m := autoIncrement.FindStringSubmatch(line); m != nil {
+				autoKey = m[1]
+				columns = append(columns, m[1]+" integer PRIMARY KEY AUTOINCREMENT")
+			} else if m := primaryKey.FindStringSubmatch(line); m != nil {
+				// 自增列已经是主键
+				if m[1] != autoKey {
+					columns = append(columns, line)
+				}
+			} else if m := indexKey.FindStringSubmatch(line); m != nil {
+				name := "`" + strings.Trim(table, "`") + "_" + m[2] + "`"
+				indexes = append(indexes, "CREATE "+m[1]+"INDEX "+name+" ON "+table+" "+m[3])
+			} else {
+				columns = append(columns, timestampType.ReplaceAllString(line, "timestamp"))
+			}
+		}
+		stmts = append(stmts, "CREATE TABLE "+table+" ("+strings.Join(columns, ", ")+")")
+		stmts = append(stmts, indexes...)
+	}
+	return stmts, nil
+}
+
+// OpenStore 在临时目录中创建 SQLite 数据库并建表，返回的 cleanup 函数关闭并删除数据库
+//
+// 事务以 IMMEDIATE 模式开始，同一时间只有一个写事务，相当于 MySQL 中的 SELECT ... FOR UPDATE
+func OpenStore() (*sql.DB, func(), error) {
+	dir, err := ioutil.TempDir("", "todo-e2e")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dsn := "file:" + filepath.Join(dir, "todo.db") + "?_busy_timeout=10000&_journal_mode=WAL&_txlock=immediate&_foreign_keys=1&_loc=UTC"
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, err
+	}
+	cleanup := func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+
+	mysql, err := ioutil.ReadFile(schemaFile)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	schema, err := sqliteSchema(string(mysql))
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("failed to create schema: %v", err)
+		}
+	}
+	return db, cleanup, nil
+}
+
+// mysqlDialect 把服务使用的 MySQL 语句改写为 SQLite 语句
+type mysqlDialect struct {
+	*sqlite3.SQLiteDriver
+}
+
+func (d *mysqlDialect) Open(dsn string) (driver.Conn, error) {
+	c, err := d.SQLiteDriver.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{c.(*sqlite3.SQLiteConn)}, nil
+}
+
+var (
+	forUpdate       = regexp.MustCompile("\\s+FOR UPDATE(\\s+OF\\s+`?\\w+`?(\\s*,\\s*`?\\w+`?)*)?(\\s+SKIP LOCKED|\\s+NOWAIT)?")
+	onDuplicateKey  = regexp.MustCompile(`^INSERT INTO (\w+)(.*) ON DUPLICATE KEY UPDATE (.*)$`)
+	valuesFunction  = regexp.MustCompile("VALUES\\((`\\w+`)\\)")
+	likePlaceholder = regexp.MustCompile(`LIKE \?`)
+)
+
+// conflictTargets 是 ON DUPLICATE KEY UPDATE 改写为 ON CONFLICT 时各表的唯一键
+var conflictTargets = map[string]string{
+	"Idempotency": "`Key`",
+}
+
+// rewrite 改写 SQLite 不支持的 MySQL 语法
+func rewrite(query string) string {
+	// SQLite 的写事务是互斥的，不需要行锁; 去掉整个锁定子句，包括 OF 和 SKIP LOCKED
+	query = forUpdate.ReplaceAllString(query, "")
+
+	if m := onDuplicateKey.FindStringSubmatch(query); m != nil {
+		query = "INSERT INTO " + m[1] + m[2] + " ON CONFLICT(" + conflictTargets[m[1]] + ") DO UPDATE SET " +
+			valuesFunction.ReplaceAllString(m[3], "excluded.$1")
+	}
+
+	// MySQL 的 LIKE 默认以反斜杠转义
+	return likePlaceholder.ReplaceAllString(query, `LIKE ? ESCAPE '\'`)
+}
+
+// args 把时间参数转换为 UTC 并截断到微秒，与 MySQL 的 timestamp(6) 一致，保证按字符串比较时间的结果正确
+func args(in []driver.NamedValue) []driver.NamedValue {
+	out := make([]driver.NamedValue, len(in))
+	for i, a := range in {
+		if t, ok := a.Value.(time.Time); ok {
+			a.Value = t.UTC().Truncate(time.Microsecond)
+		}
+		out[i] = a
+	}
+	return out
+}
+
+// conn 在执行语句前改写语句和参数
+type conn struct {
+	*sqlite3.SQLiteConn
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	s, err := c.SQLiteConn.PrepareContext(ctx, rewrite(query))
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{s.(*sqlite3.SQLiteStmt)}, nil
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, a []driver.NamedValue) (driver.Rows, error) {
+	return c.SQLiteConn.QueryContext(ctx, rewrite(query), args(a))
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, a []driver.NamedValue) (driver.Result, error) {
+	return c.SQLiteConn.ExecContext(ctx, rewrite(query), args(a))
+}
+
+// stmt 是改写后的预编译语句
+type stmt struct {
+	*sqlite3.SQLiteStmt
+}
+
+func (s *stmt) QueryContext(ctx context.Context, a []driver.NamedValue) (driver.Rows, error) {
+	return s.SQLiteStmt.QueryContext(ctx, args(a))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, a []driver.NamedValue) (driver.Result, error) {
+	return s.SQLiteStmt.ExecContext(ctx, args(a))
+}
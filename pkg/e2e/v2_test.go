@@ -0,0 +1,113 @@
+package e2e
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v2"
+)
+
+// TestV2 检查 v2 和 v1 读写同一份数据: 一个版本的修改在另一个版本中可见
+func TestV2(t *testing.T) {
+	e := newEnv(t)
+	defer e.server.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+Token)
+	todoV2 := e.server.ToDoV2Client()
+
+	// v2 REST 创建，v1 gRPC 读取
+	created := &v2.ToDo{}
+	body := `{"title":"milk","description":"two bottles","reminder":"2030-01-07T09:00:00Z","timeZone":"Europe/Berlin"}`
+	if err := e.doJSON(ctx, http.MethodPost, "/v2/todos", body, created); err != nil {
+		t.Fatalf("POST /v2/todos error = %v", err)
+	}
+	if created.Name != "todos/1" || created.ReminderStatus != v2.ToDo_PENDING {
+		t.Errorf("POST /v2/todos = %v, want todos/1 with pending reminder", created)
+	}
+	read, err := e.todo.Read(ctx, &v1.ReadRequest{Api: "v1", Id: 1})
+	if err != nil || read.ToDo.Title != "milk" || read.ToDo.TimeZone != "Europe/Berlin" {
+		t.Errorf("v1 Read() = %v, %v, want the ToDo created with v2", read, err)
+	}
+
+	// PATCH 没有 updateMask 时只修改请求体中的字段
+	patched := &v2.ToDo{}
+	if err := e.doJSON(ctx, http.MethodPatch, "/v2/todos/1", `{"title":"oat milk"}`, patched); err != nil {
+		t.Fatalf("PATCH /v2/todos/1 error = %v", err)
+	}
+	if patched.Title != "oat milk" || patched.Description != "two bottles" {
+		t.Errorf("PATCH /v2/todos/1 = %v, want only title changed", patched)
+	}
+	patched, err = todoV2.UpdateToDo(ctx, &v2.UpdateToDoRequest{
+		ToDo:       &v2.ToDo{Name: "todos/1", Title: "ignored"},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"description"}},
+	})
+	if err != nil || patched.Title != "oat milk" || len(patched.Description) > 0 {
+		t.Errorf("UpdateToDo(updateMask=description) = %v, %v, want only description cleared", patched, err)
+	}
+
+	// v1 修改，v2 gRPC 读取
+	update := &v1.ToDo{Id: 1, Title: "soy milk", Reminder: created.Reminder, TimeZone: "Europe/Berlin"}
+	if _, err := e.todo.Update(ctx, &v1.UpdateRequest{Api: "v1", ToDo: update}); err != nil {
+		t.Fatalf("v1 Update() error = %v", err)
+	}
+	got, err := todoV2.GetToDo(ctx, &v2.GetToDoRequest{Name: "todos/1"})
+	if err != nil || got.Title != "soy milk" {
+		t.Errorf("GetToDo() = %v, %v, want the ToDo updated with v1", got, err)
+	}
+
+	// v2 删除后 v1 读不到，恢复后又可以读到
+	deleted := &v2.ToDo{}
+	if err := e.doJSON(ctx, http.MethodDelete, "/v2/todos/1", "", deleted); err != nil || deleted.DeleteTime == nil {
+		t.Errorf("DELETE /v2/todos/1 = %v, %v, want ToDo with deleteTime", deleted, err)
+	}
+	if _, err := e.todo.Read(ctx, &v1.ReadRequest{Api: "v1", Id: 1}); status.Code(err) != codes.NotFound {
+		t.Errorf("v1 Read() of deleted ToDo error = %v, want NotFound", err)
+	}
+	undeleted := &v2.ToDo{}
+	if err := e.doJSON(ctx, http.MethodPost, "/v2/todos/1:undelete", "{}", undeleted); err != nil || undeleted.DeleteTime != nil {
+		t.Errorf("POST /v2/todos/1:undelete = %v, %v, want ToDo without deleteTime", undeleted, err)
+	}
+
+	// v1 创建的 ToDo 出现在 v2 的列表中
+	if _, err := e.todo.Create(ctx, &v1.CreateRequest{Api: "v1", ToDo: toDo("bread")}); err != nil {
+		t.Fatalf("v1 Create() error = %v", err)
+	}
+	page := &v2.ListToDosResponse{}
+	if err := e.doJSON(ctx, http.MethodGet, "/v2/todos?pageSize=1", "", page); err != nil {
+		t.Fatalf("GET /v2/todos error = %v", err)
+	}
+	if len(page.ToDos) != 1 || page.ToDos[0].Name != "todos/1" || len(page.NextPageToken) == 0 {
+		t.Fatalf("GET /v2/todos?pageSize=1 = %v, want first page", page)
+	}
+	next, err := todoV2.ListToDos(ctx, &v2.ListToDosRequest{PageSize: 1, PageToken: page.NextPageToken})
+	if err != nil || len(next.ToDos) != 1 || next.ToDos[0].Title != "bread" || len(next.NextPageToken) > 0 {
+		t.Errorf("ListToDos() second page = %v, %v, want the ToDo created with v1", next, err)
+	}
+
+	if err := e.doJSON(ctx, http.MethodGet, "/v2/todos/milk", "", &v2.ToDo{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GET /v2/todos/milk error = %v, want InvalidArgument", err)
+	}
+	if _, err := e.todo.Read(ctx, &v1.ReadRequest{Api: "v2", Id: 1}); status.Code(err) != codes.Unimplemented ||
+		!strings.Contains(status.Convert(err).Message(), "v2.ToDoService") {
+		t.Errorf("v1 Read() with api v2 error = %v, want Unimplemented pointing to v2.ToDoService", err)
+	}
+}
+
+// doJSON 以 body 为请求体调用网关，把 JSON 响应解析到 out
+func (e *env) doJSON(ctx context.Context, method, path, body string, out proto.Message) error {
+	data, err := e.download(ctx, method, path, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return jsonpb.UnmarshalString(data, out)
+}
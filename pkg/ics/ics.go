@@ -0,0 +1,470 @@
+// Package ics 在 ToDo 和 RFC 5545 iCalendar 之间转换
+//
+// 导出时每个 ToDo 是一个 VTODO 或 VEVENT，提醒编码为在开始或到期时刻触发的 VALARM，重复规则编码为 DTSTART/RRULE/EXDATE。
+// 导入时读取 VTODO 和 VEVENT，第一个 VALARM 的 TRIGGER 决定提醒时间，没有 VALARM 时在到期时刻提醒。
+package ics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/ptypes"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// prodID 是导出的日历的 PRODID
+	prodID = "-//go-grpc-http-rest-microservice-tutorial//ToDo//EN"
+	// uidDomain 是导出的组件 UID 的域名部分
+	uidDomain = "todo.xiaosongfu.com"
+
+	// maxLineLength 是折行前一行的最大字节数，不包括 CRLF
+	maxLineLength = 75
+
+	dateTimeLayout = "20060102T150405"
+	dateLayout     = "20060102"
+)
+
+// Component 是 ToDo 导出为的日历组件类型
+type Component string
+
+const (
+	// VTodo 导出为待办事项，日历应用在任务列表中显示
+	VTodo Component = "VTODO"
+	// VEvent 导出为事件，不支持 VTODO 的日历应用也能显示
+	VEvent Component = "VEVENT"
+)
+
+// Encode 把 todos 编码为一个 VCALENDAR; name 不为空时作为日历的显示名称，now 是 DTSTAMP
+func Encode(w io.Writer, todos []*v1.ToDo, component Component, name string, now time.Time) error {
+	if component != VTodo && component != VEvent {
+		return fmt.Errorf("unsupported component %q", component)
+	}
+
+	e := &encoder{w: bufio.NewWriter(w)}
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + prodID)
+	e.line("CALSCALE:GREGORIAN")
+	if len(name) > 0 {
+		e.line("X-WR-CALNAME:" + escape(name))
+	}
+	for _, todo := range todos {
+		if err := e.todo(todo, component, now); err != nil {
+			return err
+		}
+	}
+	e.line("END:VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line 写入一个内容行，超过 maxLineLength 字节时在 UTF-8 字符边界折行
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	limit := maxLineLength
+	for len(s) > limit {
+		n := limit
+		for n > 0 && !utf8.RuneStart(s[n]) {
+			n--
+		}
+		_, e.err = e.w.WriteString(s[:n] + "\r\n ")
+		s = s[n:]
+		// 续行开头的空格也计入长度
+		limit = maxLineLength - 1
+	}
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s + "\r\n")
+	}
+}
+
+func (e *encoder) todo(todo *v1.ToDo, component Component, now time.Time) error {
+	e.line("BEGIN:" + string(component))
+	e.line(fmt.Sprintf("UID:todo-%d@%s", todo.Id, uidDomain))
+	e.line("DTSTAMP:" + now.UTC().Format(dateTimeLayout) + "Z")
+	e.line("SUMMARY:" + escape(todo.Title))
+	if len(todo.Description) > 0 {
+		e.line("DESCRIPTION:" + escape(todo.Description))
+	}
+
+	if todo.Reminder != nil {
+		reminder, err := ptypes.Timestamp(todo.Reminder)
+		if err != nil {
+			return fmt.Errorf("ToDo with ID='%d' has invalid reminder: %v", todo.Id, err)
+		}
+
+		// 重复的 ToDo 从规则的 DTSTART 开始，否则在当前提醒时间到期
+		start := formatTime(reminder, todo.TimeZone)
+		var rules []string
+		for _, l := range strings.Split(todo.Recurrence, "\n") {
+			l = strings.TrimSpace(l)
+			switch {
+			case strings.HasPrefix(l, "DTSTART"):
+				start = strings.TrimPrefix(zoned("DTSTART", l, todo.TimeZone), "DTSTART")
+			case strings.HasPrefix(l, "EXDATE"):
+				rules = append(rules, zoned("EXDATE", l, todo.TimeZone))
+			case len(l) > 0:
+				rules = append(rules, l)
+			}
+		}
+
+		// RRULE 需要 DTSTART，而 VTODO 的 DUE 必须晚于 DTSTART，所以只有不重复的 VTODO 使用 DUE
+		trigger := "TRIGGER:PT0S"
+		if component == VTodo && len(rules) == 0 {
+			e.line("DUE" + start)
+			trigger = "TRIGGER;RELATED=END:PT0S"
+		} else {
+			e.line("DTSTART" + start)
+		}
+		for _, l := range rules {
+			e.line(l)
+		}
+
+		e.line("BEGIN:VALARM")
+		e.line("ACTION:DISPLAY")
+		e.line("DESCRIPTION:" + escape(todo.Title))
+		e.line(trigger)
+		e.line("END:VALARM")
+	}
+
+	e.line("END:" + string(component))
+	return nil
+}
+
+// formatTime 把 t 格式化为 zone 的 ";TZID=zone:value"，zone 为空或未知时格式化为 UTC 的 ":valueZ"
+func formatTime(t time.Time, zone string) string {
+	if len(zone) > 0 {
+		if loc, err := time.LoadLocation(zone); err == nil && loc != time.UTC {
+			return ";TZID=" + zone + ":" + t.In(loc).Format(dateTimeLayout)
+		}
+	}
+	return ":" + t.UTC().Format(dateTimeLayout) + "Z"
+}
+
+// zoned 给不带时区的 DTSTART/EXDATE 加上 ToDo 的时区; 服务器保存的规则是 timeZone 的墙上时间，
+// 日历程序会按设备的时区解释不带时区的时间
+func zoned(name, line, zone string) string {
+	value := strings.TrimPrefix(line, name)
+	if !strings.HasPrefix(value, ":") || strings.HasSuffix(value, "Z") {
+		return line
+	}
+	values := strings.Split(value[1:], ",")
+	if len(zone) > 0 {
+		if loc, err := time.LoadLocation(zone); err == nil && loc != time.UTC {
+			return name + ";TZID=" + zone + ":" + strings.Join(values, ",")
+		}
+	}
+	for i := range values {
+		values[i] += "Z"
+	}
+	return name + ":" + strings.Join(values, ",")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape 转义 TEXT 类型的值
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+var unescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+// unescape 还原 TEXT 类型的值
+func unescape(s string) string {
+	return unescaper.Replace(s)
+}
+
+// property 是一个内容行
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// component 是解析中的一个 VTODO 或 VEVENT
+type component struct {
+	name  string
+	props map[string][]property
+	// trigger 是第一个 VALARM 的 TRIGGER
+	trigger *property
+}
+
+func (c *component) get(name string) *property {
+	if p := c.props[name]; len(p) > 0 {
+		return &p[0]
+	}
+	return nil
+}
+
+// Decode 解析 VCALENDAR 中的 VTODO 和 VEVENT，其他组件以及已完成或已取消的 VTODO/VEVENT 被忽略
+func Decode(r io.Reader) ([]*v1.ToDo, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		todos []*v1.ToDo
+		stack []string
+		cur   *component
+		n     int
+	)
+	for i, l := range unfold(data) {
+		if len(l) == 0 {
+			continue
+		}
+		p, err := parseLine(l)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+
+		switch p.name {
+		case "BEGIN":
+			name := strings.ToUpper(p.value)
+			stack = append(stack, name)
+			if (name == "VTODO" || name == "VEVENT") && cur == nil {
+				cur = &component{name: name, props: make(map[string][]property)}
+				n++
+			}
+			continue
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1] != strings.ToUpper(p.value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", i+1, p.value)
+			}
+			stack = stack[:len(stack)-1]
+			if cur != nil && len(stack) > 0 && stack[len(stack)-1] == "VCALENDAR" {
+				todo, err := cur.toDo()
+				if err != nil {
+					return nil, fmt.Errorf("%s %d: %v", cur.name, n, err)
+				}
+				if todo != nil {
+					todos = append(todos, todo)
+				}
+				cur = nil
+			}
+			continue
+		}
+
+		if cur == nil {
+			continue
+		}
+		switch stack[len(stack)-1] {
+		case cur.name:
+			cur.props[p.name] = append(cur.props[p.name], p)
+		case "VALARM":
+			if p.name == "TRIGGER" && cur.trigger == nil {
+				trigger := p
+				cur.trigger = &trigger
+			}
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1])
+	}
+	return todos, nil
+}
+
+// unfold 把数据分成内容行并合并以空格或制表符开头的续行
+func unfold(data []byte) []string {
+	var lines []string
+	for _, l := range strings.Split(string(bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)), "\n") {
+		if len(lines) > 0 && len(l) > 0 && (l[0] == ' ' || l[0] == '\t') {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		lines = append(lines, strings.TrimRight(l, "\r"))
+	}
+	return lines
+}
+
+// parseLine 解析 "NAME;PARAM=value:VALUE"，参数值可以用双引号包含 ';' 和 ':'
+func parseLine(l string) (property, error) {
+	p := property{params: make(map[string]string)}
+
+	var quoted bool
+	start, colon := 0, -1
+	var fields []string
+	for i := 0; i < len(l) && colon < 0; i++ {
+		switch c := l[i]; {
+		case c == '"':
+			quoted = !quoted
+		case (c == ';' || c == ':') && !quoted:
+			fields = append(fields, l[start:i])
+			start = i + 1
+			if c == ':' {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return p, fmt.Errorf("invalid content line %q", l)
+	}
+
+	p.name = strings.ToUpper(fields[0])
+	for _, f := range fields[1:] {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) == 2 {
+			p.params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	p.value = l[colon+1:]
+	return p, nil
+}
+
+// toDo 把组件转换为 ToDo，已完成或已取消的组件返回 nil
+func (c *component) toDo() (*v1.ToDo, error) {
+	if s := c.get("STATUS"); s != nil {
+		switch strings.ToUpper(s.value) {
+		case "COMPLETED", "CANCELLED":
+			return nil, nil
+		}
+	}
+
+	todo := &v1.ToDo{}
+	if p := c.get("SUMMARY"); p != nil {
+		todo.Title = unescape(p.value)
+	}
+	if p := c.get("DESCRIPTION"); p != nil {
+		todo.Description = unescape(p.value)
+	}
+
+	// VTODO 在 DUE 到期，VEVENT 和没有 DUE 的 VTODO 在 DTSTART 到期
+	base := c.get("DUE")
+	if base == nil {
+		base = c.get("DTSTART")
+	}
+	if base == nil {
+		return todo, nil
+	}
+	due, loc, err := parseTime(*base)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", base.name, err)
+	}
+	if loc != time.UTC {
+		todo.TimeZone = loc.String()
+	}
+
+	reminder := due
+	if t := c.trigger; t != nil {
+		if strings.ToUpper(t.params["VALUE"]) == "DATE-TIME" || strings.HasSuffix(t.value, "Z") {
+			if reminder, _, err = parseTime(*t); err != nil {
+				return nil, fmt.Errorf("invalid TRIGGER: %v", err)
+			}
+		} else {
+			d, err := parseDuration(t.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid TRIGGER: %v", err)
+			}
+			// VEVENT 的 TRIGGER 默认相对于 DTSTART，RELATED=END 时相对于 DTEND
+			if end := c.get("DTEND"); c.name == "VEVENT" && strings.ToUpper(t.params["RELATED"]) == "END" && end != nil {
+				if reminder, _, err = parseTime(*end); err != nil {
+					return nil, fmt.Errorf("invalid DTEND: %v", err)
+				}
+			}
+			reminder = reminder.Add(d)
+		}
+	}
+	if todo.Reminder, err = ptypes.TimestampProto(reminder); err != nil {
+		return nil, err
+	}
+
+	// 重复规则从提醒时间开始，排除的日期平移相同的时间
+	if rule := c.get("RRULE"); rule != nil {
+		offset := reminder.Sub(due)
+		lines := []string{"DTSTART" + formatTime(reminder, todo.TimeZone), "RRULE:" + rule.value}
+		for _, ex := range c.props["EXDATE"] {
+			for _, v := range strings.Split(ex.value, ",") {
+				t, _, err := parseTime(property{params: ex.params, value: v})
+				if err != nil {
+					return nil, fmt.Errorf("invalid EXDATE: %v", err)
+				}
+				lines = append(lines, "EXDATE"+formatTime(t.Add(offset), todo.TimeZone))
+			}
+		}
+		todo.Recurrence = strings.Join(lines, "\n")
+	}
+
+	return todo, nil
+}
+
+// parseTime 解析 DATE-TIME 或 DATE 类型的值，返回时间和所在的时区; 没有 TZID 的本地时间按 UTC 解析
+func parseTime(p property) (time.Time, *time.Location, error) {
+	loc := time.UTC
+	if tz, ok := p.params["TZID"]; ok {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("unknown time zone %q", tz)
+		}
+		loc = l
+	}
+
+	v := p.value
+	switch {
+	case strings.HasSuffix(v, "Z"):
+		t, err := time.ParseInLocation(dateTimeLayout, strings.TrimSuffix(v, "Z"), time.UTC)
+		return t, time.UTC, err
+	case len(v) == len(dateLayout):
+		t, err := time.ParseInLocation(dateLayout, v, loc)
+		return t, loc, err
+	default:
+		t, err := time.ParseInLocation(dateTimeLayout, v, loc)
+		return t, loc, err
+	}
+}
+
+// parseDuration 解析 RFC 5545 的 DURATION，例如 "-PT15M"、"P1DT2H"、"P1W"
+func parseDuration(s string) (time.Duration, error) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+	var d time.Duration
+	var n int64
+	var digits bool
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			n, digits = n*10+int64(c-'0'), true
+		case c == 'T':
+			units = map[byte]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+		default:
+			unit, ok := units[c]
+			if !ok || !digits {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			d += time.Duration(n) * unit
+			n, digits = 0, false
+		}
+	}
+	if digits {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return sign * d, nil
+}
@@ -0,0 +1,157 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+func TestEncode(t *testing.T) {
+	reminder, _ := ptypes.TimestampProto(time.Date(2019, 5, 6, 7, 0, 0, 0, time.UTC))
+	todos := []*v1.ToDo{
+		{Id: 1, Title: "买牛奶, 鸡蛋", Description: "two bottles;\nfresh", Reminder: reminder},
+		{Id: 2, Title: "chores", Reminder: reminder, TimeZone: "Europe/Berlin",
+			Recurrence: "DTSTART;TZID=Europe/Berlin:20190506T090000\nRRULE:FREQ=WEEKLY\nEXDATE;TZID=Europe/Berlin:20190513T090000"},
+		{Id: 3, Title: strings.Repeat("长", 40)},
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, todos, VTodo, "alice ToDo", time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Encode() error =%v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:todo-1@" + uidDomain + "\r\nDTSTAMP:20190501T000000Z\r\nSUMMARY:买牛奶\\, 鸡蛋\r\nDESCRIPTION:two bottles\\;\\nfresh\r\nDUE:20190506T070000Z\r\n",
+		"TRIGGER;RELATED=END:PT0S\r\n",
+		"DTSTART;TZID=Europe/Berlin:20190506T090000\r\nRRULE:FREQ=WEEKLY\r\nEXDATE;TZID=Europe/Berlin:20190513T090000\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Encode() does not contain %q:\n%s", want, got)
+		}
+	}
+	for _, l := range strings.Split(got, "\r\n") {
+		if len(l) > maxLineLength {
+			t.Errorf("line longer than %d octets: %q", maxLineLength, l)
+		}
+	}
+
+	// 编码后再解码得到相同的 ToDo
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode() error =%v", err)
+	}
+	if len(decoded) != len(todos) {
+		t.Fatalf("Decode() returned %d ToDo, want %d", len(decoded), len(todos))
+	}
+	for i, todo := range todos {
+		want := proto.Clone(todo).(*v1.ToDo)
+		want.Id = 0
+		if !proto.Equal(decoded[i], want) {
+			t.Errorf("Decode()[%d] =%v, want %v", i, decoded[i], want)
+		}
+	}
+}
+
+func TestEncodeLocalRecurrence(t *testing.T) {
+	reminder, _ := ptypes.TimestampProto(time.Date(2019, 5, 6, 7, 0, 0, 0, time.UTC))
+	todos := []*v1.ToDo{
+		{Id: 1, Title: "chores", Reminder: reminder, TimeZone: "Europe/Berlin",
+			Recurrence: "DTSTART:20190506T090000\nRRULE:FREQ=WEEKLY\nEXDATE:20190513T090000"},
+		{Id: 2, Title: "standup", Reminder: reminder, Recurrence: "DTSTART:20190506T070000\nRRULE:FREQ=DAILY"},
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, todos, VEvent, "alice ToDo", time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Encode() error =%v", err)
+	}
+
+	// 不带时区的规则加上 ToDo 的时区，没有时区时使用 UTC
+	for _, want := range []string{
+		"DTSTART;TZID=Europe/Berlin:20190506T090000\r\nRRULE:FREQ=WEEKLY\r\nEXDATE;TZID=Europe/Berlin:20190513T090000\r\n",
+		"DTSTART:20190506T070000Z\r\nRRULE:FREQ=DAILY\r\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Encode() does not contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"SUMMARY:standup with a very long summary that an exporter folded over more",
+		"  than one line",
+		`DTSTART;TZID="Europe/Berlin":20190506T093000`,
+		"DTEND;TZID=Europe/Berlin:20190506T094500",
+		"RRULE:FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+		"EXDATE;TZID=Europe/Berlin:20190508T093000,20190509T093000",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VTODO",
+		"SUMMARY:done already",
+		"STATUS:COMPLETED",
+		"DUE:20190506T070000Z",
+		"END:VTODO",
+		"BEGIN:VTODO",
+		"SUMMARY:taxes",
+		"DUE;VALUE=DATE:20190531",
+		"BEGIN:VALARM",
+		"TRIGGER;VALUE=DATE-TIME:20190530T080000Z",
+		"END:VALARM",
+		"END:VTODO",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	got, err := Decode(strings.NewReader(calendar))
+	if err != nil {
+		t.Fatalf("Decode() error =%v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Decode() returned %d ToDo, want 2: %v", len(got), got)
+	}
+
+	standup := got[0]
+	if standup.Title != "standup with a very long summary that an exporter folded over more than one line" {
+		t.Errorf("Title =%q", standup.Title)
+	}
+	if standup.TimeZone != "Europe/Berlin" || ptypes.TimestampString(standup.Reminder) != "2019-05-06T07:15:00Z" {
+		t.Errorf("TimeZone =%q, Reminder =%v", standup.TimeZone, ptypes.TimestampString(standup.Reminder))
+	}
+	wantRecurrence := "DTSTART;TZID=Europe/Berlin:20190506T091500\nRRULE:FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR\n" +
+		"EXDATE;TZID=Europe/Berlin:20190508T091500\nEXDATE;TZID=Europe/Berlin:20190509T091500"
+	if standup.Recurrence != wantRecurrence {
+		t.Errorf("Recurrence =%q, want %q", standup.Recurrence, wantRecurrence)
+	}
+
+	if taxes := got[1]; taxes.Title != "taxes" || ptypes.TimestampString(taxes.Reminder) != "2019-05-30T08:00:00Z" {
+		t.Errorf("Decode()[1] =%v", taxes)
+	}
+
+	for _, invalid := range []string{
+		"BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR",
+		"BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nDUE;TZID=Nowhere/City:20190101T000000\r\nEND:VTODO\r\nEND:VCALENDAR",
+		"BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nDUE:20190101T000000Z\r\nBEGIN:VALARM\r\nTRIGGER:-P1X\r\nEND:VALARM\r\nEND:VTODO\r\nEND:VCALENDAR",
+		"not a calendar",
+	} {
+		if _, err := Decode(strings.NewReader(invalid)); err == nil {
+			t.Errorf("Decode(%q) expected error", invalid)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Package notify 把到期的 ToDo 提醒发送给用户
+package notify
+
+import (
+	"context"
+	"log"
+
+	"github.com/golang/protobuf/ptypes"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+// Notifier 发送一个到期的提醒，返回错误时提醒被标记为发送失败
+type Notifier interface {
+	Notify(ctx context.Context, todo *v1.ToDo) error
+}
+
+// Multi 把提醒发送给所有 Notifier，返回第一个错误
+type Multi []Notifier
+
+// Notify 实现 Notifier
+func (m Multi) Notify(ctx context.Context, todo *v1.ToDo) error {
+	var first error
+	for _, n := range m {
+		if err := n.Notify(ctx, todo); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Log 把提醒写入日志，没有配置其它 Notifier 时使用
+type Log struct{}
+
+// Notify 实现 Notifier
+func (Log) Notify(ctx context.Context, todo *v1.ToDo) error {
+	log.Printf("reminder: ToDo ID='%d' '%s' is due at %s", todo.Id, todo.Title, ptypes.TimestampString(todo.Reminder))
+	return nil
+}
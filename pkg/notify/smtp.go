@@ -0,0 +1,245 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"text/template"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// DefaultSubjectTemplate 是提醒邮件默认的标题模板
+	DefaultSubjectTemplate = "Reminder: {{.Title}}"
+	// DefaultBodyTemplate 是提醒邮件默认的正文模板
+	DefaultBodyTemplate = "{{.Title}}\n\n{{if .Description}}{{.Description}}\n\n{{end}}Due at {{.Reminder.Format \"2006-01-02 15:04 MST\"}}\n"
+	// DefaultTimeout 是发送一封邮件默认的超时时间
+	DefaultTimeout = 30 * time.Second
+)
+
+// Message 是渲染邮件模板使用的数据
+type Message struct {
+	ID          int64
+	Title       string
+	Description string
+	// Reminder 是 ToDo 时区的本地时间
+	Reminder time.Time
+}
+
+// SMTP 通过 SMTP 服务器把提醒发送到 ToDo 所属用户的邮箱
+type SMTP struct {
+	// Addr 是 SMTP 服务器的 host:port
+	Addr string
+	// From 是发件人地址
+	From string
+	// Username 和 Password 不为空时使用 PLAIN 认证
+	Username string
+	Password string
+	// StartTLS 为 true 时要求服务器支持 STARTTLS 并在认证前升级连接
+	StartTLS bool
+	// TLSConfig 是 STARTTLS 使用的配置，为 nil 时使用 Addr 中的主机名校验证书
+	TLSConfig *tls.Config
+	// Timeout 是发送一封邮件 (包括连接) 的超时时间，为 0 时使用 DefaultTimeout
+	Timeout time.Duration
+
+	// Subject 和 Body 是邮件标题和正文的模板，数据是 Message
+	Subject *template.Template
+	Body    *template.Template
+
+	// Recipient 返回提醒的收件人，返回空字符串时不发送
+	Recipient func(ctx context.Context, todo *v1.ToDo) (string, error)
+}
+
+// ParseTemplates 解析邮件标题和正文的模板，空字符串使用默认模板
+func ParseTemplates(subject, body string) (*template.Template, *template.Template, error) {
+	if len(subject) == 0 {
+		subject = DefaultSubjectTemplate
+	}
+	if len(body) == 0 {
+		body = DefaultBodyTemplate
+	}
+
+	s, err := template.New("subject").Parse(subject)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid subject template: %v", err)
+	}
+	b, err := template.New("body").Parse(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid body template: %v", err)
+	}
+	return s, b, nil
+}
+
+// Notify 实现 Notifier
+func (s *SMTP) Notify(ctx context.Context, todo *v1.ToDo) error {
+	to, err := s.Recipient(ctx, todo)
+	if err != nil {
+		return fmt.Errorf("failed to look up recipient: %v", err)
+	}
+	if len(to) == 0 {
+		return nil
+	}
+
+	msg, err := s.render(to, todo)
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, to, msg)
+}
+
+// render 渲染模板并生成完整的邮件
+func (s *SMTP) render(to string, todo *v1.ToDo) ([]byte, error) {
+	data := Message{ID: todo.Id, Title: todo.Title, Description: todo.Description}
+	if todo.Reminder != nil {
+		reminder, err := ptypes.Timestamp(todo.Reminder)
+		if err != nil {
+			return nil, err
+		}
+		// 未知的时区按 UTC 显示
+		if loc, err := time.LoadLocation(todo.TimeZone); err == nil {
+			reminder = reminder.In(loc)
+		}
+		data.Reminder = reminder
+	}
+
+	var subject, body bytes.Buffer
+	if err := s.Subject.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("failed to render subject: %v", err)
+	}
+	if err := s.Body.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("failed to render body: %v", err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject.String()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&msg)
+	if _, err := w.Write(body.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+// send 连接 SMTP 服务器发送一封邮件，整个会话不超过 Timeout，服务器不响应时不会阻塞发送提醒的循环
+func (s *SMTP) send(ctx context.Context, to string, msg []byte) error {
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return err
+	}
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if s.StartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server %s does not support STARTTLS", s.Addr)
+		}
+		config := s.TLSConfig
+		if config == nil {
+			config = &tls.Config{ServerName: host}
+		}
+		if err := c.StartTLS(config); err != nil {
+			return err
+		}
+	}
+
+	if len(s.Username) > 0 {
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// AddressBook 保存每个用户的邮件地址
+type AddressBook struct {
+	addresses map[string]string
+	fallback  string
+}
+
+// LoadAddressBook 从 JSON 文件读取用户到邮件地址的映射，例如 {"alice": "alice@example.com"}
+// path 为空时只使用 fallback; 没有配置地址的用户使用 fallback，fallback 为空时不发送
+func LoadAddressBook(path, fallback string) (*AddressBook, error) {
+	book := &AddressBook{addresses: make(map[string]string), fallback: fallback}
+	if len(path) == 0 {
+		return book, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &book.addresses); err != nil {
+		return nil, fmt.Errorf("invalid address book %s: %v", path, err)
+	}
+	return book, nil
+}
+
+// Address 返回用户的邮件地址
+func (b *AddressBook) Address(user string) string {
+	if a, ok := b.addresses[user]; ok {
+		return a
+	}
+	return b.fallback
+}
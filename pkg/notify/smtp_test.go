@@ -0,0 +1,215 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+// fakeSMTP 是只实现发送邮件所需命令的本地 SMTP 服务器，收到的命令和邮件写入 commands 和 messages
+type fakeSMTP struct {
+	listener   net.Listener
+	extensions []string
+	commands   chan string
+	messages   chan string
+}
+
+func newFakeSMTP(t *testing.T, extensions ...string) *fakeSMTP {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	s := &fakeSMTP{listener: l, extensions: extensions, commands: make(chan string, 100), messages: make(chan string, 10)}
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTP) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTP) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		s.commands <- line
+
+		switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+		case "EHLO":
+			for _, ext := range s.extensions {
+				reply("250-" + ext)
+			}
+			reply("250 localhost")
+		case "AUTH":
+			reply("235 2.7.0 Authentication successful")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var msg strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				msg.WriteString(l)
+			}
+			s.messages <- msg.String()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPNotify(t *testing.T) {
+	server := newFakeSMTP(t, "AUTH PLAIN")
+	defer server.listener.Close()
+
+	subject, body, err := ParseTemplates("", "")
+	if err != nil {
+		t.Fatalf("ParseTemplates() error =%v", err)
+	}
+
+	reminder, _ := ptypes.TimestampProto(time.Date(2019, 5, 1, 8, 30, 0, 0, time.UTC))
+	notifier := &SMTP{
+		Addr:     server.listener.Addr().String(),
+		From:     "todo@example.com",
+		Username: "user",
+		Password: "password",
+		Subject:  subject,
+		Body:     body,
+		Recipient: func(ctx context.Context, todo *v1.ToDo) (string, error) {
+			return "alice@example.com", nil
+		},
+	}
+
+	err = notifier.Notify(context.Background(), &v1.ToDo{Id: 1, Title: "买牛奶", Description: "two bottles", Reminder: reminder, TimeZone: "Europe/Berlin"})
+	if err != nil {
+		t.Fatalf("SMTP.Notify() error =%v", err)
+	}
+
+	msg := <-server.messages
+	for _, want := range []string{
+		"To: alice@example.com\r\n",
+		"Subject: =?utf-8?q?Reminder:_=E4=B9=B0=E7=89=9B=E5=A5=B6?=\r\n",
+		"two bottles",
+		"Due at 2019-05-01 10:30 CEST",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message does not contain %q:\n%s", want, msg)
+		}
+	}
+
+	var commands []string
+	for len(server.commands) > 0 {
+		commands = append(commands, <-server.commands)
+	}
+	joined := strings.Join(commands, "\n")
+	for _, want := range []string{"AUTH PLAIN", "MAIL FROM:<todo@example.com>", "RCPT TO:<alice@example.com>"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("commands do not contain %q:\n%s", want, joined)
+		}
+	}
+}
+
+func TestSMTPRequiresStartTLS(t *testing.T) {
+	server := newFakeSMTP(t)
+	defer server.listener.Close()
+
+	subject, body, _ := ParseTemplates("", "")
+	notifier := &SMTP{
+		Addr:     server.listener.Addr().String(),
+		From:     "todo@example.com",
+		StartTLS: true,
+		Subject:  subject,
+		Body:     body,
+		Recipient: func(ctx context.Context, todo *v1.ToDo) (string, error) {
+			return "alice@example.com", nil
+		},
+	}
+
+	err := notifier.Notify(context.Background(), &v1.ToDo{Id: 1, Title: "title"})
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Fatalf("SMTP.Notify() error =%v, want STARTTLS not supported", err)
+	}
+}
+
+func TestSMTPTimeout(t *testing.T) {
+	// 接受连接但从不响应的服务器
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	subject, body, _ := ParseTemplates("", "")
+	notifier := &SMTP{
+		Addr:    l.Addr().String(),
+		From:    "todo@example.com",
+		Timeout: 100 * time.Millisecond,
+		Subject: subject,
+		Body:    body,
+		Recipient: func(ctx context.Context, todo *v1.ToDo) (string, error) {
+			return "alice@example.com", nil
+		},
+	}
+
+	start := time.Now()
+	err = notifier.Notify(context.Background(), &v1.ToDo{Id: 1, Title: "title"})
+	if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+		t.Errorf("SMTP.Notify() error =%v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("SMTP.Notify() took %v, want about %v", elapsed, notifier.Timeout)
+	}
+}
+
+func TestAddressBook(t *testing.T) {
+	book := &AddressBook{addresses: map[string]string{"alice": "alice@example.com"}, fallback: "team@example.com"}
+
+	if got := book.Address("alice"); got != "alice@example.com" {
+		t.Errorf("AddressBook.Address(alice) =%s", got)
+	}
+	if got := book.Address("bob"); got != "team@example.com" {
+		t.Errorf("AddressBook.Address(bob) =%s, want fallback", got)
+	}
+}
@@ -3,20 +3,40 @@ package grpc
 import (
 	"context"
 	v1 "go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	v2 "go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v2"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 	"log"
 	"net"
+	"time"
 )
 
-func RunServer(ctx context.Context, v1API v1.ToDoServiceServer, port string) error {
+// minKeepalive 是服务器允许客户端发送 keepalive ping 的最小间隔，和 gRPC 客户端允许设置的最小间隔相同
+// 客户端在没有调用时也可以发送 ping; 更频繁的 ping 会被服务器以 GOAWAY (too_many_pings) 关闭连接
+const minKeepalive = 10 * time.Second
+
+// RunServer 启动 gRPC 服务器，opts 是额外的服务器选项，例如认证拦截器
+func RunServer(ctx context.Context, v1API v1.ToDoServiceServer, v2API v2.ToDoServiceServer, webhookAPI v1.WebhookServiceServer, port string, opts ...grpc.ServerOption) error {
 	listen, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		return err
 	}
 
-	server := grpc.NewServer()
-	v1.RegisterToDoServiceServer(server, v1API)
+	server := NewServer(v1API, v2API, webhookAPI, opts...)
 
 	log.Println("starting gRPC server...")
 	return server.Serve(listen)
-}
\ No newline at end of file
+}
+
+// NewServer 创建注册了所有服务的 gRPC 服务器，允许客户端按 minKeepalive 的间隔发送 keepalive ping
+func NewServer(v1API v1.ToDoServiceServer, v2API v2.ToDoServiceServer, webhookAPI v1.WebhookServiceServer, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+		MinTime:             minKeepalive,
+		PermitWithoutStream: true,
+	})}, opts...)
+	server := grpc.NewServer(opts...)
+	v1.RegisterToDoServiceServer(server, v1API)
+	v2.RegisterToDoServiceServer(server, v2API)
+	v1.RegisterWebhookServiceServer(server, webhookAPI)
+	return server
+}
@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+// calendarPath 输出用户的 iCalendar 订阅
+// user 和 token 参数从 GetCalendarToken 获得，component 参数为 VTODO (默认) 或 VEVENT
+const calendarPath = "/v1/todo/calendar.ics"
+
+// calendarHandler 把 gRPC ExportICS 的结果以 text/calendar 输出，日历应用可以直接订阅
+type calendarHandler struct {
+	client v1.ToDoServiceClient
+}
+
+func (h *calendarHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	ctx := metadata.AppendToOutgoingContext(r.Context(), requestIDKey, r.Header.Get(requestIDHeader))
+	resp, err := h.client.ExportICS(ctx, &v1.ExportICSRequest{
+		Api:       "v1",
+		User:      q.Get("user"),
+		Token:     q.Get("token"),
+		Component: q.Get("component"),
+	})
+	if err != nil {
+		errorHandler(ctx, nil, nil, w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="calendar.ics"`)
+	io.WriteString(w, resp.Calendar)
+}
@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// legacyReadPath 是 Read 已废弃的旧路径 /v1/dodo/{id}，新路径是 /v1/todo/{id}
+const legacyReadPath = "/v1/dodo/"
+
+// readDeprecationTime 是 /v1/dodo/{id} 被废弃的时间
+var readDeprecationTime = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+// deprecatedRequests 按路由统计仍在使用已废弃路径的请求数，通过 expvar 导出并由网关的 /metrics 输出，
+// 旧路径的请求数降为 0 之后就可以删除它
+var deprecatedRequests = expvar.NewMap("rest_deprecated_requests")
+
+// deprecated 给旧路径的响应加上 Deprecation 头 (RFC 9745) 和指向新路径的 Link 头，并记录请求数
+func deprecated(route string, when time.Time, successor func(r *http.Request) string, h http.Handler) http.Handler {
+	deprecation := fmt.Sprintf("@%d", when.Unix())
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deprecatedRequests.Add(route, 1)
+		w.Header().Set("Deprecation", deprecation)
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor(r)))
+		h.ServeHTTP(w, r)
+	})
+}
+
+// readSuccessor 把 /v1/dodo/{id} 转换为 /v1/todo/{id}
+func readSuccessor(r *http.Request) string {
+	return "/v1/todo/" + strings.TrimPrefix(r.URL.Path, legacyReadPath)
+}
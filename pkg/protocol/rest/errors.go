@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	_ "google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// requestIDHeader 是客户端传入/服务器返回请求 ID 的 HTTP 头
+	requestIDHeader = "X-Request-Id"
+	// requestIDKey 是转发给 gRPC 服务的 metadata key
+	requestIDKey = "x-request-id"
+)
+
+// errorBody 是返回给 REST 客户端的统一错误格式
+type errorBody struct {
+	Code      int32             `json:"code"`
+	Status    string            `json:"status"`
+	Message   string            `json:"message"`
+	RequestID string            `json:"requestId,omitempty"`
+	Details   []json.RawMessage `json:"details,omitempty"`
+}
+
+type errorEnvelope struct {
+	Error errorBody `json:"error"`
+}
+
+// withRequestID 确保每个请求都带有请求 ID: 客户端没有提供时生成一个，并在响应头中返回
+func withRequestID(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if len(id) == 0 {
+			id = newRequestID()
+			r.Header.Set(requestIDHeader, id)
+		}
+		w.Header().Set(requestIDHeader, id)
+		h.ServeHTTP(w, r)
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "-"
+	}
+	return hex.EncodeToString(b)
+}
+
+// requestIDMetadata 把请求 ID 转发给 gRPC 服务，便于在服务器日志中关联
+func requestIDMetadata(_ context.Context, r *http.Request) metadata.MD {
+	return metadata.Pairs(requestIDKey, r.Header.Get(requestIDHeader))
+}
+
+// errorHandler 把 gRPC 错误转换为统一的 JSON 错误格式
+func errorHandler(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	s, ok := status.FromError(err)
+	if !ok {
+		s = status.New(codes.Unknown, err.Error())
+	}
+
+	body := errorBody{
+		Code:      int32(s.Code()),
+		Status:    code.Code_name[int32(s.Code())],
+		Message:   s.Message(),
+		RequestID: r.Header.Get(requestIDHeader),
+	}
+
+	m := jsonpb.Marshaler{}
+	for _, d := range s.Proto().Details {
+		detail, err := m.MarshalToString(d)
+		if err != nil {
+			log.Printf("failed to marshal error detail %q: %v", d.TypeUrl, err)
+			continue
+		}
+		body.Details = append(body.Details, json.RawMessage(detail))
+	}
+
+	w.Header().Del("Trailer")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(runtime.HTTPStatusFromCode(s.Code()))
+	if err := json.NewEncoder(w).Encode(errorEnvelope{Error: body}); err != nil {
+		log.Printf("failed to write error response: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHandler(t *testing.T) {
+	st, _ := status.New(codes.InvalidArgument, "invalid request: toDo.title must not be empty").
+		WithDetails(&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "toDo.title", Description: "must not be empty"}}})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail bool
+	}{
+		{"invalid argument with details", st.Err(), http.StatusBadRequest, "INVALID_ARGUMENT", true},
+		{"not found", status.Error(codes.NotFound, "ToDo with ID='1' is not found"), http.StatusNotFound, "NOT_FOUND", false},
+		{"aborted", status.Error(codes.Aborted, "deadlock"), http.StatusConflict, "ABORTED", false},
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), http.StatusServiceUnavailable, "UNAVAILABLE", false},
+		{"not a status error", errors.New("boom"), http.StatusInternalServerError, "UNKNOWN", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/todo/1", nil)
+			r.Header.Set(requestIDHeader, "req-1")
+			w := httptest.NewRecorder()
+			w.Header().Set("Trailer", "Grpc-Trailer-Foo")
+
+			errorHandler(context.Background(), nil, &runtime.JSONPb{}, w, r, tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if w.Header().Get("Content-Type") != "application/json" || len(w.Header().Get("Trailer")) > 0 {
+				t.Errorf("headers = %v, want JSON without Trailer", w.Header())
+			}
+
+			var got struct {
+				Error struct {
+					Code      int32             `json:"code"`
+					Status    string            `json:"status"`
+					Message   string            `json:"message"`
+					RequestID string            `json:"requestId"`
+					Details   []json.RawMessage `json:"details"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("invalid body %s: %v", w.Body.String(), err)
+			}
+			if got.Error.Status != tt.wantCode || got.Error.Code != int32(status.Code(tt.err)) || got.Error.RequestID != "req-1" {
+				t.Errorf("error = %+v, want status %s with request ID req-1", got.Error, tt.wantCode)
+			}
+			if got.Error.Message != status.Convert(tt.err).Message() {
+				t.Errorf("message = %q, want %q", got.Error.Message, status.Convert(tt.err).Message())
+			}
+
+			if !tt.wantDetail {
+				if len(got.Error.Details) > 0 {
+					t.Errorf("details = %s, want none", got.Error.Details)
+				}
+				return
+			}
+			var detail struct {
+				Type            string `json:"@type"`
+				FieldViolations []struct {
+					Field string `json:"field"`
+				} `json:"fieldViolations"`
+			}
+			if len(got.Error.Details) != 1 || json.Unmarshal(got.Error.Details[0], &detail) != nil ||
+				detail.Type != "type.googleapis.com/google.rpc.BadRequest" || len(detail.FieldViolations) != 1 || detail.FieldViolations[0].Field != "toDo.title" {
+				t.Errorf("details = %s, want a BadRequest for toDo.title", got.Error.Details)
+			}
+		})
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	var seen string
+	h := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.Header.Get(requestIDHeader)
+	}))
+
+	// 客户端提供的请求 ID 原样转发并返回
+	r := httptest.NewRequest(http.MethodGet, "/v1/todo/1", nil)
+	r.Header.Set(requestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if seen != "req-1" || w.Header().Get(requestIDHeader) != "req-1" {
+		t.Errorf("request ID = %q, response header = %q, want req-1", seen, w.Header().Get(requestIDHeader))
+	}
+	if md := requestIDMetadata(context.Background(), r); len(md.Get(requestIDKey)) != 1 || md.Get(requestIDKey)[0] != "req-1" {
+		t.Errorf("requestIDMetadata() = %v, want %s=req-1", md, requestIDKey)
+	}
+
+	// 没有提供时生成一个新的，每个请求都不同
+	ids := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/todo/1", nil))
+		id := w.Header().Get(requestIDHeader)
+		if len(id) != 32 || id != seen {
+			t.Errorf("generated request ID = %q, handler saw %q, want the same 32 hex characters", id, seen)
+		}
+		ids[id] = true
+	}
+	if len(ids) != 2 {
+		t.Errorf("generated request IDs = %v, want distinct IDs", ids)
+	}
+}
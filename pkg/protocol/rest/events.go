@@ -0,0 +1,214 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// eventsPath 以 Server-Sent Events 输出 ToDo 变更事件
+	eventsPath = "/v1/todo/events"
+	// eventsWebSocketPath 以 WebSocket 输出 ToDo 变更事件
+	eventsWebSocketPath = "/v1/todo/events/ws"
+
+	// heartbeatInterval 是 SSE 心跳间隔，防止代理关闭空闲连接
+	heartbeatInterval = 15 * time.Second
+)
+
+// eventsHandler 把 gRPC Watch 流转换为浏览器可以直接使用的 SSE 和 WebSocket
+type eventsHandler struct {
+	client v1.ToDoServiceClient
+	// allowedOrigins 是除同源之外允许建立 WebSocket 连接的 Origin，例如 https://app.example.com，"*" 允许任何 Origin
+	allowedOrigins []string
+}
+
+// watch 调用 gRPC Watch，since 是客户端最后收到的事件序号
+func (h *eventsHandler) watch(ctx context.Context, r *http.Request, since int64) (v1.ToDoService_WatchClient, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, requestIDKey, r.Header.Get(requestIDHeader))
+	return h.client.Watch(ctx, &v1.WatchRequest{Api: "v1", SinceSequence: since})
+}
+
+// lastSequence 读取续传序号: SSE 使用 Last-Event-ID 头，WebSocket 和不支持自定义头的客户端使用 since 参数
+func lastSequence(r *http.Request) (int64, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if len(v) == 0 {
+		v = r.URL.Query().Get("since")
+	}
+	if len(v) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// ServeHTTP 输出 Server-Sent Events
+func (h *eventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	f, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	since, err := lastSequence(r)
+	if err != nil {
+		http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream, err := h.watch(ctx, r, since)
+	if err != nil {
+		errorHandler(ctx, nil, nil, w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	events := make(chan *v1.ToDoEvent)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case events <- resp.Event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	m := jsonpb.Marshaler{OrigName: true}
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case ev := <-events:
+			data, err := m.MarshalToString(ev)
+			if err != nil {
+				log.Printf("failed to marshal event %d: %v", ev.Sequence, err)
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Sequence, strings.ToLower(ev.Type.String()), data)
+		case err := <-errs:
+			// 流结束时告诉客户端原因，例如续传序号已过期时客户端需要重新加载
+			s := status.Convert(err)
+			fmt.Fprintf(w, "event: error\ndata: {\"code\":%d,\"message\":%q}\n\n", s.Code(), s.Message())
+			f.Flush()
+			return
+		}
+		f.Flush()
+	}
+}
+
+// serveWebSocket 通过 WebSocket 输出事件，每条消息是一个 JSON 格式的 ToDoEvent
+func (h *eventsHandler) serveWebSocket(ws *websocket.Conn) {
+	defer ws.Close()
+
+	r := ws.Request()
+	since, err := lastSequence(r)
+	if err != nil {
+		_ = websocket.JSON.Send(ws, map[string]interface{}{"code": 3, "message": "invalid since parameter"})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// 客户端关闭连接时结束 Watch
+	go func() {
+		var discard []byte
+		for websocket.Message.Receive(ws, &discard) == nil {
+		}
+		cancel()
+	}()
+
+	stream, err := h.watch(ctx, r, since)
+	if err != nil {
+		s := status.Convert(err)
+		_ = websocket.JSON.Send(ws, map[string]interface{}{"code": s.Code(), "message": s.Message()})
+		return
+	}
+
+	m := jsonpb.Marshaler{OrigName: true}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if ctx.Err() == nil {
+				s := status.Convert(err)
+				_ = websocket.JSON.Send(ws, map[string]interface{}{"code": s.Code(), "message": s.Message()})
+			}
+			return
+		}
+
+		data, err := m.MarshalToString(resp.Event)
+		if err != nil {
+			log.Printf("failed to marshal event %d: %v", resp.Event.Sequence, err)
+			return
+		}
+		if err := websocket.Message.Send(ws, data); err != nil {
+			return
+		}
+	}
+}
+
+// webSocketHandler 返回 WebSocket 端点的 handler
+func (h *eventsHandler) webSocketHandler() http.Handler {
+	return websocket.Server{Handler: h.serveWebSocket, Handshake: h.checkOrigin}
+}
+
+// checkOrigin 拒绝来自其他网站的 WebSocket 连接，防止恶意网页借用浏览器中的凭证订阅事件
+// 浏览器总是发送 Origin 头，没有 Origin 的是非浏览器客户端，不受此限制
+func (h *eventsHandler) checkOrigin(config *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if len(origin) == 0 {
+		return nil
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || len(u.Host) == 0 {
+		return fmt.Errorf("invalid origin %q", origin)
+	}
+	config.Origin = u
+
+	if strings.EqualFold(u.Host, r.Host) {
+		return nil
+	}
+	for _, allowed := range h.allowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), u.Scheme+"://"+u.Host) {
+			return nil
+		}
+	}
+	return fmt.Errorf("origin %q is not allowed", origin)
+}
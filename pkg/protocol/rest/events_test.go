@@ -0,0 +1,226 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+// watchClient 是只实现 Watch 的 ToDoServiceClient，依次返回 events 之后以 err 结束流
+type watchClient struct {
+	v1.ToDoServiceClient
+	events []*v1.ToDoEvent
+	err    error
+	since  chan int64
+}
+
+func newWatchClient(err error, events ...*v1.ToDoEvent) *watchClient {
+	return &watchClient{events: events, err: err, since: make(chan int64, 1)}
+}
+
+func (c *watchClient) Watch(ctx context.Context, in *v1.WatchRequest, _ ...grpc.CallOption) (v1.ToDoService_WatchClient, error) {
+	c.since <- in.SinceSequence
+	if in.SinceSequence < 0 {
+		return nil, status.Error(codes.OutOfRange, "since sequence has expired")
+	}
+	return &watchStream{ctx: ctx, events: c.events, err: c.err}, nil
+}
+
+type watchStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	events []*v1.ToDoEvent
+	err    error
+}
+
+func (s *watchStream) Recv() (*v1.WatchResponse, error) {
+	if len(s.events) == 0 {
+		return nil, s.err
+	}
+	ev := s.events[0]
+	s.events = s.events[1:]
+	return &v1.WatchResponse{Api: "v1", Event: ev}, nil
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+var testEvents = []*v1.ToDoEvent{
+	{Sequence: 6, Type: v1.ToDoEvent_CREATED, ToDo: &v1.ToDo{Id: 1, Title: "a"}},
+	{Sequence: 7, Type: v1.ToDoEvent_UPDATED, ToDo: &v1.ToDo{Id: 1, Title: "b"}},
+}
+
+func TestEventsSSE(t *testing.T) {
+	client := newWatchClient(status.Error(codes.Unavailable, "server is shutting down"), testEvents...)
+	srv := httptest.NewServer(withRequestID(&eventsHandler{client: client}))
+	defer srv.Close()
+
+	// Last-Event-ID 优先于 since 参数
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+eventsPath+"?since=1", nil)
+	req.Header.Set("Last-Event-ID", "5")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", eventsPath, err)
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	body := string(b)
+
+	if since := <-client.since; since != 5 {
+		t.Errorf("Watch() since = %d, want 5", since)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("GET %s = %d %s, want an event stream", eventsPath, resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	for _, want := range []string{
+		"id: 6\nevent: created\ndata: {\"sequence\":\"6\",\"type\":\"CREATED\"",
+		"id: 7\nevent: updated\n",
+		"event: error\ndata: {\"code\":14,\"message\":\"server is shutting down\"}",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body = %q, want %q", body, want)
+		}
+	}
+
+	// 没有 Last-Event-ID 时使用 since 参数
+	resp, err = http.Get(srv.URL + eventsPath + "?since=6")
+	if err != nil {
+		t.Fatalf("GET %s error = %v", eventsPath, err)
+	}
+	resp.Body.Close()
+	if since := <-client.since; since != 6 {
+		t.Errorf("Watch() since = %d, want 6", since)
+	}
+}
+
+func TestEventsSSEErrors(t *testing.T) {
+	client := newWatchClient(io.EOF)
+	srv := httptest.NewServer(withRequestID(&eventsHandler{client: client}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		want   int
+	}{
+		{"invalid since", http.MethodGet, "?since=abc", http.StatusBadRequest},
+		{"expired since", http.MethodGet, "?since=-1", http.StatusBadRequest},
+		{"method not allowed", http.MethodPost, "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+eventsPath+tt.query, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("%s %s error = %v", tt.method, eventsPath, err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("%s %s%s = %d, want %d", tt.method, eventsPath, tt.query, resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventsWebSocket(t *testing.T) {
+	client := newWatchClient(status.Error(codes.Unavailable, "server is shutting down"), testEvents...)
+	events := &eventsHandler{client: client, allowedOrigins: []string{"https://app.example.com"}}
+	srv := httptest.NewServer(events.webSocketHandler())
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + eventsWebSocketPath
+
+	// 同源的连接从 since 之后续传
+	ws, err := websocket.Dial(wsURL+"?since=5", "", srv.URL)
+	if err != nil {
+		t.Fatalf("websocket.Dial() error = %v", err)
+	}
+	var got []string
+	for {
+		var msg string
+		if err := websocket.Message.Receive(ws, &msg); err != nil {
+			break
+		}
+		got = append(got, msg)
+	}
+	ws.Close()
+
+	if since := <-client.since; since != 5 {
+		t.Errorf("Watch() since = %d, want 5", since)
+	}
+	if len(got) != 3 || !strings.Contains(got[0], `"sequence":"6"`) || !strings.Contains(got[1], `"sequence":"7"`) || !strings.Contains(got[2], `"code":14`) {
+		t.Errorf("messages = %v, want events 6 and 7 followed by the Unavailable error", got)
+	}
+
+	// 其他网站的页面不能连接
+	if _, err := websocket.Dial(wsURL, "", "https://evil.example.com"); err == nil {
+		t.Error("websocket.Dial() from another origin error = nil, want handshake error")
+	}
+
+	// 不合法的 since 参数
+	ws, err = websocket.Dial(wsURL+"?since=abc", "", "https://app.example.com")
+	if err != nil {
+		t.Fatalf("websocket.Dial() error = %v", err)
+	}
+	var msg struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := websocket.JSON.Receive(ws, &msg); err != nil || msg.Code != int(codes.InvalidArgument) {
+		t.Errorf("message = %+v, error = %v, want InvalidArgument", msg, err)
+	}
+	ws.Close()
+
+	// 过期的 since 参数
+	ws, err = websocket.Dial(wsURL+"?since=-1", "", srv.URL)
+	if err != nil {
+		t.Fatalf("websocket.Dial() error = %v", err)
+	}
+	if err := websocket.JSON.Receive(ws, &msg); err != nil || msg.Code != int(codes.OutOfRange) {
+		t.Errorf("message = %+v, error = %v, want OutOfRange", msg, err)
+	}
+	ws.Close()
+}
+
+func TestEventsWebSocketOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		wantOK  bool
+	}{
+		{"no origin", nil, "", true},
+		{"same origin", nil, "http://gateway.example.com", true},
+		{"other origin", nil, "https://evil.example.com", false},
+		{"allowed origin", []string{"https://app.example.com/"}, "https://app.example.com", true},
+		{"allowed origin other scheme", []string{"https://app.example.com"}, "http://app.example.com", false},
+		{"any origin", []string{"*"}, "https://evil.example.com", true},
+		{"invalid origin", []string{"*"}, "null", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &eventsHandler{allowedOrigins: tt.allowed}
+			r := httptest.NewRequest(http.MethodGet, "http://gateway.example.com"+eventsWebSocketPath, nil)
+			if len(tt.origin) > 0 {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if err := h.checkOrigin(&websocket.Config{}, r); (err == nil) != tt.wantOK {
+				t.Errorf("checkOrigin(%q) error = %v, want ok = %v", tt.origin, err, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+//go:build ignore
+// +build ignore
+
+// gen_assets 把 OpenAPI 文档和 Swagger UI 文件生成到 assets.go 中，编译进网关
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	swaggerDir   = "../../../api/swagger"
+	swaggerUIDir = "../../../third_party/swagger-ui"
+)
+
+// swaggerUIFiles 是 Swagger UI 页面用到的文件
+var swaggerUIFiles = []string{
+	"favicon-32x32.png",
+	"swagger-ui-bundle.js",
+	"swagger-ui-standalone-preset.js",
+	"swagger-ui.css",
+}
+
+func main() {
+	specs := make(map[string]string)
+	for _, version := range []string{"v1", "v2"} {
+		specs[version] = filepath.Join(swaggerDir, version, "todo_service.swagger.json")
+	}
+	files := make(map[string]string)
+	for _, name := range swaggerUIFiles {
+		files[name] = filepath.Join(swaggerUIDir, name)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by gen_assets.go. DO NOT EDIT.\n\npackage rest\n\n")
+	b.WriteString("// openAPISpecs 是 api/swagger 中的 OpenAPI 文档，key 是 API 版本\n")
+	writeMap(&b, "openAPISpecs", specs)
+	b.WriteString("\n// swaggerUIFiles 是 third_party/swagger-ui 中的 Swagger UI 文件，key 是文件名\n")
+	writeMap(&b, "swaggerUIFiles", files)
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatalf("failed to format source: %v", err)
+	}
+	if err := ioutil.WriteFile("assets.go", src, 0644); err != nil {
+		log.Fatalf("failed to write assets.go: %v", err)
+	}
+}
+
+// writeMap 把 files 中每个文件的内容写为 map[string]string 变量
+func writeMap(b *bytes.Buffer, name string, files map[string]string) {
+	var keys []string
+	for k := range files {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(b, "var %s = map[string]string{\n", name)
+	for _, k := range keys {
+		data, err := ioutil.ReadFile(files[k])
+		if err != nil {
+			log.Fatalf("failed to read %s: %v", files[k], err)
+		}
+		fmt.Fprintf(b, "%q: %q,\n", k, data)
+	}
+	b.WriteString("}\n")
+}
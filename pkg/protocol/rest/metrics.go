@@ -0,0 +1,35 @@
+package rest
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+)
+
+// metricsPath 以 JSON 输出 metricVars 中的 expvar 变量，格式与 /debug/vars 相同
+const metricsPath = "/metrics"
+
+// metricVars 是 metricsPath 输出的 expvar 变量
+// 不使用 expvar.Handler: 它还输出进程的命令行，其中包括 -db-password 等参数
+var metricVars = []string{"rest_deprecated_requests"}
+
+func metrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	fmt.Fprint(w, "{\n")
+	for i, name := range metricVars {
+		if i > 0 {
+			fmt.Fprint(w, ",\n")
+		}
+		var value interface{} = "null"
+		if v := expvar.Get(name); v != nil {
+			value = v
+		}
+		fmt.Fprintf(w, "%q: %s", name, value)
+	}
+	fmt.Fprint(w, "\n}\n")
+}
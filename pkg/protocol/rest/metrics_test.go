@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	h, stop := newTestHandler(t, "")
+	defer stop()
+
+	read := func() map[string]map[string]int64 {
+		w := get(h, metricsPath)
+		if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+			t.Fatalf("GET %s = %d %s, want JSON", metricsPath, w.Code, w.Header().Get("Content-Type"))
+		}
+		var vars map[string]map[string]int64
+		if err := json.Unmarshal(w.Body.Bytes(), &vars); err != nil {
+			t.Fatalf("GET %s = %s, invalid JSON: %v", metricsPath, w.Body.String(), err)
+		}
+		return vars
+	}
+
+	const route = "GET /v1/dodo/{id}"
+	before := read()["rest_deprecated_requests"][route]
+	// gRPC 服务不可用，请求失败也计数
+	get(h, legacyReadPath+"1?api=v1")
+
+	vars := read()
+	if got := vars["rest_deprecated_requests"][route]; got != before+1 {
+		t.Errorf("rest_deprecated_requests[%q] = %d, want %d", route, got, before+1)
+	}
+	if _, ok := vars["cmdline"]; ok || len(vars) != len(metricVars) {
+		t.Errorf("GET %s = %v, want only %v", metricsPath, vars, metricVars)
+	}
+}
@@ -0,0 +1,120 @@
+package rest
+
+//go:generate go run gen_assets.go
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// openAPIPath 是 v1 OpenAPI 文档的路径
+	openAPIPath = "/openapi.json"
+	// openAPIV2Path 是 v2 OpenAPI 文档的路径
+	openAPIV2Path = "/v2/openapi.json"
+	// docsPath 是 Swagger UI 页面的路径
+	docsPath = "/docs/"
+)
+
+// swaggerUIPage 是 Swagger UI 的首页，文档地址使用相对路径，网关部署在反向代理的子路径下时也可以访问
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <meta charset="UTF-8">
+    <title>ToDo service API</title>
+    <link rel="stylesheet" type="text/css" href="swagger-ui.css" />
+    <link rel="icon" type="image/png" href="favicon-32x32.png" sizes="32x32" />
+  </head>
+  <body>
+    <div id="swagger-ui"></div>
+    <script src="swagger-ui-bundle.js" charset="UTF-8"></script>
+    <script src="swagger-ui-standalone-preset.js" charset="UTF-8"></script>
+    <script>
+      window.onload = function() {
+        window.ui = SwaggerUIBundle({
+          urls: [
+            {url: "../openapi.json", name: "v1"},
+            {url: "../v2/openapi.json", name: "v2"}
+          ],
+          dom_id: "#swagger-ui",
+          deepLinking: true,
+          presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+          layout: "StandaloneLayout"
+        });
+      };
+    </script>
+  </body>
+</html>
+`
+
+// parsePublicURL 解析客户端访问网关使用的地址，为空时返回 nil
+func parsePublicURL(s string) (*url.URL, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return nil, fmt.Errorf("invalid public URL '%s', must be http(s)://host[:port][/path]", s)
+	}
+	return u, nil
+}
+
+// newOpenAPIHandler 返回提供 OpenAPI 文档的 handler
+//
+// publicURL 不为 nil 时用它替换文档中的 host、schemes 和 basePath；
+// 为 nil 时去掉 host 和 schemes，Swagger UI 使用打开文档的地址调用接口
+func newOpenAPIHandler(spec string, publicURL *url.URL) (http.Handler, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(spec), &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %v", err)
+	}
+
+	delete(doc, "host")
+	delete(doc, "schemes")
+	if publicURL != nil {
+		doc["host"] = publicURL.Host
+		doc["schemes"] = []string{publicURL.Scheme}
+		if path := strings.TrimSuffix(publicURL.Path, "/"); len(path) > 0 {
+			doc["basePath"] = path
+		}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		http.ServeContent(w, r, "openapi.json", time.Time{}, strings.NewReader(string(data)))
+	}), nil
+}
+
+// swaggerUI 提供 Swagger UI 页面和它使用的文件
+func swaggerUI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, docsPath)
+	if len(name) == 0 || name == "index.html" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		http.ServeContent(w, r, "index.html", time.Time{}, strings.NewReader(swaggerUIPage))
+		return
+	}
+
+	data, ok := swaggerUIFiles[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeContent(w, r, name, time.Time{}, strings.NewReader(data))
+}
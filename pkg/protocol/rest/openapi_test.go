@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+)
+
+func newTestHandler(t *testing.T, publicURL string) (http.Handler, func()) {
+	conn, err := grpc.Dial("localhost:0", grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	h, err := NewHandler(context.Background(), conn, publicURL, nil)
+	if err != nil {
+		conn.Close()
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+	return h, func() { conn.Close() }
+}
+
+func get(h http.Handler, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestOpenAPI(t *testing.T) {
+	h, stop := newTestHandler(t, "https://todo.example.com/api/")
+	defer stop()
+
+	w := get(h, openAPIPath)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("GET %s = %d %s, want JSON", openAPIPath, w.Code, w.Header().Get("Content-Type"))
+	}
+	var spec struct {
+		Host     string                     `json:"host"`
+		Schemes  []string                   `json:"schemes"`
+		BasePath string                     `json:"basePath"`
+		Paths    map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("invalid spec: %v", err)
+	}
+	if spec.Host != "todo.example.com" || len(spec.Schemes) != 1 || spec.Schemes[0] != "https" || spec.BasePath != "/api" {
+		t.Errorf("spec host = %s, schemes = %v, basePath = %s, want the public URL", spec.Host, spec.Schemes, spec.BasePath)
+	}
+	if _, ok := spec.Paths["/v1/todo/{id}"]; !ok {
+		t.Errorf("spec paths = %v, want /v1/todo/{id}", spec.Paths)
+	}
+	if strings.Contains(w.Body.String(), "xxx.com") {
+		t.Error("spec contains placeholder contact xxx.com")
+	}
+
+	if w := get(h, openAPIV2Path); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"/v2/todos"`) {
+		t.Errorf("GET %s = %d, want v2 spec", openAPIV2Path, w.Code)
+	}
+
+	// 没有配置 publicURL 时不指定 host，使用打开文档的地址
+	h, stop = newTestHandler(t, "")
+	defer stop()
+	var doc map[string]interface{}
+	if err := json.Unmarshal(get(h, openAPIPath).Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid spec: %v", err)
+	}
+	if _, ok := doc["host"]; ok {
+		t.Errorf("spec host = %v, want no host", doc["host"])
+	}
+	if _, ok := doc["schemes"]; ok {
+		t.Errorf("spec schemes = %v, want no schemes", doc["schemes"])
+	}
+
+	for _, u := range []string{"todo.example.com", "ftp://todo.example.com", "https://"} {
+		if _, err := NewHandler(context.Background(), nil, u, nil); err == nil {
+			t.Errorf("NewHandler(%q) error = nil, want error", u)
+		}
+	}
+}
+
+func TestSwaggerUI(t *testing.T) {
+	h, stop := newTestHandler(t, "")
+	defer stop()
+
+	w := get(h, docsPath)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "swagger-ui-bundle.js") || !strings.Contains(w.Body.String(), "../openapi.json") {
+		t.Errorf("GET %s = %d %s, want Swagger UI page", docsPath, w.Code, w.Body.String())
+	}
+	w = get(h, docsPath+"swagger-ui-bundle.js")
+	if w.Code != http.StatusOK || !strings.Contains(w.Header().Get("Content-Type"), "javascript") || w.Body.Len() != len(swaggerUIFiles["swagger-ui-bundle.js"]) {
+		t.Errorf("GET swagger-ui-bundle.js = %d %s, want the bundled file", w.Code, w.Header().Get("Content-Type"))
+	}
+	if w := get(h, docsPath+"missing.js"); w.Code != http.StatusNotFound {
+		t.Errorf("GET missing.js = %d, want 404", w.Code)
+	}
+	if w := get(h, "/docs"); w.Code != http.StatusMovedPermanently {
+		t.Errorf("GET /docs = %d, want redirect to %s", w.Code, docsPath)
+	}
+}
+
+// TestAssetsUpToDate 检查修改 proto 或 Swagger UI 后是否运行了 go generate
+func TestAssetsUpToDate(t *testing.T) {
+	for version, spec := range openAPISpecs {
+		data, err := ioutil.ReadFile("../../../api/swagger/" + version + "/todo_service.swagger.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != spec {
+			t.Errorf("openAPISpecs[%q] is out of date, run go generate ./pkg/protocol/rest", version)
+		}
+	}
+	for name, content := range swaggerUIFiles {
+		data, err := ioutil.ReadFile("../../../third_party/swagger-ui/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("swaggerUIFiles[%q] is out of date, run go generate ./pkg/protocol/rest", name)
+		}
+	}
+}
@@ -4,26 +4,112 @@ import (
 	"context"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v2"
 	"google.golang.org/grpc"
 	"log"
 	"net/http"
 )
 
-func RunServer(ctx context.Context, grpcPort, httpPort string) error {
+// RunServer 启动 REST 网关，publicURL 是客户端访问网关使用的地址，用于 OpenAPI 文档中的 host
+// allowedOrigins 是除同源之外允许连接 WebSocket 端点的 Origin
+func RunServer(ctx context.Context, grpcPort, httpPort, publicURL string, allowedOrigins []string) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	mux := runtime.NewServeMux()
-	opts := []grpc.DialOption{grpc.WithInsecure()}
+	conn, err := grpc.DialContext(ctx, "localhost:"+grpcPort, grpc.WithInsecure())
+	if err != nil {
+		log.Fatalf("failed to dial gRPC server: %v", err)
+	}
+	defer conn.Close()
 
-	if err := v1.RegisterToDoServiceHandlerFromEndpoint(ctx, mux, "localhost:"+grpcPort, opts); err != nil {
+	handler, err := NewHandler(ctx, conn, publicURL, allowedOrigins)
+	if err != nil {
 		log.Fatalf("failed to start HTTP gateway: %v", err)
 	}
 
 	srv := &http.Server{
 		Addr:    ":" + httpPort,
-		Handler: mux,
+		Handler: handler,
 	}
 	log.Println("starting HTTP/REST gateway...")
 	return srv.ListenAndServe()
 }
+
+// NewHandler 创建通过 conn 调用 gRPC 服务的 REST 网关
+// publicURL 为空时 OpenAPI 文档不指定 host，使用打开文档的地址
+// allowedOrigins 为空时 WebSocket 端点只接受同源的浏览器连接
+func NewHandler(ctx context.Context, conn *grpc.ClientConn, publicURL string, allowedOrigins []string) (http.Handler, error) {
+	u, err := parsePublicURL(publicURL)
+	if err != nil {
+		return nil, err
+	}
+	openAPI, err := newOpenAPIHandler(openAPISpecs["v1"], u)
+	if err != nil {
+		return nil, err
+	}
+	openAPIV2, err := newOpenAPIHandler(openAPISpecs["v2"], u)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := runtime.NewServeMux(
+		runtime.WithProtoErrorHandler(errorHandler),
+		runtime.WithMetadata(requestIDMetadata),
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithMarshalerOption(ndjsonContentType, &ndjsonMarshaler{runtime.JSONPb{OrigName: true}}),
+	)
+
+	if err := v1.RegisterToDoServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := v1.RegisterWebhookServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+	if err := v2.RegisterToDoServiceHandler(ctx, mux, conn); err != nil {
+		return nil, err
+	}
+
+	// SSE/WebSocket、日历订阅和导入导出端点直接使用 gRPC 客户端
+	client := v1.NewToDoServiceClient(conn)
+	events := &eventsHandler{client: client, allowedOrigins: allowedOrigins}
+
+	handler := http.NewServeMux()
+	handler.Handle(eventsPath, events)
+	handler.Handle(eventsWebSocketPath, events.webSocketHandler())
+	handler.Handle(calendarPath, &calendarHandler{client: client})
+	transfer := &transferHandler{mux: mux, client: client}
+	handler.HandleFunc(exportPath, transfer.export)
+	handler.HandleFunc(importPath, transfer.importFile)
+	handler.Handle(openAPIPath, openAPI)
+	handler.Handle(openAPIV2Path, openAPIV2)
+	handler.HandleFunc(docsPath, swaggerUI)
+	handler.HandleFunc(metricsPath, metrics)
+	handler.Handle(legacyReadPath, deprecated("GET /v1/dodo/{id}", readDeprecationTime, readSuccessor, mux))
+	handler.Handle("/", mux)
+
+	return withRequestID(handler), nil
+}
+
+// headerMatcher 在默认规则之外把 Idempotency-Key 头转发给 gRPC 服务
+func headerMatcher(key string) (string, bool) {
+	if key == "Idempotency-Key" {
+		return "idempotency-key", true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// ndjsonContentType 是换行分隔的 JSON, 客户端通过 Accept 头请求流式接口使用该格式
+const ndjsonContentType = "application/x-ndjson"
+
+// ndjsonMarshaler 与默认的 JSON marshaler 相同，只是响应的 Content-Type 不同
+type ndjsonMarshaler struct {
+	runtime.JSONPb
+}
+
+func (*ndjsonMarshaler) ContentType() string {
+	return ndjsonContentType
+}
+
+func (*ndjsonMarshaler) Delimiter() []byte {
+	return []byte("\n")
+}
@@ -0,0 +1,262 @@
+package rest
+
+import (
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// exportPath 下载 ToDo，参数 format 为 jsonl (默认) 或 csv，showDeleted、reminderFrom、reminderTo (RFC 3339) 和 titleContains 过滤导出的 ToDo
+	exportPath = "/v1/todo:export"
+	// importPath 上传 ToDo，请求体是文件本身或 multipart/form-data 中名为 file 的部分，参数 dryRun=true 只返回报告
+	importPath = "/v1/todo:import"
+
+	// importChunkSize 是上传数据发送给 gRPC Import 的分块大小
+	importChunkSize = 64 * 1024
+)
+
+// fileFormat 是数据格式对应的 Content-Type 和文件扩展名
+type fileFormat struct {
+	contentType string
+	ext         string
+}
+
+var fileFormats = map[v1.DataFormat]fileFormat{
+	v1.DataFormat_JSONL: {ndjsonContentType, ".jsonl"},
+	v1.DataFormat_CSV:   {"text/csv; charset=utf-8", ".csv"},
+}
+
+// formatNames 是 format 参数、Content-Type 和文件扩展名可以使用的名字
+var formatNames = map[string]v1.DataFormat{
+	"jsonl":                   v1.DataFormat_JSONL,
+	"ndjson":                  v1.DataFormat_JSONL,
+	".jsonl":                  v1.DataFormat_JSONL,
+	".ndjson":                 v1.DataFormat_JSONL,
+	ndjsonContentType:         v1.DataFormat_JSONL,
+	"application/jsonl":       v1.DataFormat_JSONL,
+	"application/json-lines":  v1.DataFormat_JSONL,
+	"application/x-jsonlines": v1.DataFormat_JSONL,
+	"csv":                     v1.DataFormat_CSV,
+	".csv":                    v1.DataFormat_CSV,
+	"text/csv":                v1.DataFormat_CSV,
+}
+
+// transferHandler 把 gRPC Export/Import 流转换为文件下载和上传
+type transferHandler struct {
+	mux    *runtime.ServeMux
+	client v1.ToDoServiceClient
+}
+
+// invalidRequest 返回参数错误
+func invalidRequest(w http.ResponseWriter, r *http.Request, format string, a ...interface{}) {
+	errorHandler(r.Context(), nil, nil, w, r, status.Errorf(codes.InvalidArgument, format, a...))
+}
+
+// export 以附件形式输出 Export 流，第一个分块到达后才写入响应头，服务器返回的错误可以转换为错误响应
+func (h *transferHandler) export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	req := &v1.ExportRequest{Api: "v1", TitleContains: q.Get("titleContains")}
+	if f := q.Get("format"); len(f) > 0 {
+		format, ok := formatNames[strings.ToLower(f)]
+		if !ok {
+			invalidRequest(w, r, "unsupported format %q, must be jsonl or csv", f)
+			return
+		}
+		req.Format = format
+	}
+	if v := q.Get("showDeleted"); len(v) > 0 {
+		showDeleted, err := strconv.ParseBool(v)
+		if err != nil {
+			invalidRequest(w, r, "invalid showDeleted %q", v)
+			return
+		}
+		req.ShowDeleted = showDeleted
+	}
+	if v := q.Get("reminderFrom"); len(v) > 0 {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			invalidRequest(w, r, "invalid reminderFrom %q, must be RFC 3339", v)
+			return
+		}
+		req.ReminderFrom, _ = ptypes.TimestampProto(t)
+	}
+	if v := q.Get("reminderTo"); len(v) > 0 {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			invalidRequest(w, r, "invalid reminderTo %q, must be RFC 3339", v)
+			return
+		}
+		req.ReminderTo, _ = ptypes.TimestampProto(t)
+	}
+
+	ctx, err := runtime.AnnotateContext(r.Context(), h.mux, r)
+	if err != nil {
+		errorHandler(r.Context(), nil, nil, w, r, err)
+		return
+	}
+
+	stream, err := h.client.Export(ctx, req)
+	if err != nil {
+		errorHandler(ctx, nil, nil, w, r, err)
+		return
+	}
+	chunk, err := stream.Recv()
+	if err != nil && err != io.EOF {
+		errorHandler(ctx, nil, nil, w, r, err)
+		return
+	}
+
+	f := fileFormats[req.Format]
+	w.Header().Set("Content-Type", f.contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "todos" + f.ext}))
+	flusher, _ := w.(http.Flusher)
+	for err == nil {
+		if _, err := w.Write(chunk.Data); err != nil {
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		chunk, err = stream.Recv()
+	}
+	// 响应头已经发送，只能中断下载
+	if err != io.EOF {
+		log.Printf("export interrupted: %v", err)
+	}
+}
+
+// uploadFormat 按 format 参数、Content-Type、Content-Disposition 的文件名的顺序确定上传文件的格式
+func uploadFormat(param string, header interface{ Get(string) string }, filename string) (v1.DataFormat, bool) {
+	if len(param) > 0 {
+		format, ok := formatNames[strings.ToLower(param)]
+		return format, ok
+	}
+	if mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type")); err == nil {
+		if format, ok := formatNames[mediaType]; ok {
+			return format, true
+		}
+	}
+	if len(filename) == 0 {
+		if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil {
+			filename = params["filename"]
+		}
+	}
+	format, ok := formatNames[strings.ToLower(path.Ext(filename))]
+	return format, ok
+}
+
+// importFile 把上传的文件分块发送给 gRPC Import，返回 JSON 格式的导入报告
+func (h *transferHandler) importFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	req := &v1.ImportRequest{Api: "v1"}
+	if v := q.Get("dryRun"); len(v) > 0 {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			invalidRequest(w, r, "invalid dryRun %q", v)
+			return
+		}
+		req.DryRun = dryRun
+	}
+
+	var body io.Reader = r.Body
+	var header interface{ Get(string) string } = r.Header
+	var filename string
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		mr, err := r.MultipartReader()
+		if err != nil {
+			invalidRequest(w, r, "invalid multipart body: %v", err)
+			return
+		}
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				invalidRequest(w, r, "multipart body must contain a file part")
+				return
+			}
+			if part.FormName() == "file" {
+				body, header, filename = part, part.Header, part.FileName()
+				break
+			}
+		}
+	}
+
+	format, ok := uploadFormat(q.Get("format"), header, filename)
+	if !ok {
+		invalidRequest(w, r, "unknown upload format, use format=jsonl or format=csv")
+		return
+	}
+	req.Format = format
+
+	ctx, err := runtime.AnnotateContext(r.Context(), h.mux, r)
+	if err != nil {
+		errorHandler(r.Context(), nil, nil, w, r, err)
+		return
+	}
+
+	stream, err := h.client.Import(ctx)
+	if err != nil {
+		errorHandler(ctx, nil, nil, w, r, err)
+		return
+	}
+
+	// Send 返回 io.EOF 表示服务器已经结束了流，错误由 CloseAndRecv 返回
+	buf := make([]byte, importChunkSize)
+	for sendErr := error(nil); sendErr == nil; {
+		n, err := io.ReadFull(body, buf)
+		if n > 0 {
+			req.Data = buf[:n]
+			sendErr = stream.Send(req)
+			req = &v1.ImportRequest{}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			invalidRequest(w, r, "failed to read upload: %v", err)
+			return
+		}
+	}
+	if req.Api == "v1" {
+		// 空文件也发送一个消息，让服务器知道 api、format 和 dryRun
+		if err := stream.Send(req); err != nil && err != io.EOF {
+			errorHandler(ctx, nil, nil, w, r, err)
+			return
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		errorHandler(ctx, nil, nil, w, r, err)
+		return
+	}
+
+	m := jsonpb.Marshaler{OrigName: true}
+	w.Header().Set("Content-Type", "application/json")
+	if err := m.Marshal(w, resp); err != nil {
+		log.Printf("failed to write import response: %v", err)
+	}
+}
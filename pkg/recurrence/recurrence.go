@@ -0,0 +1,593 @@
+// Package recurrence 解析和展开 RFC 5545 的重复规则 (DTSTART/RRULE/EXDATE)
+//
+// 规则的文本由换行分隔的内容行组成，例如:
+//
+//	DTSTART;TZID=Europe/Berlin:20190506T090000
+//	RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10
+//	EXDATE;TZID=Europe/Berlin:20190508T090000
+//
+// 支持 FREQ 为 DAILY、WEEKLY、MONTHLY、YEARLY，以及 INTERVAL、COUNT、UNTIL、BYDAY、BYMONTHDAY、BYMONTH、WKST。
+// 重复时间按 DTSTART 所在时区的墙上时间展开，夏令时切换前后的本地时间保持不变。
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// dateTimeLayout 是 RFC 5545 DATE-TIME 的本地时间格式
+	dateTimeLayout = "20060102T150405"
+
+	// maxPeriods 是查找下一次重复时最多展开的周期数，避免永远不匹配的规则 (例如 2 月 30 日) 死循环
+	maxPeriods = 10000
+)
+
+// Frequency 是 RRULE 的 FREQ
+type Frequency int
+
+const (
+	Daily Frequency = iota
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencies = map[string]Frequency{"DAILY": Daily, "WEEKLY": Weekly, "MONTHLY": Monthly, "YEARLY": Yearly}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// WeekdayNum 是 BYDAY 的一项，N 不为 0 时表示当月第 N 个 (负数从月末倒数) Weekday
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// Rule 是解析后的重复规则
+type Rule struct {
+	// Start 是第一次重复的时间，所在时区决定展开时使用的墙上时间
+	Start    time.Time
+	Freq     Frequency
+	Interval int
+	// Count 为 0 表示不限次数
+	Count int
+	// Until 为零值表示不限结束时间
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+	// ExDates 是被排除的重复时间
+	ExDates []time.Time
+}
+
+// Parse 解析规则文本; 文本中没有 DTSTART 时使用 start 作为第一次重复的时间
+// 没有 TZID 也不以 Z 结尾的本地时间按 start 所在的时区解析
+func Parse(s string, start time.Time) (*Rule, error) {
+	r := &Rule{Start: start, Interval: 1, WeekStart: time.Monday}
+	loc := start.Location()
+
+	var hasRule bool
+	for _, line := range strings.Split(strings.Replace(s, "\r\n", "\n", -1), "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+		params := strings.Split(line[:colon], ";")
+		name, value := strings.ToUpper(params[0]), line[colon+1:]
+
+		switch name {
+		case "DTSTART":
+			times, err := parseTimes(params[1:], value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DTSTART: %v", err)
+			}
+			r.Start = times[0]
+		case "RRULE":
+			if hasRule {
+				return nil, errors.New("only one RRULE is supported")
+			}
+			hasRule = true
+			if err := r.parseRule(value); err != nil {
+				return nil, fmt.Errorf("invalid RRULE: %v", err)
+			}
+		case "EXDATE":
+			times, err := parseTimes(params[1:], value, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid EXDATE: %v", err)
+			}
+			r.ExDates = append(r.ExDates, times...)
+		default:
+			return nil, fmt.Errorf("unsupported property %s", name)
+		}
+	}
+
+	if !hasRule {
+		return nil, errors.New("RRULE is required")
+	}
+	if r.Start.IsZero() {
+		return nil, errors.New("DTSTART is required")
+	}
+	if r.Freq == Yearly && len(r.ByMonth) == 0 && (len(r.ByDay) > 0 || len(r.ByMonthDay) > 0) {
+		return nil, errors.New("YEARLY with BYDAY or BYMONTHDAY requires BYMONTH")
+	}
+	return r, nil
+}
+
+// parseTimes 解析 DTSTART/EXDATE 的值，TZID 参数指定本地时间的时区 (默认为 loc)，以 Z 结尾的值是 UTC
+func parseTimes(params []string, value string, loc *time.Location) ([]time.Time, error) {
+	for _, p := range params {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) == 2 && strings.ToUpper(kv[0]) == "TZID" {
+			l, err := time.LoadLocation(kv[1])
+			if err != nil {
+				return nil, fmt.Errorf("unknown time zone %q", kv[1])
+			}
+			loc = l
+		}
+	}
+
+	var times []time.Time
+	for _, v := range strings.Split(value, ",") {
+		t, err := parseTime(v, loc)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+func parseTime(v string, loc *time.Location) (time.Time, error) {
+	if strings.HasSuffix(v, "Z") {
+		return time.ParseInLocation(dateTimeLayout, strings.TrimSuffix(v, "Z"), time.UTC)
+	}
+	return time.ParseInLocation(dateTimeLayout, v, loc)
+}
+
+func (r *Rule) parseRule(s string) error {
+	var hasFreq bool
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("invalid part %q", part)
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		var err error
+		switch key {
+		case "FREQ":
+			f, ok := frequencies[value]
+			if !ok {
+				return fmt.Errorf("unsupported FREQ %s", value)
+			}
+			r.Freq, hasFreq = f, true
+		case "INTERVAL":
+			r.Interval, err = positive(value)
+		case "COUNT":
+			r.Count, err = positive(value)
+		case "UNTIL":
+			r.Until, err = parseTime(value, time.UTC)
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				wd, ok := weekdays[d[max(len(d)-2, 0):]]
+				if !ok {
+					return fmt.Errorf("invalid BYDAY %s", d)
+				}
+				var n int
+				if len(d) > 2 {
+					if n, err = strconv.Atoi(d[:len(d)-2]); err != nil || n == 0 || n < -5 || n > 5 {
+						return fmt.Errorf("invalid BYDAY %s", d)
+					}
+				}
+				r.ByDay = append(r.ByDay, WeekdayNum{N: n, Weekday: wd})
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return fmt.Errorf("invalid BYMONTHDAY %s", d)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, m := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(m)
+				if err != nil || n < 1 || n > 12 {
+					return fmt.Errorf("invalid BYMONTH %s", m)
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(n))
+			}
+		case "WKST":
+			wd, ok := weekdays[value]
+			if !ok {
+				return fmt.Errorf("invalid WKST %s", value)
+			}
+			r.WeekStart = wd
+		default:
+			return fmt.Errorf("unsupported part %s", key)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s %s", key, value)
+		}
+	}
+
+	if !hasFreq {
+		return errors.New("FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return errors.New("COUNT and UNTIL must not both be set")
+	}
+	if r.Freq != Monthly && r.Freq != Yearly {
+		for _, d := range r.ByDay {
+			if d.N != 0 {
+				return errors.New("BYDAY with ordinal is only supported with MONTHLY or YEARLY")
+			}
+		}
+	}
+	return nil
+}
+
+func positive(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, errors.New("must be a positive number")
+	}
+	return n, nil
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// String 返回规则的规范文本，总是包含 DTSTART
+func (r *Rule) String() string {
+	return r.format(r.Start.Location())
+}
+
+// LocalString 返回不带时区的规范文本: DTSTART 和 EXDATE 是 Start 所在时区的墙上时间，
+// 再次解析时按 Parse 的 start 所在的时区解释，所以同一条规则可以换到另一个时区展开
+func (r *Rule) LocalString() string {
+	return r.format(nil)
+}
+
+// format 返回规范文本，loc 为 nil 时 DTSTART 和 EXDATE 格式化为不带时区的本地时间
+func (r *Rule) format(loc *time.Location) string {
+	lines := []string{"DTSTART" + formatTimes(loc, r.Start)}
+
+	parts := []string{"FREQ=" + [...]string{"DAILY", "WEEKLY", "MONTHLY", "YEARLY"}[r.Freq]}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(dateTimeLayout)+"Z")
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			days[i] = strings.ToUpper(d.Weekday.String()[:2])
+			if d.N != 0 {
+				days[i] = strconv.Itoa(d.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+strings.ToUpper(r.WeekStart.String()[:2]))
+	}
+	lines = append(lines, "RRULE:"+strings.Join(parts, ";"))
+
+	if len(r.ExDates) > 0 {
+		exDates := r.ExDates
+		if loc == nil {
+			// 不带时区的 EXDATE 和 DTSTART 使用相同时区的墙上时间
+			exDates = make([]time.Time, len(r.ExDates))
+			for i, t := range r.ExDates {
+				exDates[i] = t.In(r.Start.Location())
+			}
+		}
+		lines = append(lines, "EXDATE"+formatTimes(loc, exDates...))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatTimes 把时间格式化为 ";TZID=zone:value,..." 或 UTC 的 ":valueZ,..."，loc 为 nil 时格式化为本地时间 ":value,..."
+func formatTimes(loc *time.Location, times ...time.Time) string {
+	values := make([]string, len(times))
+	if loc == nil {
+		for i, t := range times {
+			values[i] = t.Format(dateTimeLayout)
+		}
+		return ":" + strings.Join(values, ",")
+	}
+	if loc == time.UTC || loc.String() == "Local" {
+		for i, t := range times {
+			values[i] = t.UTC().Format(dateTimeLayout) + "Z"
+		}
+		return ":" + strings.Join(values, ",")
+	}
+	for i, t := range times {
+		values[i] = t.In(loc).Format(dateTimeLayout)
+	}
+	return ";TZID=" + loc.String() + ":" + strings.Join(values, ",")
+}
+
+// After 返回 t 之后 (不包括 t) 的下一次重复，没有更多重复时返回 false
+func (r *Rule) After(t time.Time) (time.Time, bool) {
+	next := r.Next(t, 1)
+	if len(next) == 0 {
+		return time.Time{}, false
+	}
+	return next[0], true
+}
+
+// Next 返回 t 之后 (不包括 t) 最多 n 次重复
+func (r *Rule) Next(t time.Time, n int) []time.Time {
+	return r.Between(t, time.Time{}, n)
+}
+
+// Between 返回 t 之后 (不包括 t)、until 之前 (包括 until) 最多 n 次重复，until 为零值时不限结束时间
+func (r *Rule) Between(t, until time.Time, n int) []time.Time {
+	var result []time.Time
+	if n <= 0 {
+		return result
+	}
+	r.each(t, until, func(occurrence time.Time) bool {
+		if occurrence.After(t) {
+			result = append(result, occurrence)
+		}
+		return len(result) < n
+	})
+	return result
+}
+
+// each 按时间顺序对 from 之后、until 之前的每次重复调用 f，直到 f 返回 false 或没有更多重复
+// 没有 COUNT 时直接从 from 所在的周期开始展开，不逐个展开 from 之前的周期
+func (r *Rule) each(from, until time.Time, f func(time.Time) bool) {
+	excluded := make(map[int64]bool, len(r.ExDates))
+	for _, t := range r.ExDates {
+		excluded[t.Unix()] = true
+	}
+
+	count := 0
+	empty := 0
+	for period := r.firstPeriod(from); empty < maxPeriods; period++ {
+		if !until.IsZero() && r.periodStart(period).After(until) {
+			return
+		}
+
+		candidates := r.expand(period)
+		if len(candidates) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+
+		for _, c := range candidates {
+			if c.Before(r.Start) {
+				continue
+			}
+			if !r.Until.IsZero() && c.After(r.Until) || !until.IsZero() && c.After(until) {
+				return
+			}
+			// COUNT 包括被 EXDATE 排除的重复
+			count++
+			if r.Count > 0 && count > r.Count {
+				return
+			}
+			if excluded[c.Unix()] {
+				continue
+			}
+			if !f(c) {
+				return
+			}
+		}
+	}
+}
+
+// firstPeriod 返回可能包含 t 之后重复的第一个周期
+// 有 COUNT 时必须从第一个周期开始计数，返回 0
+func (r *Rule) firstPeriod(t time.Time) int {
+	if r.Count > 0 || !t.After(r.Start) {
+		return 0
+	}
+
+	s := r.Start
+	t = t.In(s.Location())
+	days := int(civil(t).Sub(civil(s)).Hours() / 24)
+
+	var n int
+	switch r.Freq {
+	case Daily:
+		n = days
+	case Weekly:
+		n = (days + (int(s.Weekday())-int(r.WeekStart)+7)%7) / 7
+	case Monthly:
+		n = (t.Year()-s.Year())*12 + int(t.Month()-s.Month())
+	case Yearly:
+		n = t.Year() - s.Year()
+	}
+
+	// 从前一个周期开始，周期边界附近的重复不会被跳过
+	if period := n/r.Interval - 1; period > 0 {
+		return period
+	}
+	return 0
+}
+
+// periodStart 返回第 period 个周期开始的日期，不晚于该周期中的任何重复
+func (r *Rule) periodStart(period int) time.Time {
+	s := r.Start
+	loc := s.Location()
+	switch r.Freq {
+	case Daily:
+		return time.Date(s.Year(), s.Month(), s.Day()+period*r.Interval, 0, 0, 0, 0, loc)
+	case Weekly:
+		offset := (int(s.Weekday()) - int(r.WeekStart) + 7) % 7
+		return time.Date(s.Year(), s.Month(), s.Day()-offset+7*period*r.Interval, 0, 0, 0, 0, loc)
+	case Monthly:
+		return time.Date(s.Year(), s.Month()+time.Month(period*r.Interval), 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(s.Year()+period*r.Interval, time.January, 1, 0, 0, 0, 0, loc)
+	}
+}
+
+// civil 返回 t 的本地日期，用于按日历计算相差的天数
+func civil(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// expand 返回第 period 个周期中按 BYxxx 展开后的重复时间，已排序
+func (r *Rule) expand(period int) []time.Time {
+	loc := r.Start.Location()
+	s := r.Start
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, s.Hour(), s.Minute(), s.Second(), 0, loc)
+	}
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		days = []time.Time{at(s.Year(), s.Month(), s.Day()+period*r.Interval)}
+	case Weekly:
+		offset := (int(s.Weekday()) - int(r.WeekStart) + 7) % 7
+		first := at(s.Year(), s.Month(), s.Day()-offset+7*period*r.Interval)
+		if len(r.ByDay) == 0 {
+			days = []time.Time{first.AddDate(0, 0, offset)}
+		}
+		for i := 0; i < 7; i++ {
+			d := at(first.Year(), first.Month(), first.Day()+i)
+			if r.matchesWeekday(d) {
+				days = append(days, d)
+			}
+		}
+	case Monthly:
+		days = r.expandMonth(s.Year(), s.Month()+time.Month(period*r.Interval), at)
+	case Yearly:
+		year := s.Year() + period*r.Interval
+		if len(r.ByMonth) == 0 {
+			if d := at(year, s.Month(), s.Day()); d.Month() == s.Month() {
+				days = []time.Time{d}
+			}
+			break
+		}
+		for _, m := range r.ByMonth {
+			days = append(days, r.expandMonth(year, m, at)...)
+		}
+	}
+
+	var result []time.Time
+	for _, d := range days {
+		if len(r.ByMonth) > 0 && !containsMonth(r.ByMonth, d.Month()) {
+			continue
+		}
+		if r.Freq == Daily && (len(r.ByDay) > 0 && !r.matchesWeekday(d) || len(r.ByMonthDay) > 0 && !r.matchesMonthDay(d)) {
+			continue
+		}
+		result = append(result, d)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Before(result[j]) })
+	return result
+}
+
+// expandMonth 展开一个月中的重复日期; 没有 BYDAY 和 BYMONTHDAY 时使用 DTSTART 的日期，该月没有这一天时跳过
+func (r *Rule) expandMonth(year int, month time.Month, at func(int, time.Month, int) time.Time) []time.Time {
+	first := at(year, month, 1)
+	year, month = first.Year(), first.Month()
+	last := at(year, month+1, 0).Day()
+
+	if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+		if d := r.Start.Day(); d <= last {
+			return []time.Time{at(year, month, d)}
+		}
+		return nil
+	}
+
+	var days []time.Time
+	for day := 1; day <= last; day++ {
+		d := at(year, month, day)
+		if len(r.ByDay) > 0 && !r.matchesMonthWeekday(d, last) {
+			continue
+		}
+		if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(d) {
+			continue
+		}
+		days = append(days, d)
+	}
+	return days
+}
+
+func (r *Rule) matchesWeekday(d time.Time) bool {
+	for _, w := range r.ByDay {
+		if w.Weekday == d.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMonthWeekday 判断 d 是否匹配 BYDAY，带序号的项按 d 在当月中是第几个该星期几计算
+func (r *Rule) matchesMonthWeekday(d time.Time, last int) bool {
+	for _, w := range r.ByDay {
+		if w.Weekday != d.Weekday() {
+			continue
+		}
+		if w.N == 0 ||
+			w.N > 0 && (d.Day()-1)/7+1 == w.N ||
+			w.N < 0 && (last-d.Day())/7+1 == -w.N {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(d time.Time) bool {
+	last := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, n := range r.ByMonthDay {
+		if n == d.Day() || n < 0 && last+n+1 == d.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func containsMonth(months []time.Month, m time.Month) bool {
+	for _, x := range months {
+		if x == m {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package recurrence
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database is not available: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		rule string
+		n    int
+		want []string
+	}{
+		{
+			name: "weekly on two days",
+			rule: "DTSTART:20190506T090000Z\nRRULE:FREQ=WEEKLY;BYDAY=MO,WE",
+			n:    4,
+			want: []string{"2019-05-06T09:00:00Z", "2019-05-08T09:00:00Z", "2019-05-13T09:00:00Z", "2019-05-15T09:00:00Z"},
+		},
+		{
+			name: "count includes excluded dates",
+			rule: "DTSTART:20190501T080000Z\nRRULE:FREQ=DAILY;COUNT=3\nEXDATE:20190502T080000Z",
+			n:    10,
+			want: []string{"2019-05-01T08:00:00Z", "2019-05-03T08:00:00Z"},
+		},
+		{
+			name: "last friday of the month",
+			rule: "DTSTART:20190101T170000Z\nRRULE:FREQ=MONTHLY;BYDAY=-1FR",
+			n:    3,
+			want: []string{"2019-01-25T17:00:00Z", "2019-02-22T17:00:00Z", "2019-03-29T17:00:00Z"},
+		},
+		{
+			name: "monthly skips months without the day",
+			rule: "DTSTART:20190131T120000Z\nRRULE:FREQ=MONTHLY",
+			n:    3,
+			want: []string{"2019-01-31T12:00:00Z", "2019-03-31T12:00:00Z", "2019-05-31T12:00:00Z"},
+		},
+		{
+			name: "until",
+			rule: "DTSTART:20190101T000000Z\nRRULE:FREQ=YEARLY;INTERVAL=2;UNTIL=20230101T000000Z",
+			n:    10,
+			want: []string{"2019-01-01T00:00:00Z", "2021-01-01T00:00:00Z", "2023-01-01T00:00:00Z"},
+		},
+		{
+			// 夏令时在 2019-03-31 开始，本地时间保持 09:00
+			name: "wall clock across DST",
+			rule: "DTSTART;TZID=Europe/Berlin:20190330T090000\nRRULE:FREQ=DAILY",
+			n:    2,
+			want: []string{"2019-03-30T08:00:00Z", "2019-03-31T07:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule, time.Time{})
+			if err != nil {
+				t.Fatalf("Parse() error =%v", err)
+			}
+
+			got := r.Next(r.Start.Add(-time.Second), tt.n)
+			var s []string
+			for _, g := range got {
+				s = append(s, g.UTC().Format(time.RFC3339))
+			}
+			if strings.Join(s, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Next() =%v, want %v", s, tt.want)
+			}
+		})
+	}
+
+	r, _ := Parse("DTSTART;TZID=Europe/Berlin:20190330T090000\nRRULE:FREQ=DAILY", time.Time{})
+	if r.Start.Location().String() != berlin.String() {
+		t.Errorf("Start location =%v, want %v", r.Start.Location(), berlin)
+	}
+}
+
+func TestNextFarFromStart(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		after string
+		want  []string
+	}{
+		{
+			name:  "daily",
+			rule:  "DTSTART:20190506T090000Z\nRRULE:FREQ=DAILY;INTERVAL=3",
+			after: "2100-01-01T00:00:00Z",
+			want:  []string{"2100-01-01T09:00:00Z", "2100-01-04T09:00:00Z"},
+		},
+		{
+			name:  "weekly with week start",
+			rule:  "DTSTART:20190506T090000Z\nRRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU;WKST=SU",
+			after: "2100-01-01T00:00:00Z",
+			want:  []string{"2100-01-10T09:00:00Z", "2100-01-11T09:00:00Z"},
+		},
+		{
+			name:  "monthly",
+			rule:  "DTSTART:20190131T120000Z\nRRULE:FREQ=MONTHLY",
+			after: "2100-01-31T12:00:00Z",
+			want:  []string{"2100-03-31T12:00:00Z", "2100-05-31T12:00:00Z"},
+		},
+		{
+			name:  "yearly on leap day",
+			rule:  "DTSTART:20200229T080000Z\nRRULE:FREQ=YEARLY",
+			after: "2100-01-01T00:00:00Z",
+			want:  []string{"2104-02-29T08:00:00Z", "2108-02-29T08:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule, time.Time{})
+			if err != nil {
+				t.Fatalf("Parse() error =%v", err)
+			}
+			after, _ := time.Parse(time.RFC3339, tt.after)
+
+			var s []string
+			for _, g := range r.Next(after, 2) {
+				s = append(s, g.UTC().Format(time.RFC3339))
+			}
+			if strings.Join(s, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Next() =%v, want %v", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	// 永远不匹配的规则在 until 之后停止展开
+	r, err := Parse("DTSTART:20190101T000000Z\nRRULE:FREQ=DAILY;BYMONTH=2;BYMONTHDAY=30", time.Time{})
+	if err != nil {
+		t.Fatalf("Parse() error =%v", err)
+	}
+	if got := r.Between(r.Start, r.Start.AddDate(1, 0, 0), 10); len(got) != 0 {
+		t.Errorf("Between() =%v, want none", got)
+	}
+
+	r, _ = Parse("DTSTART:20190101T090000Z\nRRULE:FREQ=WEEKLY", time.Time{})
+	got := r.Between(r.Start, r.Start.AddDate(0, 0, 21), 10)
+	if len(got) != 3 || !got[2].Equal(r.Start.AddDate(0, 0, 21)) {
+		t.Errorf("Between() =%v, want 3 weeks including until", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	start := time.Date(2019, 5, 6, 9, 0, 0, 0, time.UTC)
+
+	r, err := Parse("RRULE:FREQ=WEEKLY;BYDAY=MO", start)
+	if err != nil {
+		t.Fatalf("Parse() error =%v", err)
+	}
+	if want := "DTSTART:20190506T090000Z\nRRULE:FREQ=WEEKLY;BYDAY=MO"; r.String() != want {
+		t.Errorf("String() =%q, want %q", r.String(), want)
+	}
+	if next, ok := r.After(start); !ok || !next.Equal(start.AddDate(0, 0, 7)) {
+		t.Errorf("After() =%v, %v", next, ok)
+	}
+
+	// 没有 TZID 的本地时间按 start 的时区解析
+	if berlin, err := time.LoadLocation("Europe/Berlin"); err == nil {
+		r, err := Parse("DTSTART:20190506T090000\nRRULE:FREQ=DAILY", start.In(berlin))
+		if err != nil {
+			t.Fatalf("Parse() error =%v", err)
+		}
+		if want := "DTSTART;TZID=Europe/Berlin:20190506T090000\nRRULE:FREQ=DAILY"; r.String() != want {
+			t.Errorf("String() =%q, want %q", r.String(), want)
+		}
+	}
+
+	// LocalString 不带时区，EXDATE 换算到 DTSTART 的时区
+	if berlin, err := time.LoadLocation("Europe/Berlin"); err == nil {
+		r, err := Parse("DTSTART;TZID=Europe/Berlin:20190506T090000\nRRULE:FREQ=DAILY\nEXDATE:20190507T070000Z", start)
+		if err != nil {
+			t.Fatalf("Parse() error =%v", err)
+		}
+		if want := "DTSTART:20190506T090000\nRRULE:FREQ=DAILY\nEXDATE:20190507T090000"; r.LocalString() != want {
+			t.Errorf("LocalString() =%q, want %q", r.LocalString(), want)
+		}
+		if _, err := Parse(r.LocalString(), start.In(berlin)); err != nil {
+			t.Errorf("Parse(LocalString()) error =%v", err)
+		}
+	}
+
+	for _, invalid := range []string{
+		"",
+		"RRULE:FREQ=SECONDLY",
+		"RRULE:FREQ=DAILY;COUNT=0",
+		"RRULE:FREQ=DAILY;COUNT=2;UNTIL=20200101T000000Z",
+		"RRULE:FREQ=WEEKLY;BYDAY=1MO",
+		"RRULE:FREQ=YEARLY;BYDAY=MO",
+		"RRULE:FREQ=DAILY\nRDATE:20190101T000000Z",
+		"DTSTART;TZID=Nowhere/City:20190101T000000\nRRULE:FREQ=DAILY",
+	} {
+		if _, err := Parse(invalid, start); err == nil {
+			t.Errorf("Parse(%q) expected error", invalid)
+		}
+	}
+}
@@ -0,0 +1,515 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+// maxBatchSize 是一次批量请求允许的最大条目数，保证多行语句的参数个数不超过 MySQL 的限制
+const maxBatchSize = 5000
+
+// okStatus 是批量请求中成功条目的状态
+var okStatus = &rpcstatus.Status{Code: int32(codes.OK)}
+
+// checkBatchSize 检查批量请求的条目数
+func checkBatchSize(n int) error {
+	if n == 0 {
+		return status.Error(codes.InvalidArgument, "batch must not be empty")
+	}
+	if n > maxBatchSize {
+		return status.Errorf(codes.InvalidArgument, "batch must not contain more than %d items", maxBatchSize)
+	}
+	return nil
+}
+
+// placeholders 生成 n 组以逗号分隔的 group，例如 "(?,?,?),(?,?,?)"
+func placeholders(n int, group string) string {
+	return strings.TrimSuffix(strings.Repeat(group+",", n), ",")
+}
+
+// begin 开启事务
+func (s *server) begin(ctx context.Context) (*sql.Tx, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, unavailable(ctx, err)
+	}
+	return tx, nil
+}
+
+// BatchCreate 在一个事务中创建多个 ToDo
+// bestEffort 为 false 时任何一个条目失败整个请求都失败; 为 true 时跳过失败的条目，在结果中返回每个条目的错误
+func (t *toDoServiceServer) BatchCreate(ctx context.Context, in *v1.BatchCreateRequest) (*v1.BatchCreateResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	if err := checkBatchSize(len(in.ToDos)); err != nil {
+		return nil, err
+	}
+
+	results := make([]*v1.BatchResult, len(in.ToDos))
+	valid, err := validateBatch(in.ToDos, in.BestEffort, results, createToDoRules)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range valid {
+		normalizeRecurrence(in.ToDos[i])
+	}
+
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	err = execBatch(ctx, valid, nil, in.BestEffort, results, "failed to insert into ToDo", func(items []int) error {
+		for _, i := range items {
+			// 逐条重试时跳过之前已经插入的条目
+			if results[i] != nil {
+				continue
+			}
+			ids, err := insertToDos(ctx, tx, in.ToDos[i:i+1], nil)
+			if err != nil {
+				return err
+			}
+			results[i] = &v1.BatchResult{Id: ids[0], Status: okStatus}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []historyEntry
+	for i, r := range results {
+		if r.Status.Code == int32(codes.OK) {
+			created := *in.ToDos[i]
+			created.Id = r.Id
+			entries = append(entries, historyEntry{v1.ToDoEvent_CREATED, nil, &created})
+		}
+	}
+	if err := recordHistory(ctx, tx, principal(ctx), entries...); err != nil {
+		return nil, dbError(ctx, "failed to insert into ToDoHistory", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit transaction", err)
+	}
+
+	for _, e := range entries {
+		t.hub.Publish(v1.ToDoEvent_CREATED, e.new)
+	}
+
+	return &v1.BatchCreateResponse{
+		Api:     apiVersion,
+		Results: results,
+	}, nil
+}
+
+// BatchUpdate 在一个事务中用一条多行 UPDATE 更新多个 ToDo
+func (t *toDoServiceServer) BatchUpdate(ctx context.Context, in *v1.BatchUpdateRequest) (*v1.BatchUpdateResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	if err := checkBatchSize(len(in.ToDos)); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(in.ToDos))
+	for i, todo := range in.ToDos {
+		if todo != nil {
+			ids[i] = todo.Id
+		}
+	}
+
+	results := make([]*v1.BatchResult, len(in.ToDos))
+	valid, err := validateBatch(in.ToDos, in.BestEffort, results, append(append([]fieldRule{}, updateToDoRules...), toDoRules...))
+	if err != nil {
+		return nil, err
+	}
+	if valid, err = checkDuplicateIDs(ids, valid, in.BestEffort, results, "toDos[%d].id"); err != nil {
+		return nil, err
+	}
+	for _, i := range valid {
+		normalizeRecurrence(in.ToDos[i])
+	}
+
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	valid, existing, err := lockExisting(ctx, tx, ids, valid, in.BestEffort, results)
+	if err != nil {
+		return nil, err
+	}
+	if valid, err = checkReminderChanges(in.ToDos, valid, existing, in.BestEffort, results); err != nil {
+		return nil, err
+	}
+
+	err = execBatch(ctx, valid, ids, in.BestEffort, results, "failed to update ToDo", func(items []int) error {
+		todos := make([]*v1.ToDo, len(items))
+		for n, i := range items {
+			todos[n] = in.ToDos[i]
+		}
+
+		if err := updateToDos(ctx, tx, todos); err != nil {
+			return err
+		}
+		for _, i := range items {
+			results[i] = &v1.BatchResult{Id: ids[i], Status: okStatus}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []historyEntry
+	var reset []interface{}
+	for i, r := range results {
+		if r.Status.Code == int32(codes.OK) {
+			if carryReminder(existing[r.Id], in.ToDos[i]) {
+				reset = append(reset, r.Id)
+			}
+			entries = append(entries, historyEntry{v1.ToDoEvent_UPDATED, existing[r.Id], in.ToDos[i]})
+		}
+	}
+	if err := resetReminders(ctx, tx, reset); err != nil {
+		return nil, dbError(ctx, "failed to update ToDo", err)
+	}
+	if err := recordHistory(ctx, tx, principal(ctx), entries...); err != nil {
+		return nil, dbError(ctx, "failed to insert into ToDoHistory", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit transaction", err)
+	}
+
+	for _, e := range entries {
+		t.hub.Publish(v1.ToDoEvent_UPDATED, e.new)
+	}
+
+	return &v1.BatchUpdateResponse{
+		Api:     apiVersion,
+		Results: results,
+	}, nil
+}
+
+// BatchDelete 在一个事务中用一条 UPDATE 把多个 ToDo 移到回收站
+func (t *toDoServiceServer) BatchDelete(ctx context.Context, in *v1.BatchDeleteRequest) (*v1.BatchDeleteResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	if err := checkBatchSize(len(in.Ids)); err != nil {
+		return nil, err
+	}
+
+	results := make([]*v1.BatchResult, len(in.Ids))
+	var valid []int
+	var violations []*errdetails.BadRequest_FieldViolation
+	for i, id := range in.Ids {
+		if id <= 0 {
+			v := []*errdetails.BadRequest_FieldViolation{{Field: fmt.Sprintf("ids[%d]", i), Description: "must be a positive number"}}
+			violations = append(violations, v...)
+			results[i] = &v1.BatchResult{Id: id, Status: status.Convert(invalidArgument(v)).Proto()}
+			continue
+		}
+		valid = append(valid, i)
+	}
+	if !in.BestEffort && len(violations) > 0 {
+		return nil, invalidArgument(violations)
+	}
+
+	valid, err := checkDuplicateIDs(in.Ids, valid, in.BestEffort, results, "ids[%d]")
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	valid, existing, err := lockExisting(ctx, tx, in.Ids, valid, in.BestEffort, results)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	err = execBatch(ctx, valid, in.Ids, in.BestEffort, results, "failed to delete ToDo", func(items []int) error {
+		args := []interface{}{now}
+		for _, i := range items {
+			args = append(args, in.Ids[i])
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE ToDo SET `DeleteTime`=? WHERE `ID` IN ("+placeholders(len(items), "?")+")", args...); err != nil {
+			return err
+		}
+		for _, i := range items {
+			results[i] = &v1.BatchResult{Id: in.Ids[i], Status: okStatus}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deleteTime, _ := ptypes.TimestampProto(now)
+	var entries []historyEntry
+	for _, r := range results {
+		if r.Status.Code == int32(codes.OK) {
+			old := existing[r.Id]
+			todo := proto.Clone(old).(*v1.ToDo)
+			todo.DeleteTime = deleteTime
+			entries = append(entries, historyEntry{v1.ToDoEvent_DELETED, old, todo})
+		}
+	}
+	if err := recordHistory(ctx, tx, principal(ctx), entries...); err != nil {
+		return nil, dbError(ctx, "failed to insert into ToDoHistory", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit transaction", err)
+	}
+
+	for _, e := range entries {
+		t.hub.Publish(v1.ToDoEvent_DELETED, e.new)
+	}
+
+	return &v1.BatchDeleteResponse{
+		Api:     apiVersion,
+		Results: results,
+	}, nil
+}
+
+// validateBatch 按 rules 校验每个条目，返回通过校验的条目下标
+// 非 bestEffort 模式下任何条目不合法都返回包含所有违规字段的 InvalidArgument
+func validateBatch(todos []*v1.ToDo, bestEffort bool, results []*v1.BatchResult, rules []fieldRule) ([]int, error) {
+	var valid []int
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	for i, todo := range todos {
+		prefix := fmt.Sprintf("toDos[%d]", i)
+
+		var v []*errdetails.BadRequest_FieldViolation
+		if todo == nil {
+			v = append(v, &errdetails.BadRequest_FieldViolation{Field: prefix, Description: "must not be empty"})
+		} else {
+			v = applyRules(v, prefix+".", todo, rules)
+		}
+
+		if len(v) > 0 {
+			violations = append(violations, v...)
+			results[i] = &v1.BatchResult{Status: status.Convert(invalidArgument(v)).Proto()}
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	if !bestEffort && len(violations) > 0 {
+		return nil, invalidArgument(violations)
+	}
+	return valid, nil
+}
+
+// checkDuplicateIDs 拒绝同一个批量请求中重复出现的 ID
+func checkDuplicateIDs(ids []int64, valid []int, bestEffort bool, results []*v1.BatchResult, field string) ([]int, error) {
+	seen := make(map[int64]bool, len(valid))
+	var unique []int
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	for _, i := range valid {
+		if seen[ids[i]] {
+			v := []*errdetails.BadRequest_FieldViolation{{Field: fmt.Sprintf(field, i), Description: fmt.Sprintf("duplicate id %d", ids[i])}}
+			violations = append(violations, v...)
+			results[i] = &v1.BatchResult{Id: ids[i], Status: status.Convert(invalidArgument(v)).Proto()}
+			continue
+		}
+		seen[ids[i]] = true
+		unique = append(unique, i)
+	}
+
+	if !bestEffort && len(violations) > 0 {
+		return nil, invalidArgument(violations)
+	}
+	return unique, nil
+}
+
+// lockExisting 锁定并读取 valid 中存在的 ToDo，返回存在的条目下标
+// 非 bestEffort 模式下任何 ID 不存在都返回 NotFound
+func lockExisting(ctx context.Context, tx *sql.Tx, ids []int64, valid []int, bestEffort bool, results []*v1.BatchResult) ([]int, map[int64]*v1.ToDo, error) {
+	if len(valid) == 0 {
+		return nil, nil, nil
+	}
+
+	args := make([]interface{}, len(valid))
+	for n, i := range valid {
+		args[n] = ids[i]
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT "+toDoColumns+" FROM ToDo WHERE `ID` IN ("+placeholders(len(args), "?")+") AND `DeleteTime` IS NULL FOR UPDATE", args...)
+	if err != nil {
+		return nil, nil, dbError(ctx, "failed to select from ToDo", err)
+	}
+
+	defer rows.Close()
+
+	existing := make(map[int64]*v1.ToDo, len(valid))
+	for rows.Next() {
+		todo, err := scanToDo(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		existing[todo.Id] = todo
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, dbError(ctx, "failed to retrieve data from ToDo", err)
+	}
+
+	var found []int
+	for _, i := range valid {
+		if _, ok := existing[ids[i]]; ok {
+			found = append(found, i)
+			continue
+		}
+
+		err := status.Errorf(codes.NotFound, "ToDo with ID='%d' is not found", ids[i])
+		if !bestEffort {
+			return nil, nil, err
+		}
+		results[i] = &v1.BatchResult{Id: ids[i], Status: status.Convert(err).Proto()}
+	}
+
+	return found, existing, nil
+}
+
+// checkReminderChanges 对 valid 中的条目执行 checkReminderChange，existing 是 lockExisting 读出的修改前的 ToDo
+func checkReminderChanges(todos []*v1.ToDo, valid []int, existing map[int64]*v1.ToDo, bestEffort bool, results []*v1.BatchResult) ([]int, error) {
+	var checked []int
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	for _, i := range valid {
+		if v := checkReminderChange(existing[todos[i].Id], todos[i], fmt.Sprintf("toDos[%d].reminder", i)); v != nil {
+			violations = append(violations, v)
+			results[i] = &v1.BatchResult{Id: todos[i].Id, Status: status.Convert(invalidArgument([]*errdetails.BadRequest_FieldViolation{v})).Proto()}
+			continue
+		}
+		checked = append(checked, i)
+	}
+
+	if !bestEffort && len(violations) > 0 {
+		return nil, invalidArgument(violations)
+	}
+	return checked, nil
+}
+
+// execBatch 对 items 执行一次多行语句 exec，ids 是条目对应的 ID (创建时为 nil)
+// bestEffort 模式下多行语句失败时逐条重试，以便找出失败的条目; 死锁等需要整体重试的错误直接返回
+func execBatch(ctx context.Context, items []int, ids []int64, bestEffort bool, results []*v1.BatchResult, msg string, exec func(items []int) error) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	err := exec(items)
+	if err == nil {
+		return nil
+	}
+	if !bestEffort || dbCode(err, codes.Internal) == codes.Aborted {
+		return dbError(ctx, msg, err)
+	}
+
+	for _, i := range items {
+		if err := exec([]int{i}); err != nil {
+			if dbCode(err, codes.Internal) == codes.Aborted {
+				return dbError(ctx, msg, err)
+			}
+			results[i] = &v1.BatchResult{Status: status.Convert(dbError(ctx, msg, err)).Proto()}
+			if ids != nil {
+				results[i].Id = ids[i]
+			}
+		}
+	}
+	return nil
+}
+
+// insertToDos 在 q 的事务中逐行插入 todos，返回插入的 ID
+// 多行 INSERT 分配的自增 ID 不保证连续 (innodb_autoinc_lock_mode=2)，所以每行单独读取 LastInsertId
+// 所有者是 ctx 中通过认证的调用方; externalIDs 不为 nil 时同时写入每个 ToDo 的外部 ID，空字符串写入 NULL
+func insertToDos(ctx context.Context, q dbtx, todos []*v1.ToDo, externalIDs []string) ([]int64, error) {
+	columns, group := "`Title`, `Description`, `Reminder`, `Recurrence`, `TimeZone`, `Owner`", "(?,?,?,?,?,?)"
+	if externalIDs != nil {
+		columns, group = columns+", `ExternalID`", "(?,?,?,?,?,?,?)"
+	}
+	query := "INSERT INTO ToDo(" + columns + ") VALUES " + group
+
+	ids := make([]int64, len(todos))
+	for i, todo := range todos {
+		reminder, err := ptypes.Timestamp(todo.Reminder)
+		if err != nil {
+			return nil, err
+		}
+		args := []interface{}{todo.Title, todo.Description, reminder, todo.Recurrence, todo.TimeZone, owner(ctx)}
+		if externalIDs != nil {
+			args = append(args, sql.NullString{String: externalIDs[i], Valid: len(externalIDs[i]) > 0})
+		}
+
+		res, err := q.ExecContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		if ids[i], err = res.LastInsertId(); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// updateToDos 用一条多行 UPDATE 更新 todos
+func updateToDos(ctx context.Context, q dbtx, todos []*v1.ToDo) error {
+	var title, description, reminder, recurrence, timeZone, ids []interface{}
+	for _, todo := range todos {
+		r, err := ptypes.Timestamp(todo.Reminder)
+		if err != nil {
+			return err
+		}
+		title = append(title, todo.Id, todo.Title)
+		description = append(description, todo.Id, todo.Description)
+		reminder = append(reminder, todo.Id, r)
+		recurrence = append(recurrence, todo.Id, todo.Recurrence)
+		timeZone = append(timeZone, todo.Id, todo.TimeZone)
+		ids = append(ids, todo.Id)
+	}
+
+	when := strings.Repeat("WHEN ? THEN ? ", len(todos))
+	query := "UPDATE ToDo SET " +
+		"`Title`=CASE `ID` " + when + "END, " +
+		"`Description`=CASE `ID` " + when + "END, " +
+		"`Reminder`=CASE `ID` " + when + "END, " +
+		"`Recurrence`=CASE `ID` " + when + "END, " +
+		"`TimeZone`=CASE `ID` " + when + "END " +
+		"WHERE `ID` IN (" + placeholders(len(todos), "?") + ")"
+
+	args := append(append(append(append(append(title, description...), reminder...), recurrence...), timeZone...), ids...)
+	_, err := q.ExecContext(ctx, query, args...)
+	return err
+}
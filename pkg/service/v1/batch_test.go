@@ -0,0 +1,190 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+func TestBatchCreate(t *testing.T) {
+	ctx := context.Background()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	timeNow := time.Now().In(time.UTC)
+	reminder, _ := ptypes.TimestampProto(timeNow)
+
+	request := &v1.BatchCreateRequest{
+		Api: "v1",
+		ToDos: []*v1.ToDo{
+			{Title: "title 1", Reminder: reminder},
+			{Title: "", Reminder: reminder},
+			{Title: "title 3", Reminder: reminder},
+		},
+	}
+
+	// 非 bestEffort 模式下不合法的条目使整个请求失败，不访问数据库
+	if _, err := toDoServer.BatchCreate(ctx, request); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("toDoServiceServer.BatchCreate() error =%v, want InvalidArgument", err)
+	}
+
+	request.BestEffort = true
+	mock.ExpectBegin()
+	// 自增 ID 不一定连续
+	mock.ExpectExec("INSERT INTO ToDo").WithArgs("title 1", "", timeNow, "", "", nil).WillReturnResult(sqlmock.NewResult(10, 1))
+	mock.ExpectExec("INSERT INTO ToDo").WithArgs("title 3", "", timeNow, "", "", nil).WillReturnResult(sqlmock.NewResult(13, 1))
+	mock.ExpectExec("INSERT INTO ToDoHistory").WillReturnResult(sqlmock.NewResult(1, 2))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	got, err := toDoServer.BatchCreate(ctx, request)
+	if err != nil {
+		t.Fatalf("toDoServiceServer.BatchCreate() error =%v", err)
+	}
+
+	want := []struct {
+		id   int64
+		code codes.Code
+	}{
+		{10, codes.OK},
+		{0, codes.InvalidArgument},
+		{13, codes.OK},
+	}
+	for i, w := range want {
+		r := got.Results[i]
+		if r.Id != w.id || codes.Code(r.Status.Code) != w.code {
+			t.Errorf("result[%d] =%v, want id=%d code=%v", i, r, w.id, w.code)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBatchDeleteNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	timeNow := time.Now().In(time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID` IN \\(\\?,\\?\\) AND `DeleteTime` IS NULL FOR UPDATE").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone"}).AddRow(1, "title", "", timeNow, nil, "PENDING", nil, "", ""))
+	mock.ExpectRollback()
+
+	_, err = toDoServer.BatchDelete(ctx, &v1.BatchDeleteRequest{Api: "v1", Ids: []int64{1, 2}})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("toDoServiceServer.BatchDelete() error =%v, want NotFound", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBatchUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	timeNow := time.Now().UTC().Truncate(time.Second)
+	old := timeNow.Add(-2 * maxReminderPast)
+	reminder, _ := ptypes.TimestampProto(timeNow)
+	oldReminder, _ := ptypes.TimestampProto(old)
+	tooOld, _ := ptypes.TimestampProto(old.Add(-time.Hour))
+	columns := []string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone"}
+	existing := func() *sqlmock.Rows {
+		return sqlmock.NewRows(columns).
+			AddRow(1, "title 1", "", old, nil, "DELIVERED", old, "", "").
+			AddRow(2, "title 2", "", old, nil, "DELIVERED", old, "", "").
+			AddRow(4, "title 4", "", old, nil, "DELIVERED", old, "", "")
+	}
+
+	request := &v1.BatchUpdateRequest{
+		Api: "v1",
+		ToDos: []*v1.ToDo{
+			// reminder 没有改变，可以保留早已过去的时间
+			{Id: 1, Title: "new 1", Reminder: oldReminder},
+			// reminder 改为超出范围的时间
+			{Id: 2, Title: "new 2", Reminder: tooOld},
+			{Id: 3, Title: "new 3", Reminder: reminder},
+			// reminder 改变后重新等待发送
+			{Id: 4, Title: "new 4", Reminder: reminder},
+		},
+	}
+
+	// 非 bestEffort 模式下任何条目失败整个请求都失败
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID` IN \\(\\?,\\?,\\?,\\?\\) AND `DeleteTime` IS NULL FOR UPDATE").
+		WithArgs(1, 2, 3, 4).WillReturnRows(existing())
+	mock.ExpectRollback()
+
+	if _, err := toDoServer.BatchUpdate(ctx, request); status.Code(err) != codes.NotFound {
+		t.Fatalf("toDoServiceServer.BatchUpdate() error =%v, want NotFound", err)
+	}
+
+	request.BestEffort = true
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID` IN \\(\\?,\\?,\\?,\\?\\) AND `DeleteTime` IS NULL FOR UPDATE").
+		WithArgs(1, 2, 3, 4).WillReturnRows(existing())
+	mock.ExpectExec("UPDATE ToDo SET `Title`=CASE `ID` WHEN \\? THEN \\? WHEN \\? THEN \\? END").
+		WithArgs(1, "new 1", 4, "new 4", 1, "", 4, "", 1, old, 4, timeNow, 1, "", 4, "", 1, "", 4, "", 1, 4).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE ToDo SET `ReminderStatus`=\\?, `ReminderDeliveryTime`=NULL").WithArgs("PENDING", 4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO ToDoHistory").WillReturnResult(sqlmock.NewResult(1, 2))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	got, err := toDoServer.BatchUpdate(ctx, request)
+	if err != nil {
+		t.Fatalf("toDoServiceServer.BatchUpdate() error =%v", err)
+	}
+
+	want := []struct {
+		id   int64
+		code codes.Code
+	}{
+		{1, codes.OK},
+		{2, codes.InvalidArgument},
+		{3, codes.NotFound},
+		{4, codes.OK},
+	}
+	for i, w := range want {
+		r := got.Results[i]
+		if r.Id != w.id || codes.Code(r.Status.Code) != w.code {
+			t.Errorf("result[%d] =%v, want id=%d code=%v", i, r, w.id, w.code)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/auth"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/ics"
+)
+
+// CalendarToken 返回 user 的日历订阅令牌，令牌是 secret 对用户名的 HMAC-SHA256，更换 secret 使所有令牌失效
+func CalendarToken(secret, user string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(user))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkCalendarToken 检查日历订阅令牌，没有配置 secret 时日历订阅被禁用
+func (t *toDoServiceServer) checkCalendarToken(user, token string) error {
+	if len(t.calendarSecret) == 0 {
+		return status.Error(codes.FailedPrecondition, "calendar feed is disabled")
+	}
+	if len(user) == 0 || !hmac.Equal([]byte(token), []byte(CalendarToken(t.calendarSecret, user))) {
+		return status.Error(codes.PermissionDenied, "invalid calendar token")
+	}
+	return nil
+}
+
+// GetCalendarToken 返回当前用户的日历订阅令牌，只有通过认证的用户可以获取令牌
+func (t *toDoServiceServer) GetCalendarToken(ctx context.Context, in *v1.GetCalendarTokenRequest) (*v1.GetCalendarTokenResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	if len(t.calendarSecret) == 0 {
+		return nil, status.Error(codes.FailedPrecondition, "calendar feed is disabled")
+	}
+
+	user, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "calendar token requires an authenticated user")
+	}
+	return &v1.GetCalendarTokenResponse{
+		Api:   apiVersion,
+		User:  user,
+		Token: CalendarToken(t.calendarSecret, user),
+	}, nil
+}
+
+// ExportICS 把 user 拥有的、没有被删除的 ToDo 导出为 iCalendar
+// component 为 VTODO (默认) 或 VEVENT
+func (t *toDoServiceServer) ExportICS(ctx context.Context, in *v1.ExportICSRequest) (*v1.ExportICSResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	if err := t.checkCalendarToken(in.User, in.Token); err != nil {
+		return nil, err
+	}
+
+	component := ics.VTodo
+	switch strings.ToUpper(in.Component) {
+	case "", string(ics.VTodo):
+	case string(ics.VEvent):
+		component = ics.VEvent
+	default:
+		return nil, invalidArgument([]*errdetails.BadRequest_FieldViolation{{Field: "component", Description: "must be VTODO or VEVENT"}})
+	}
+
+	conn, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, "SELECT "+toDoColumns+" FROM ToDo WHERE `Owner`=? AND `DeleteTime` IS NULL ORDER BY `ID`", in.User)
+	if err != nil {
+		return nil, dbError(ctx, "failed to select from ToDo", err)
+	}
+	defer rows.Close()
+
+	var todos []*v1.ToDo
+	for rows.Next() {
+		todo, err := scanToDo(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dbError(ctx, "failed to retrieve data from ToDo", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ics.Encode(&buf, todos, component, in.User+" ToDo", time.Now()); err != nil {
+		return nil, status.Error(codes.Internal, "failed to encode calendar: "+err.Error())
+	}
+
+	return &v1.ExportICSResponse{
+		Api:      apiVersion,
+		Calendar: buf.String(),
+	}, nil
+}
+
+// ImportICS 把 iCalendar 中的 VTODO 和 VEVENT 创建为 ToDo，语义与 BatchCreate 相同
+// 已经开始的重复规则从当前时间之后的下一次重复开始提醒
+func (t *toDoServiceServer) ImportICS(ctx context.Context, in *v1.ImportICSRequest) (*v1.ImportICSResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	todos, err := ics.Decode(strings.NewReader(in.Calendar))
+	if err != nil {
+		return nil, invalidArgument([]*errdetails.BadRequest_FieldViolation{{Field: "calendar", Description: err.Error()}})
+	}
+	if len(todos) == 0 {
+		return nil, invalidArgument([]*errdetails.BadRequest_FieldViolation{{Field: "calendar", Description: "must contain at least one VTODO or VEVENT"}})
+	}
+
+	now := time.Now()
+	for _, todo := range todos {
+		if next := nextOccurrence(todo, now); next != nil && todo.Reminder.Seconds < now.Unix() {
+			todo.Reminder = next
+		}
+	}
+
+	resp, err := t.BatchCreate(ctx, &v1.BatchCreateRequest{Api: in.Api, ToDos: todos, BestEffort: in.BestEffort})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.ImportICSResponse{
+		Api:     apiVersion,
+		Results: resp.Results,
+	}, nil
+}
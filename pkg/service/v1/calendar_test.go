@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/auth"
+)
+
+func TestExportICS(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "secret")
+	// 匿名调用方不能获取令牌，否则任何人都可以订阅匿名 ToDo
+	if _, err := toDoServer.GetCalendarToken(context.Background(), &v1.GetCalendarTokenRequest{Api: "v1"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("toDoServiceServer.GetCalendarToken() error =%v, want Unauthenticated", err)
+	}
+
+	ctx := auth.NewContext(context.Background(), "alice")
+
+	token, err := toDoServer.GetCalendarToken(ctx, &v1.GetCalendarTokenRequest{Api: "v1"})
+	if err != nil || token.User != "alice" || token.Token != CalendarToken("secret", "alice") {
+		t.Fatalf("toDoServiceServer.GetCalendarToken() =%v, error =%v", token, err)
+	}
+
+	_, err = toDoServer.ExportICS(context.Background(), &v1.ExportICSRequest{Api: "v1", User: "bob", Token: token.Token})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("toDoServiceServer.ExportICS() error =%v, want PermissionDenied", err)
+	}
+
+	columns := []string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone"}
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `Owner`=\\? AND `DeleteTime` IS NULL ORDER BY `ID`").
+		WithArgs("alice").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "title", "", time.Date(2019, 5, 6, 7, 0, 0, 0, time.UTC), nil, "PENDING", nil, "", ""))
+
+	got, err := toDoServer.ExportICS(context.Background(), &v1.ExportICSRequest{Api: "v1", User: "alice", Token: token.Token, Component: "vevent"})
+	if err != nil {
+		t.Fatalf("toDoServiceServer.ExportICS() error =%v", err)
+	}
+	if !strings.Contains(got.Calendar, "BEGIN:VEVENT\r\nUID:todo-1@") || !strings.Contains(got.Calendar, "DTSTART:20190506T070000Z\r\n") {
+		t.Errorf("toDoServiceServer.ExportICS() =%s", got.Calendar)
+	}
+
+	disabled := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	if _, err := disabled.ExportICS(context.Background(), &v1.ExportICSRequest{Api: "v1", User: "alice", Token: token.Token}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("toDoServiceServer.ExportICS() error =%v, want FailedPrecondition", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestImportICS(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	due := time.Now().UTC().Truncate(time.Second).Add(24 * time.Hour)
+	calendar := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:title\r\nDUE:" + due.Format("20060102T150405") + "Z\r\n" +
+		"BEGIN:VALARM\r\nTRIGGER;RELATED=END:-PT1H\r\nEND:VALARM\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO ToDo").WithArgs("title", "", due.Add(-time.Hour), "", "", "alice").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO ToDoHistory").WillReturnResult(sqlmock.NewResult(1, 1))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	got, err := toDoServer.ImportICS(auth.NewContext(context.Background(), "alice"), &v1.ImportICSRequest{Api: "v1", Calendar: calendar})
+	if err != nil {
+		t.Fatalf("toDoServiceServer.ImportICS() error =%v", err)
+	}
+	if len(got.Results) != 1 || got.Results[0].Id != 1 {
+		t.Errorf("toDoServiceServer.ImportICS() =%v", got)
+	}
+
+	_, err = toDoServer.ImportICS(context.Background(), &v1.ImportICSRequest{Api: "v1", Calendar: "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("toDoServiceServer.ImportICS() error =%v, want InvalidArgument", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"context"
+	"database/sql/driver"
+	"log"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey 是 HTTP gateway 转发请求 ID 使用的 metadata key
+const requestIDKey = "x-request-id"
+
+// MySQL 服务器错误码
+// https://dev.mysql.com/doc/refman/8.0/en/server-error-reference.html
+const (
+	mysqlErrConCount          = 1040
+	mysqlErrServerShutdown    = 1053
+	mysqlErrDupEntry          = 1062
+	mysqlErrLockWaitTimeout   = 1205
+	mysqlErrLockDeadlock      = 1213
+	mysqlErrQueryInterrupted  = 1317
+	mysqlErrRowIsReferenced   = 1451
+	mysqlErrNoReferencedRow   = 1452
+	mysqlErrReadOnlyMode      = 1290
+	mysqlErrExecutionTimedOut = 3024
+)
+
+// requestID 从 gRPC metadata 中读取请求 ID，不存在时返回 "-"
+func requestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(requestIDKey); len(v) > 0 {
+			return v[0]
+		}
+	}
+	return "-"
+}
+
+// dbCode 把数据库驱动返回的错误归类为 gRPC 状态码，便于客户端判断是否可以重试
+// 无法识别的错误返回 fallback
+func dbCode(err error, fallback codes.Code) codes.Code {
+	switch err {
+	case context.DeadlineExceeded:
+		return codes.DeadlineExceeded
+	case context.Canceled:
+		return codes.Canceled
+	case driver.ErrBadConn, mysql.ErrInvalidConn:
+		return codes.Unavailable
+	}
+
+	switch e := err.(type) {
+	case *mysql.MySQLError:
+		switch e.Number {
+		case mysqlErrLockDeadlock, mysqlErrLockWaitTimeout:
+			return codes.Aborted
+		case mysqlErrDupEntry:
+			return codes.AlreadyExists
+		case mysqlErrRowIsReferenced, mysqlErrNoReferencedRow:
+			return codes.FailedPrecondition
+		case mysqlErrConCount, mysqlErrServerShutdown, mysqlErrReadOnlyMode:
+			return codes.Unavailable
+		case mysqlErrExecutionTimedOut:
+			return codes.DeadlineExceeded
+		case mysqlErrQueryInterrupted:
+			return codes.Aborted
+		}
+	case net.Error:
+		if e.Timeout() {
+			return codes.DeadlineExceeded
+		}
+		return codes.Unavailable
+	}
+
+	return fallback
+}
+
+// dbError 把数据库错误的细节只记录到服务器日志，向客户端返回归类后的状态码和不含驱动信息的错误
+func dbError(ctx context.Context, msg string, err error) error {
+	log.Printf("request %s: %s: %v", requestID(ctx), msg, err)
+	return status.Error(dbCode(err, codes.Internal), msg)
+}
+
+// unavailable 用于无法获取数据库连接的情况，客户端可以稍后重试
+func unavailable(ctx context.Context, err error) error {
+	log.Printf("request %s: failed to connect to database: %v", requestID(ctx), err)
+	return status.Error(dbCode(err, codes.Unavailable), "database is unavailable")
+}
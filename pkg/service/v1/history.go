@@ -0,0 +1,326 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/auth"
+)
+
+const (
+	// anonymous 是没有身份信息的调用方
+	anonymous = "anonymous"
+	// systemPrincipal 是后台任务 (例如清理回收站) 的身份
+	systemPrincipal = "system"
+
+	// revisionColumns 是 scanRevision 读取的列
+	revisionColumns = "`ID`, `Action`, `OldValue`, `NewValue`, `Principal`, `ChangeTime`"
+)
+
+// principal 返回 auth 拦截器认证的调用方身份，不信任客户端自己声明的身份
+func principal(ctx context.Context) string {
+	if p, ok := auth.FromContext(ctx); ok {
+		return p
+	}
+	return anonymous
+}
+
+// owner 返回新建 ToDo 的所有者: 通过认证的调用方，匿名调用方创建的 ToDo 没有所有者 (NULL)
+func owner(ctx context.Context) sql.NullString {
+	p, ok := auth.FromContext(ctx)
+	return sql.NullString{String: p, Valid: ok}
+}
+
+// historyEntry 是 ToDo 的一次变更，old 或 new 为 nil 表示变更前或变更后不存在
+type historyEntry struct {
+	action v1.ToDoEvent_Type
+	old    *v1.ToDo
+	new    *v1.ToDo
+}
+
+// recordHistory 把变更写入 ToDoHistory，并把变更事件加入 webhook 发送队列，必须和变更本身在同一个事务中执行
+// 事件和变更一起提交或回滚 (outbox)，进程重启或 hub 的订阅者跟不上时事件也不会丢失
+func recordHistory(ctx context.Context, q dbtx, principal string, entries ...historyEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	eventTime, _ := ptypes.TimestampProto(now)
+	args := make([]interface{}, 0, len(entries)*6)
+	events := make([]*v1.ToDoEvent, 0, len(entries))
+	for _, e := range entries {
+		id := e.new.GetId()
+		todo := e.new
+		if e.new == nil {
+			id = e.old.GetId()
+			todo = &v1.ToDo{Id: id}
+		}
+		events = append(events, &v1.ToDoEvent{Type: e.action, ToDo: todo, Time: eventTime})
+
+		oldValue, err := marshalToDo(e.old)
+		if err != nil {
+			return err
+		}
+		newValue, err := marshalToDo(e.new)
+		if err != nil {
+			return err
+		}
+
+		args = append(args, id, e.action.String(), oldValue, newValue, principal, now)
+	}
+
+	_, err := q.ExecContext(ctx, "INSERT INTO ToDoHistory(`ToDoID`, `Action`, `OldValue`, `NewValue`, `Principal`, `ChangeTime`) VALUES "+
+		placeholders(len(entries), "(?,?,?,?,?,?)"), args...)
+	if err != nil {
+		return err
+	}
+
+	return enqueueWebhooks(ctx, q, events...)
+}
+
+// marshalToDo 把 ToDo 序列化为 JSON 保存，nil 保存为 NULL
+func marshalToDo(todo *v1.ToDo) (sql.NullString, error) {
+	if todo == nil {
+		return sql.NullString{}, nil
+	}
+	s, err := (&jsonpb.Marshaler{}).MarshalToString(todo)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: s, Valid: true}, nil
+}
+
+// unmarshalToDo 是 marshalToDo 的逆操作
+func unmarshalToDo(s sql.NullString) (*v1.ToDo, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	var todo v1.ToDo
+	if err := jsonpb.UnmarshalString(s.String, &todo); err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// scanRevision 从当前行读取 Revision，查询的列必须是 revisionColumns
+func scanRevision(ctx context.Context, rows *sql.Rows) (*v1.Revision, error) {
+	var r v1.Revision
+	var action string
+	var oldValue, newValue sql.NullString
+	var changeTime time.Time
+	if err := rows.Scan(&r.Revision, &action, &oldValue, &newValue, &r.Principal, &changeTime); err != nil {
+		return nil, dbError(ctx, "failed to retrieve field values from ToDoHistory row", err)
+	}
+
+	var err error
+	r.Action = v1.ToDoEvent_Type(v1.ToDoEvent_Type_value[action])
+	if r.OldToDo, err = unmarshalToDo(oldValue); err != nil {
+		return nil, dbError(ctx, "oldValue field has invalid format", err)
+	}
+	if r.NewToDo, err = unmarshalToDo(newValue); err != nil {
+		return nil, dbError(ctx, "newValue field has invalid format", err)
+	}
+	if r.ChangeTime, err = ptypes.TimestampProto(changeTime); err != nil {
+		return nil, dbError(ctx, "changeTime field has invalid format", err)
+	}
+
+	return &r, nil
+}
+
+// ListRevisions 按时间顺序返回 ToDo 的所有变更，包括已经被永久删除的 ToDo
+func (t *toDoServiceServer) ListRevisions(ctx context.Context, in *v1.ListRevisionsRequest) (*v1.ListRevisionsResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	conn, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, "SELECT "+revisionColumns+" FROM ToDoHistory WHERE `ToDoID`=? ORDER BY `ID`", in.Id)
+	if err != nil {
+		return nil, dbError(ctx, "failed to select from ToDoHistory", err)
+	}
+
+	defer rows.Close()
+
+	var list []*v1.Revision
+	for rows.Next() {
+		r, err := scanRevision(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, r)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(ctx, "failed to retrieve data from ToDoHistory", err)
+	}
+
+	if len(list) == 0 {
+		return nil, status.Errorf(codes.NotFound, "ToDo with ID='%d' is not found", in.Id)
+	}
+
+	return &v1.ListRevisionsResponse{
+		Api:       apiVersion,
+		Revisions: list,
+	}, nil
+}
+
+// ReadAt 返回 ToDo 在指定时间的内容
+func (t *toDoServiceServer) ReadAt(ctx context.Context, in *v1.ReadAtRequest) (*v1.ReadAtResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	at, err := ptypes.Timestamp(in.Time)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "time field has invalid format->"+err.Error())
+	}
+
+	conn, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, "SELECT "+revisionColumns+" FROM ToDoHistory WHERE `ToDoID`=? AND `ChangeTime`<=? ORDER BY `ID` DESC LIMIT 1", in.Id, at.UTC())
+	if err != nil {
+		return nil, dbError(ctx, "failed to select from ToDoHistory", err)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return nil, dbError(ctx, "failed to retrieve data from ToDoHistory", err)
+		}
+		return nil, status.Errorf(codes.NotFound, "ToDo with ID='%d' did not exist at %s", in.Id, at.Format(time.RFC3339))
+	}
+
+	r, err := scanRevision(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.NewToDo == nil {
+		return nil, status.Errorf(codes.NotFound, "ToDo with ID='%d' was purged at %s", in.Id, ptypes.TimestampString(r.ChangeTime))
+	}
+
+	return &v1.ReadAtResponse{
+		Api:      apiVersion,
+		ToDo:     r.NewToDo,
+		Revision: r.Revision,
+	}, nil
+}
+
+// Revert 把 ToDo 恢复为指定版本的内容，恢复本身也作为一次更新记录在历史中
+func (t *toDoServiceServer) Revert(ctx context.Context, in *v1.RevertRequest) (*v1.RevertResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT "+revisionColumns+" FROM ToDoHistory WHERE `ID`=? AND `ToDoID`=?", in.Revision, in.Id)
+	if err != nil {
+		return nil, dbError(ctx, "failed to select from ToDoHistory", err)
+	}
+
+	var r *v1.Revision
+	if rows.Next() {
+		r, err = scanRevision(ctx, rows)
+	} else {
+		err = rows.Err()
+		if err == nil {
+			err = status.Errorf(codes.NotFound, "revision %d of ToDo with ID='%d' is not found", in.Revision, in.Id)
+		} else {
+			err = dbError(ctx, "failed to retrieve data from ToDoHistory", err)
+		}
+	}
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.NewToDo == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "revision %d of ToDo with ID='%d' is a purge and cannot be reverted to", in.Revision, in.Id)
+	}
+
+	old, err := lockToDo(ctx, tx, in.Id, true)
+	if status.Code(err) == codes.NotFound {
+		return nil, status.Errorf(codes.FailedPrecondition, "ToDo with ID='%d' was purged and cannot be reverted", in.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	target := proto.Clone(r.NewToDo).(*v1.ToDo)
+	reminder, err := ptypes.Timestamp(target.Reminder)
+	if err != nil {
+		return nil, dbError(ctx, "reminder field has invalid format", err)
+	}
+	var deleteTime interface{}
+	if target.DeleteTime != nil {
+		d, err := ptypes.Timestamp(target.DeleteTime)
+		if err != nil {
+			return nil, dbError(ctx, "deleteTime field has invalid format", err)
+		}
+		deleteTime = d
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE ToDo SET `Title`=?, `Description`=?, `Reminder`=?, `Recurrence`=?, `TimeZone`=?, `DeleteTime`=? WHERE `ID`=?",
+		target.Title, target.Description, reminder, target.Recurrence, target.TimeZone, deleteTime, in.Id)
+	if err != nil {
+		return nil, dbError(ctx, "failed to update ToDo", err)
+	}
+
+	if carryReminder(old, target) {
+		if err := resetReminders(ctx, tx, []interface{}{in.Id}); err != nil {
+			return nil, dbError(ctx, "failed to update ToDo", err)
+		}
+	}
+
+	if err := recordHistory(ctx, tx, principal(ctx), historyEntry{v1.ToDoEvent_UPDATED, old, target}); err != nil {
+		return nil, dbError(ctx, "failed to insert into ToDoHistory", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit transaction", err)
+	}
+
+	t.hub.Publish(v1.ToDoEvent_UPDATED, target)
+
+	return &v1.RevertResponse{
+		Api:  apiVersion,
+		ToDo: target,
+	}, nil
+}
+
+// Creator 返回 ToDo 的所有者，即创建它的通过认证的用户; 匿名创建的 ToDo 返回 anonymous
+func Creator(ctx context.Context, db *sql.DB, id int64) (string, error) {
+	var p sql.NullString
+	err := db.QueryRowContext(ctx, "SELECT `Owner` FROM ToDo WHERE `ID`=?", id).Scan(&p)
+	if err == sql.ErrNoRows || err == nil && !p.Valid {
+		return anonymous, nil
+	}
+	return p.String, err
+}
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/auth"
+)
+
+func TestUpdateRecordsHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	timeNow := time.Now().UTC().Truncate(time.Second)
+	reminder, _ := ptypes.TimestampProto(timeNow)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID`=\\? AND `DeleteTime` IS NULL FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone"}).AddRow(1, "old", "", timeNow, nil, "PENDING", nil, "", ""))
+	mock.ExpectExec("UPDATE ToDo").WithArgs("new", "", timeNow, "", "", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO ToDoHistory").
+		WithArgs(1, "UPDATED", `{"id":"1","title":"old","reminder":"`+timeNow.Format(time.RFC3339)+`"}`, sqlmock.AnyArg(), "alice", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	ctx := auth.NewContext(context.Background(), "alice")
+	_, err = toDoServer.Update(ctx, &v1.UpdateRequest{Api: "v1", ToDo: &v1.ToDo{Id: 1, Title: "new", Reminder: reminder}})
+	if err != nil {
+		t.Fatalf("toDoServiceServer.Update() error =%v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReadAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	timeNow := time.Now().In(time.UTC)
+	at, _ := ptypes.TimestampProto(timeNow)
+	columns := []string{"ID", "Action", "OldValue", "NewValue", "Principal", "ChangeTime"}
+
+	mock.ExpectQuery("SELECT (.+) FROM ToDoHistory WHERE `ToDoID`=\\? AND `ChangeTime`<=\\? ORDER BY `ID` DESC LIMIT 1").WithArgs(1, timeNow).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(5, "UPDATED", `{"id":"1","title":"old"}`, `{"id":"1","title":"new"}`, "alice", timeNow))
+
+	got, err := toDoServer.ReadAt(context.Background(), &v1.ReadAtRequest{Api: "v1", Id: 1, Time: at})
+	if err != nil || got.Revision != 5 || got.ToDo.Title != "new" {
+		t.Fatalf("toDoServiceServer.ReadAt() =%v, error =%v, want revision 5 with title 'new'", got, err)
+	}
+
+	// 指定时间 ToDo 已经被永久删除
+	mock.ExpectQuery("SELECT (.+) FROM ToDoHistory").WithArgs(1, timeNow).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(6, "PURGED", `{"id":"1","title":"new"}`, nil, systemPrincipal, timeNow))
+
+	if _, err := toDoServer.ReadAt(context.Background(), &v1.ReadAtRequest{Api: "v1", Id: 1, Time: at}); status.Code(err) != codes.NotFound {
+		t.Errorf("toDoServiceServer.ReadAt() error =%v, want NotFound", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,124 @@
+package v1
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// DefaultHubHistory 是 Hub 默认保留的历史事件数量，用于客户端重连后续传
+	DefaultHubHistory = 1024
+
+	// subscriberBuffer 是每个订阅者的事件缓冲区大小，缓冲区满时订阅者被断开
+	subscriberBuffer = 64
+)
+
+// ErrSequenceExpired 表示请求续传的序号对应的事件已经不在历史记录中
+var ErrSequenceExpired = errors.New("sequence is no longer available")
+
+// Hub 在进程内把 ToDo 变更事件广播给所有订阅者
+// 每个事件都有一个递增的序号，订阅者可以从上次收到的序号续传
+type Hub struct {
+	mu          sync.Mutex
+	sequence    int64
+	history     []*v1.ToDoEvent
+	size        int
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription 是 Hub 的一个订阅者
+type Subscription struct {
+	// C 按序号顺序接收事件; 订阅者处理过慢时 C 被关闭，需要从最后收到的序号重新订阅
+	C <-chan *v1.ToDoEvent
+
+	c chan *v1.ToDoEvent
+}
+
+// NewHub 创建保留最近 size 个事件的 Hub
+func NewHub(size int) *Hub {
+	if size <= 0 {
+		size = DefaultHubHistory
+	}
+	return &Hub{
+		size:        size,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish 为事件分配序号并广播给所有订阅者
+func (h *Hub) Publish(typ v1.ToDoEvent_Type, todo *v1.ToDo) *v1.ToDoEvent {
+	ev := &v1.ToDoEvent{
+		Type: typ,
+		ToDo: proto.Clone(todo).(*v1.ToDo),
+		Time: ptypes.TimestampNow(),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sequence++
+	ev.Sequence = h.sequence
+
+	h.history = append(h.history, ev)
+	if len(h.history) > h.size {
+		h.history = h.history[len(h.history)-h.size:]
+	}
+
+	for s := range h.subscribers {
+		select {
+		case s.c <- ev:
+		default:
+			// 订阅者跟不上，断开它而不是阻塞其他订阅者
+			delete(h.subscribers, s)
+			close(s.c)
+		}
+	}
+
+	return ev
+}
+
+// Subscribe 订阅序号大于 since 的事件，since 为 0 表示只订阅之后的新事件
+// 返回的 backlog 是历史记录中已有的事件，之后的事件从 Subscription.C 接收
+func (h *Hub) Subscribe(since int64) (*Subscription, []*v1.ToDoEvent, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// 序号比当前还大说明服务器重启过，事件已经丢失
+	if since > h.sequence {
+		return nil, nil, ErrSequenceExpired
+	}
+
+	var backlog []*v1.ToDoEvent
+	if since > 0 && since < h.sequence {
+		if len(h.history) == 0 || h.history[0].Sequence > since+1 {
+			return nil, nil, ErrSequenceExpired
+		}
+		for _, ev := range h.history {
+			if ev.Sequence > since {
+				backlog = append(backlog, ev)
+			}
+		}
+	}
+
+	c := make(chan *v1.ToDoEvent, subscriberBuffer)
+	s := &Subscription{C: c, c: c}
+	h.subscribers[s] = struct{}{}
+
+	return s, backlog, nil
+}
+
+// Unsubscribe 取消订阅
+func (h *Hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[s]; ok {
+		delete(h.subscribers, s)
+		close(s.c)
+	}
+}
@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"testing"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+func TestHubResume(t *testing.T) {
+	hub := NewHub(3)
+	for i := int64(1); i <= 5; i++ {
+		hub.Publish(v1.ToDoEvent_CREATED, &v1.ToDo{Id: i})
+	}
+
+	// 历史记录中只保留序号 3,4,5
+	_, backlog, err := hub.Subscribe(3)
+	if err != nil {
+		t.Fatalf("Hub.Subscribe() error =%v", err)
+	}
+	if len(backlog) != 2 || backlog[0].Sequence != 4 || backlog[1].Sequence != 5 {
+		t.Errorf("Hub.Subscribe() backlog =%v, want sequences 4,5", backlog)
+	}
+
+	if _, _, err := hub.Subscribe(1); err != ErrSequenceExpired {
+		t.Errorf("Hub.Subscribe(1) error =%v, want=%v", err, ErrSequenceExpired)
+	}
+
+	if _, _, err := hub.Subscribe(100); err != ErrSequenceExpired {
+		t.Errorf("Hub.Subscribe(100) error =%v, want=%v", err, ErrSequenceExpired)
+	}
+}
+
+func TestHubBroadcast(t *testing.T) {
+	hub := NewHub(DefaultHubHistory)
+
+	a, _, _ := hub.Subscribe(0)
+	b, _, _ := hub.Subscribe(0)
+
+	hub.Publish(v1.ToDoEvent_UPDATED, &v1.ToDo{Id: 1, Title: "title"})
+
+	for _, s := range []*Subscription{a, b} {
+		ev := <-s.C
+		if ev.Sequence != 1 || ev.Type != v1.ToDoEvent_UPDATED || ev.ToDo.Title != "title" {
+			t.Errorf("received event =%v", ev)
+		}
+	}
+
+	hub.Unsubscribe(a)
+	if _, ok := <-a.C; ok {
+		t.Errorf("channel of unsubscribed subscription is not closed")
+	}
+}
+
+func TestHubSlowSubscriber(t *testing.T) {
+	hub := NewHub(DefaultHubHistory)
+	s, _, _ := hub.Subscribe(0)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.Publish(v1.ToDoEvent_CREATED, &v1.ToDo{})
+	}
+
+	n := 0
+	for range s.C {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Errorf("slow subscriber received %d events, want=%d", n, subscriberBuffer)
+	}
+
+	// 已经被断开的订阅者再次取消订阅不应 panic
+	hub.Unsubscribe(s)
+}
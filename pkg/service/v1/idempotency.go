@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// DefaultIdempotencyWindow 是幂等键默认的有效期
+	DefaultIdempotencyWindow = 24 * time.Hour
+
+	// idempotencyKeyMetadata 是 gateway 转发 Idempotency-Key 头使用的 metadata key
+	idempotencyKeyMetadata = "idempotency-key"
+
+	// maxIdempotencyKeyLength 与 Idempotency 表 Key 列的长度一致
+	maxIdempotencyKeyLength = 128
+
+	// idempotencyCleanInterval 是后台删除过期幂等键的间隔
+	idempotencyCleanInterval = time.Hour
+)
+
+// idempotencyKey 返回 Create 请求的幂等键: 优先使用请求中的 requestId，其次是 Idempotency-Key 头
+func idempotencyKey(ctx context.Context, in *v1.CreateRequest) string {
+	if len(in.RequestId) > 0 {
+		return in.RequestId
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(idempotencyKeyMetadata); len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// validateIdempotencyKey 校验幂等键的长度
+func validateIdempotencyKey(key string) error {
+	if len(key) > maxIdempotencyKeyLength {
+		return invalidArgument([]*errdetails.BadRequest_FieldViolation{{
+			Field:       "requestId",
+			Description: "must not be longer than 128 characters",
+		}})
+	}
+	return nil
+}
+
+// requestHash 计算请求内容的摘要，用于发现同一个幂等键被用于不同的请求
+func requestHash(todo *v1.ToDo) (string, error) {
+	b, err := proto.Marshal(todo)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// createIdempotent 在一个事务中创建 ToDo 并记录幂等键
+// 有效期内的重放返回第一次创建的 ID，相同的幂等键用于不同内容的请求时返回 FailedPrecondition
+func (t *toDoServiceServer) createIdempotent(ctx context.Context, key string, in *v1.CreateRequest) (*v1.CreateResponse, error) {
+	hash, err := requestHash(in.ToDo)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to hash request")
+	}
+
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	var storedHash string
+	var id int64
+	var createTime time.Time
+	err = tx.QueryRowContext(ctx, "SELECT `RequestHash`, `ToDoID`, `CreateTime` FROM Idempotency WHERE `Key`=? FOR UPDATE", key).Scan(&storedHash, &id, &createTime)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return nil, dbError(ctx, "failed to select from Idempotency", err)
+	case time.Since(createTime) < t.idempotencyWindow:
+		if storedHash != hash {
+			return nil, status.Errorf(codes.FailedPrecondition, "idempotency key '%s' was already used with a different request", key)
+		}
+		return &v1.CreateResponse{
+			Api: apiVersion,
+			Id:  id,
+		}, nil
+	}
+
+	ids, err := insertToDos(ctx, tx, []*v1.ToDo{in.ToDo}, nil)
+	if err != nil {
+		return nil, dbError(ctx, "failed to insert into ToDo", err)
+	}
+
+	// 过期的幂等键直接覆盖; 并发使用同一个幂等键时其中一个事务会因死锁失败，返回 Aborted 让客户端重试
+	_, err = tx.ExecContext(ctx, "INSERT INTO Idempotency(`Key`, `RequestHash`, `ToDoID`, `CreateTime`) VALUES (?,?,?,?) "+
+		"ON DUPLICATE KEY UPDATE `RequestHash`=VALUES(`RequestHash`), `ToDoID`=VALUES(`ToDoID`), `CreateTime`=VALUES(`CreateTime`)",
+		key, hash, ids[0], time.Now().UTC())
+	if err != nil {
+		return nil, dbError(ctx, "failed to insert into Idempotency", err)
+	}
+
+	created := *in.ToDo
+	created.Id = ids[0]
+	if err := recordHistory(ctx, tx, principal(ctx), historyEntry{v1.ToDoEvent_CREATED, nil, &created}); err != nil {
+		return nil, dbError(ctx, "failed to insert into ToDoHistory", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit transaction", err)
+	}
+
+	t.hub.Publish(v1.ToDoEvent_CREATED, &created)
+
+	return &v1.CreateResponse{
+		Api: apiVersion,
+		Id:  ids[0],
+	}, nil
+}
+
+// RunIdempotencyCleaner 定期删除超过有效期 window 的幂等键，直到 ctx 被取消
+// 过期的幂等键不会再被使用，不删除的话 Idempotency 表会无限增长
+func RunIdempotencyCleaner(ctx context.Context, db *sql.DB, window time.Duration) {
+	ticker := time.NewTicker(idempotencyCleanInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := cleanIdempotencyKeys(ctx, db, time.Now().UTC().Add(-window)); err != nil {
+			log.Printf("failed to delete expired idempotency keys: %v", err)
+		} else if n > 0 {
+			log.Printf("deleted %d expired idempotency keys", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanIdempotencyKeys 分批删除 before 之前创建的幂等键，返回删除的数量
+func cleanIdempotencyKeys(ctx context.Context, db *sql.DB, before time.Time) (int64, error) {
+	var total int64
+	for {
+		res, err := db.ExecContext(ctx, "DELETE FROM Idempotency WHERE `CreateTime` < ? LIMIT ?", before, purgeBatchSize)
+		if err != nil {
+			return total, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+
+		total += n
+		if n < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
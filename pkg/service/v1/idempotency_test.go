@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+func TestCreateIdempotent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	timeNow := time.Now().In(time.UTC)
+	reminder, _ := ptypes.TimestampProto(timeNow)
+
+	todo := &v1.ToDo{Title: "title", Description: "description", Reminder: reminder}
+	hash, _ := requestHash(todo)
+	columns := []string{"RequestHash", "ToDoID", "CreateTime"}
+
+	// 第一次使用幂等键: 创建 ToDo 并记录幂等键
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM Idempotency").WithArgs("key-1").WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectExec("INSERT INTO ToDo").WithArgs("title", "description", timeNow, "", "", nil).WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO Idempotency").WithArgs("key-1", hash, 7, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO ToDoHistory").WithArgs(7, "CREATED", nil, sqlmock.AnyArg(), anonymous, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	got, err := toDoServer.Create(context.Background(), &v1.CreateRequest{Api: "v1", ToDo: todo, RequestId: "key-1"})
+	if err != nil || got.Id != 7 {
+		t.Fatalf("toDoServiceServer.Create() =%v, error =%v, want id 7", got, err)
+	}
+
+	// 通过 Idempotency-Key 头重放: 返回第一次创建的 ID
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM Idempotency").WithArgs("key-1").WillReturnRows(sqlmock.NewRows(columns).AddRow(hash, 7, timeNow))
+	mock.ExpectRollback()
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(idempotencyKeyMetadata, "key-1"))
+	got, err = toDoServer.Create(ctx, &v1.CreateRequest{Api: "v1", ToDo: todo})
+	if err != nil || got.Id != 7 {
+		t.Fatalf("toDoServiceServer.Create() replay =%v, error =%v, want id 7", got, err)
+	}
+
+	// 同一个幂等键用于不同的请求
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM Idempotency").WithArgs("key-1").WillReturnRows(sqlmock.NewRows(columns).AddRow(hash, 7, timeNow))
+	mock.ExpectRollback()
+
+	other := &v1.ToDo{Title: "other", Reminder: reminder}
+	_, err = toDoServer.Create(context.Background(), &v1.CreateRequest{Api: "v1", ToDo: other, RequestId: "key-1"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("toDoServiceServer.Create() error =%v, want FailedPrecondition", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCleanIdempotencyKeys(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	before := time.Now().UTC()
+
+	// 删除满一批时继续删除下一批
+	mock.ExpectExec("DELETE FROM Idempotency WHERE `CreateTime` < \\? LIMIT \\?").WithArgs(before, purgeBatchSize).WillReturnResult(sqlmock.NewResult(0, purgeBatchSize))
+	mock.ExpectExec("DELETE FROM Idempotency WHERE `CreateTime` < \\? LIMIT \\?").WithArgs(before, purgeBatchSize).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	n, err := cleanIdempotencyKeys(context.Background(), db, before)
+	if err != nil || n != purgeBatchSize+3 {
+		t.Fatalf("cleanIdempotencyKeys() =%d, error =%v, want %d", n, err, purgeBatchSize+3)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,206 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/recurrence"
+)
+
+const (
+	// defaultOccurrences 是 ListOccurrences 默认返回的重复次数
+	defaultOccurrences = 10
+	// maxOccurrences 是 ListOccurrences 最多返回的重复次数
+	maxOccurrences = 100
+	// occurrenceWindowYears 是 ListOccurrences 从开始时间向后查找重复的年数，很少匹配的规则不会展开过多的周期
+	occurrenceWindowYears = 10
+)
+
+// location 返回 todo 的时区，timeZone 为空时是 UTC
+func location(todo *v1.ToDo) (*time.Location, error) {
+	if todo.TimeZone == "Local" {
+		return nil, fmt.Errorf("unknown time zone %s", todo.TimeZone)
+	}
+	return time.LoadLocation(todo.TimeZone)
+}
+
+// parseRecurrence 解析 todo 的重复规则，规则中没有 DTSTART 时以 reminder 作为第一次重复的时间
+// 重复按 todo 时区的墙上时间展开，夏令时切换前后提醒的本地时间不变
+func parseRecurrence(todo *v1.ToDo) (*recurrence.Rule, error) {
+	start, err := ptypes.Timestamp(todo.Reminder)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := location(todo)
+	if err != nil {
+		return nil, err
+	}
+	return recurrence.Parse(todo.Recurrence, start.In(loc))
+}
+
+// normalizeRecurrence 把 todo 的重复规则保存为包含 DTSTART 的规范文本，之后 reminder 前进时第一次重复的时间不变
+// 规则中的时间保存为 timeZone 的墙上时间，不带时区: 修改 timeZone 后规则按新时区的墙上时间展开
+// 带 TZID 或 UTC 的 DTSTART/EXDATE 先换算到 timeZone; 调用前 todo 必须已经通过校验
+func normalizeRecurrence(todo *v1.ToDo) {
+	if len(todo.Recurrence) == 0 {
+		return
+	}
+	r, err := parseRecurrence(todo)
+	if err != nil {
+		return
+	}
+	loc, _ := location(todo)
+	r.Start = r.Start.In(loc)
+	todo.Recurrence = r.LocalString()
+}
+
+// nextOccurrence 返回重复的 todo 在 after 之后的下一次重复，不是重复的 todo 或没有更多重复时返回 nil
+func nextOccurrence(todo *v1.ToDo, after time.Time) *timestamp.Timestamp {
+	if len(todo.Recurrence) == 0 {
+		return nil
+	}
+	r, err := parseRecurrence(todo)
+	if err != nil {
+		return nil
+	}
+	next, ok := r.After(after)
+	if !ok {
+		return nil
+	}
+	ts, err := ptypes.TimestampProto(next)
+	if err != nil {
+		return nil
+	}
+	return ts
+}
+
+// Complete 完成 ToDo 的当前一次重复: 重复的 ToDo 的 reminder 前进到下一次重复，
+// 不是重复的 ToDo 或已经没有更多重复时 ToDo 被移到回收站
+func (t *toDoServiceServer) Complete(ctx context.Context, in *v1.CompleteRequest) (*v1.CompleteResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	old, err := lockToDo(ctx, tx, in.Id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	reminder, err := ptypes.Timestamp(old.Reminder)
+	if err != nil {
+		return nil, dbError(ctx, "reminder field has invalid format", err)
+	}
+
+	todo := proto.Clone(old).(*v1.ToDo)
+	action := v1.ToDoEvent_UPDATED
+	if next := nextOccurrence(old, reminder); next != nil {
+		todo.Reminder, todo.ReminderStatus, todo.ReminderDeliveryTime = next, v1.ToDo_PENDING, nil
+		nextTime, _ := ptypes.Timestamp(next)
+		// 下一次重复重新等待发送，清除当前一次重复的发送结果和重试状态
+		_, err = tx.ExecContext(ctx, "UPDATE ToDo SET `Reminder`=?, `ReminderStatus`=?, `ReminderDeliveryTime`=NULL, `ReminderAttempts`=0, `ReminderNextAttemptTime`=NULL WHERE `ID`=?",
+			nextTime, v1.ToDo_PENDING.String(), in.Id)
+	} else {
+		action = v1.ToDoEvent_DELETED
+		now := time.Now().UTC()
+		todo.DeleteTime, _ = ptypes.TimestampProto(now)
+		_, err = tx.ExecContext(ctx, "UPDATE ToDo SET `DeleteTime`=? WHERE `ID`=?", now, in.Id)
+	}
+	if err != nil {
+		return nil, dbError(ctx, "failed to complete ToDo", err)
+	}
+
+	if err := recordHistory(ctx, tx, principal(ctx), historyEntry{action, old, todo}); err != nil {
+		return nil, dbError(ctx, "failed to insert into ToDoHistory", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit transaction", err)
+	}
+
+	t.hub.Publish(action, todo)
+
+	return &v1.CompleteResponse{
+		Api:  apiVersion,
+		ToDo: todo,
+	}, nil
+}
+
+// ListOccurrences 预览重复规则接下来的 count 次重复，只返回开始时间之后 occurrenceWindowYears 年内的重复
+// id 不为 0 时使用 ToDo 的规则，从当前的 reminder (包括) 开始; 否则使用请求中的 recurrence 和 timeZone，从 start (默认为当前时间) 开始
+func (t *toDoServiceServer) ListOccurrences(ctx context.Context, in *v1.ListOccurrencesRequest) (*v1.ListOccurrencesResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	count := int(in.Count)
+	if count <= 0 {
+		count = defaultOccurrences
+	}
+	if count > maxOccurrences {
+		count = maxOccurrences
+	}
+
+	todo := &v1.ToDo{Recurrence: in.Recurrence, Reminder: in.Start, TimeZone: in.TimeZone}
+	if in.Id != 0 {
+		conn, err := t.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		defer conn.Close()
+
+		if todo, err = queryToDo(ctx, conn, in.Id, false); err != nil {
+			return nil, err
+		}
+		if len(todo.Recurrence) == 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "ToDo with ID='%d' is not recurring", in.Id)
+		}
+	} else if todo.Reminder == nil {
+		todo.Reminder = ptypes.TimestampNow()
+	}
+
+	field := func(name, desc string) error {
+		return invalidArgument([]*errdetails.BadRequest_FieldViolation{{Field: name, Description: desc}})
+	}
+	if desc := validTimeZone(todo); len(desc) > 0 {
+		return nil, field("timeZone", desc)
+	}
+	if len(todo.Recurrence) == 0 {
+		return nil, field("recurrence", "must not be empty")
+	}
+	r, err := parseRecurrence(todo)
+	if err != nil {
+		return nil, field("recurrence", "invalid rule: "+err.Error())
+	}
+
+	from, _ := ptypes.Timestamp(todo.Reminder)
+	var list []*timestamp.Timestamp
+	for _, o := range r.Between(from.Add(-time.Nanosecond), from.AddDate(occurrenceWindowYears, 0, 0), count) {
+		ts, err := ptypes.TimestampProto(o)
+		if err != nil {
+			break
+		}
+		list = append(list, ts)
+	}
+
+	return &v1.ListOccurrencesResponse{
+		Api:         apiVersion,
+		Occurrences: list,
+	}, nil
+}
@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+func TestComplete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	reminder := time.Date(2019, 5, 6, 9, 0, 0, 0, time.UTC)
+	columns := []string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone"}
+
+	// 重复的 ToDo 前进到下一次重复
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID`=\\? AND `DeleteTime` IS NULL FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "chores", "", reminder, nil, "DELIVERED", reminder, "DTSTART:20190506T090000Z\nRRULE:FREQ=WEEKLY", ""))
+	mock.ExpectExec("UPDATE ToDo SET `Reminder`=\\?, `ReminderStatus`=\\?, `ReminderDeliveryTime`=NULL, `ReminderAttempts`=0, `ReminderNextAttemptTime`=NULL WHERE `ID`=\\?").
+		WithArgs(reminder.AddDate(0, 0, 7), "PENDING", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO ToDoHistory").WithArgs(1, "UPDATED", sqlmock.AnyArg(), sqlmock.AnyArg(), anonymous, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	got, err := toDoServer.Complete(context.Background(), &v1.CompleteRequest{Api: "v1", Id: 1})
+	if err != nil {
+		t.Fatalf("toDoServiceServer.Complete() error =%v", err)
+	}
+	if next, _ := ptypes.Timestamp(got.ToDo.Reminder); !next.Equal(reminder.AddDate(0, 0, 7)) || got.ToDo.ReminderStatus != v1.ToDo_PENDING {
+		t.Errorf("toDoServiceServer.Complete() =%v, want reminder one week later", got.ToDo)
+	}
+
+	// 不是重复的 ToDo 被移到回收站
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID`=\\? AND `DeleteTime` IS NULL FOR UPDATE").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "once", "", reminder, nil, "PENDING", nil, "", ""))
+	mock.ExpectExec("UPDATE ToDo SET `DeleteTime`=\\? WHERE `ID`=\\?").WithArgs(sqlmock.AnyArg(), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO ToDoHistory").WithArgs(2, "DELETED", sqlmock.AnyArg(), sqlmock.AnyArg(), anonymous, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	got, err = toDoServer.Complete(context.Background(), &v1.CompleteRequest{Api: "v1", Id: 2})
+	if err != nil || got.ToDo.DeleteTime == nil {
+		t.Fatalf("toDoServiceServer.Complete() =%v, error =%v, want deleted", got, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestNormalizeRecurrenceTimeZone(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("time zone database is not available: %v", err)
+	}
+
+	// 09:00 Europe/Berlin
+	reminder, _ := ptypes.TimestampProto(time.Date(2019, 5, 6, 7, 0, 0, 0, time.UTC))
+	todo := &v1.ToDo{Reminder: reminder, TimeZone: "Europe/Berlin", Recurrence: "RRULE:FREQ=DAILY"}
+	normalizeRecurrence(todo)
+	if want := "DTSTART:20190506T090000\nRRULE:FREQ=DAILY"; todo.Recurrence != want {
+		t.Fatalf("normalizeRecurrence() =%q, want %q", todo.Recurrence, want)
+	}
+
+	// 修改时区后按新时区的墙上时间展开
+	todo.TimeZone = "America/New_York"
+	normalizeRecurrence(todo)
+	after := time.Date(2019, 5, 6, 12, 0, 0, 0, time.UTC)
+	if next := nextOccurrence(todo, after); ptypes.TimestampString(next) != "2019-05-06T13:00:00Z" {
+		t.Errorf("nextOccurrence() =%v, want 09:00 America/New_York", next)
+	}
+
+	// 带 TZID 的 DTSTART 换算到 timeZone
+	todo = &v1.ToDo{Reminder: reminder, TimeZone: "America/New_York", Recurrence: "DTSTART;TZID=Europe/Berlin:20190506T090000\nRRULE:FREQ=DAILY"}
+	normalizeRecurrence(todo)
+	if want := "DTSTART:20190506T030000\nRRULE:FREQ=DAILY"; todo.Recurrence != want {
+		t.Errorf("normalizeRecurrence() =%q, want %q", todo.Recurrence, want)
+	}
+}
+
+func TestListOccurrences(t *testing.T) {
+	toDoServer := NewToDoServiceServer(nil, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	start, _ := ptypes.TimestampProto(time.Date(2019, 5, 1, 8, 0, 0, 0, time.UTC))
+
+	got, err := toDoServer.ListOccurrences(context.Background(), &v1.ListOccurrencesRequest{
+		Api:        "v1",
+		Recurrence: "RRULE:FREQ=MONTHLY;BYMONTHDAY=1,15",
+		Start:      start,
+		Count:      3,
+	})
+	if err != nil {
+		t.Fatalf("toDoServiceServer.ListOccurrences() error =%v", err)
+	}
+
+	want := []string{"2019-05-01T08:00:00Z", "2019-05-15T08:00:00Z", "2019-06-01T08:00:00Z"}
+	if len(got.Occurrences) != len(want) {
+		t.Fatalf("toDoServiceServer.ListOccurrences() =%v, want %v", got.Occurrences, want)
+	}
+	for i, o := range got.Occurrences {
+		if s := ptypes.TimestampString(o); s != want[i] {
+			t.Errorf("occurrences[%d] =%s, want %s", i, s, want[i])
+		}
+	}
+
+	// 按 timeZone 的墙上时间展开，夏令时在 2019-03-31 开始
+	start, _ = ptypes.TimestampProto(time.Date(2019, 3, 30, 8, 0, 0, 0, time.UTC))
+	got, err = toDoServer.ListOccurrences(context.Background(), &v1.ListOccurrencesRequest{
+		Api:        "v1",
+		Recurrence: "RRULE:FREQ=DAILY",
+		Start:      start,
+		Count:      2,
+		TimeZone:   "Europe/Berlin",
+	})
+	if err != nil {
+		t.Fatalf("toDoServiceServer.ListOccurrences() error =%v", err)
+	}
+	if len(got.Occurrences) != 2 || ptypes.TimestampString(got.Occurrences[1]) != "2019-03-31T07:00:00Z" {
+		t.Errorf("toDoServiceServer.ListOccurrences() =%v, want 09:00 Europe/Berlin", got.Occurrences)
+	}
+
+	// 只返回 occurrenceWindowYears 年内的重复
+	got, err = toDoServer.ListOccurrences(context.Background(), &v1.ListOccurrencesRequest{
+		Api:        "v1",
+		Recurrence: "RRULE:FREQ=YEARLY;INTERVAL=4",
+		Start:      start,
+		Count:      5,
+	})
+	if err != nil || len(got.Occurrences) != 3 {
+		t.Errorf("toDoServiceServer.ListOccurrences() =%v, error =%v, want 3 occurrences in %d years", got, err, occurrenceWindowYears)
+	}
+
+	for _, in := range []*v1.ListOccurrencesRequest{
+		{Api: "v1", Recurrence: "RRULE:FREQ=SOMETIMES"},
+		{Api: "v1", Recurrence: "RRULE:FREQ=DAILY", TimeZone: "Mars/Olympus_Mons"},
+	} {
+		_, err = toDoServer.ListOccurrences(context.Background(), in)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("toDoServiceServer.ListOccurrences(%v) error =%v, want InvalidArgument", in, err)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/notify"
+)
+
+const (
+	// reminderInterval 是检查到期提醒的间隔
+	reminderInterval = 10 * time.Second
+	// reminderBatchSize 是一次认领的最多提醒数
+	reminderBatchSize = 100
+	// reminderLease 是认领的提醒等待发送结果的时间，认领后进程退出的提醒在租约过期后被重新发送
+	reminderLease = 5 * time.Minute
+
+	// maxReminderAttempts 是放弃重试之前的最大尝试次数，之后提醒保持 FAILED
+	maxReminderAttempts = 5
+	// reminderBaseBackoff 是第一次重试前的等待时间，之后每次翻倍
+	reminderBaseBackoff = time.Minute
+	// reminderMaxBackoff 是两次重试之间的最长等待时间
+	reminderMaxBackoff = time.Hour
+)
+
+// RunReminders 定期把到期的提醒发送给 notifier，直到 ctx 被取消
+// 多个副本可以同时运行: 到期的 ToDo 用 FOR UPDATE SKIP LOCKED 锁定并认领，每个提醒只会被一个副本处理 (需要 MySQL 8.0)
+func RunReminders(ctx context.Context, db *sql.DB, hub *Hub, notifier notify.Notifier) {
+	ticker := time.NewTicker(reminderInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := deliverReminders(ctx, db, hub, notifier, time.Now().UTC()); err != nil {
+			log.Printf("failed to deliver reminders: %v", err)
+		} else if n > 0 {
+			log.Printf("delivered %d reminders", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// claimedReminder 是一个已认领、等待发送的提醒
+type claimedReminder struct {
+	todo     *v1.ToDo
+	attempts int32
+	err      error
+}
+
+// deliverReminders 分批发送 now 之前到期的提醒，返回处理的数量
+// 每批提醒先在一个短事务中认领，提交后再调用 notifier，最后在另一个事务中记录结果，发送期间不持有行锁
+// 发送失败的提醒按指数退避重试; 认领后进程退出时提醒在租约过期后重新发送，所以 notifier 可能收到重复的提醒
+func deliverReminders(ctx context.Context, db *sql.DB, hub *Hub, notifier notify.Notifier, now time.Time) (int, error) {
+	lease := now.Add(reminderLease).Truncate(time.Second)
+
+	var total int
+	for {
+		claimed, err := claimReminders(ctx, db, now, lease)
+		if err != nil {
+			return total, err
+		}
+
+		for _, c := range claimed {
+			c.err = notifier.Notify(ctx, c.todo)
+			// 停止时不记录结果，租约过期后由下一次检查重新发送
+			if ctx.Err() != nil {
+				return total, ctx.Err()
+			}
+			if c.err != nil {
+				log.Printf("failed to deliver reminder of ToDo ID='%d': %v", c.todo.Id, c.err)
+			}
+		}
+
+		todos, err := recordReminders(ctx, db, claimed, now, lease)
+		if err != nil {
+			return total, err
+		}
+
+		total += len(claimed)
+		for _, todo := range todos {
+			hub.Publish(v1.ToDoEvent_UPDATED, todo)
+		}
+
+		if len(claimed) < reminderBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// claimReminders 在一个事务中锁定最多 reminderBatchSize 个到期的提醒，把下一次尝试的时间设置为 lease 后提交
+// 等待发送和可以重试的失败提醒都会被认领
+func claimReminders(ctx context.Context, db *sql.DB, now, lease time.Time) ([]*claimedReminder, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT "+toDoColumns+", `ReminderAttempts` FROM ToDo "+
+		"WHERE `ReminderStatus` IN (?,?) AND `Reminder`<=? AND `ReminderAttempts`<? AND (`ReminderNextAttemptTime` IS NULL OR `ReminderNextAttemptTime`<=?) AND `DeleteTime` IS NULL "+
+		"ORDER BY `Reminder` LIMIT ? FOR UPDATE SKIP LOCKED",
+		v1.ToDo_PENDING.String(), v1.ToDo_FAILED.String(), now, maxReminderAttempts, now, reminderBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var claimed []*claimedReminder
+	for rows.Next() {
+		c := &claimedReminder{}
+		if c.todo, err = scanToDo(ctx, rows, &c.attempts); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		claimed = append(claimed, c)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	args := []interface{}{lease}
+	for _, c := range claimed {
+		args = append(args, c.todo.Id)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE ToDo SET `ReminderNextAttemptTime`=? WHERE `ID` IN ("+placeholders(len(claimed), "?")+")", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, tx.Commit()
+}
+
+// recordReminders 在一个事务中记录发送结果并把事件加入 webhook 发送队列，返回被更新的 ToDo
+// 重复的 ToDo 发送成功或用完重试次数后前进到下一次重复，和 Complete 一样重新等待发送
+// 只更新仍然持有租约的提醒: 发送期间 reminder 被修改 (租约被清除) 或租约过期后被其它副本重新认领的不再更新
+func recordReminders(ctx context.Context, db *sql.DB, claimed []*claimedReminder, now, lease time.Time) ([]*v1.ToDo, error) {
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	deliveryTime, _ := ptypes.TimestampProto(now)
+	var todos []*v1.ToDo
+	var events []*v1.ToDoEvent
+	for _, c := range claimed {
+		attempts := c.attempts + 1
+		s, next := v1.ToDo_DELIVERED, mysql.NullTime{}
+		if c.err != nil {
+			s = v1.ToDo_FAILED
+			if attempts < maxReminderAttempts {
+				next = mysql.NullTime{Time: now.Add(backoff(attempts, reminderBaseBackoff, reminderMaxBackoff)), Valid: true}
+			}
+		}
+
+		fired := proto.Clone(c.todo).(*v1.ToDo)
+		fired.ReminderStatus, fired.ReminderDeliveryTime = s, deliveryTime
+		todo := fired
+
+		var res sql.Result
+		reminder, _ := ptypes.Timestamp(c.todo.Reminder)
+		if occurrence := nextOccurrence(c.todo, reminder); !next.Valid && occurrence != nil {
+			// 不再重试时前进到下一次重复，清除当前一次重复的发送结果和重试状态
+			todo = proto.Clone(c.todo).(*v1.ToDo)
+			todo.Reminder, todo.ReminderStatus, todo.ReminderDeliveryTime = occurrence, v1.ToDo_PENDING, nil
+			nextTime, _ := ptypes.Timestamp(occurrence)
+			res, err = tx.ExecContext(ctx, "UPDATE ToDo SET `Reminder`=?, `ReminderStatus`=?, `ReminderDeliveryTime`=NULL, `ReminderAttempts`=0, `ReminderNextAttemptTime`=NULL "+
+				"WHERE `ID`=? AND `ReminderNextAttemptTime`=?", nextTime, v1.ToDo_PENDING.String(), c.todo.Id, lease)
+		} else {
+			res, err = tx.ExecContext(ctx, "UPDATE ToDo SET `ReminderStatus`=?, `ReminderDeliveryTime`=?, `ReminderAttempts`=?, `ReminderNextAttemptTime`=? "+
+				"WHERE `ID`=? AND `ReminderNextAttemptTime`=?", s.String(), now, attempts, next, c.todo.Id, lease)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return nil, err
+		} else if n == 0 {
+			continue
+		}
+
+		todos = append(todos, todo)
+
+		// 提醒第一次到期时在同一个事务中加入 REMINDER 事件，重试时不重复发送; REMINDER 事件带着这一次重复的提醒
+		events = append(events, &v1.ToDoEvent{Type: v1.ToDoEvent_UPDATED, ToDo: todo, Time: deliveryTime})
+		if c.attempts == 0 {
+			events = append(events, &v1.ToDoEvent{Type: v1.ToDoEvent_REMINDER, ToDo: fired, Time: deliveryTime})
+		}
+	}
+	if err := enqueueWebhooks(ctx, tx, events...); err != nil {
+		return nil, err
+	}
+
+	return todos, tx.Commit()
+}
+
+// carryReminder 把 old 的提醒发送状态带到 new; 提醒时间改变时 new 重新等待发送，返回 true
+func carryReminder(old, new *v1.ToDo) bool {
+	if proto.Equal(old.Reminder, new.Reminder) {
+		new.ReminderStatus, new.ReminderDeliveryTime = old.ReminderStatus, old.ReminderDeliveryTime
+		return false
+	}
+	new.ReminderStatus, new.ReminderDeliveryTime = v1.ToDo_PENDING, nil
+	return true
+}
+
+// resetReminders 把提醒时间改变的 ToDo 重新设置为等待发送，清除重试次数和正在发送的提醒的租约
+func resetReminders(ctx context.Context, q dbtx, ids []interface{}) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	args := append([]interface{}{v1.ToDo_PENDING.String()}, ids...)
+	_, err := q.ExecContext(ctx, "UPDATE ToDo SET `ReminderStatus`=?, `ReminderDeliveryTime`=NULL, `ReminderAttempts`=0, `ReminderNextAttemptTime`=NULL "+
+		"WHERE `ID` IN ("+placeholders(len(ids), "?")+")", args...)
+	return err
+}
@@ -0,0 +1,184 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+// notifierFunc 把函数适配为 notify.Notifier
+type notifierFunc func(ctx context.Context, todo *v1.ToDo) error
+
+func (f notifierFunc) Notify(ctx context.Context, todo *v1.ToDo) error {
+	return f(ctx, todo)
+}
+
+func TestDeliverReminders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	now := time.Now().UTC()
+	lease := now.Add(reminderLease).Truncate(time.Second)
+	columns := []string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone", "ReminderAttempts"}
+
+	// 认领后提交，发送时不持有行锁
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ReminderStatus` IN \\(\\?,\\?\\) AND `Reminder`<=\\? AND `ReminderAttempts`<\\? AND \\(`ReminderNextAttemptTime` IS NULL OR `ReminderNextAttemptTime`<=\\?\\) AND `DeleteTime` IS NULL ORDER BY `Reminder` LIMIT \\? FOR UPDATE SKIP LOCKED").
+		WithArgs("PENDING", "FAILED", now, maxReminderAttempts, now, reminderBatchSize).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "title 1", "", now, nil, "PENDING", nil, "", "", 0).
+			AddRow(2, "title 2", "", now, nil, "PENDING", nil, "", "", 0).
+			AddRow(3, "title 3", "", now, nil, "FAILED", now, "", "", maxReminderAttempts-1).
+			AddRow(4, "title 4", "", now, nil, "PENDING", nil, "", "", 0).
+			AddRow(5, "weekly", "", now.Truncate(time.Second), nil, "PENDING", nil, "RRULE:FREQ=WEEKLY", "", 0).
+			AddRow(6, "daily", "", now, nil, "PENDING", nil, "RRULE:FREQ=DAILY", "", 0).
+			AddRow(7, "daily", "", now.Truncate(time.Second), nil, "FAILED", now, "RRULE:FREQ=DAILY", "", maxReminderAttempts-1))
+	mock.ExpectExec("UPDATE ToDo SET `ReminderNextAttemptTime`=\\? WHERE `ID` IN \\(\\?,\\?,\\?,\\?,\\?,\\?,\\?\\)").WithArgs(lease, 1, 2, 3, 4, 5, 6, 7).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+	mock.ExpectCommit()
+
+	record := "UPDATE ToDo SET `ReminderStatus`=\\?, `ReminderDeliveryTime`=\\?, `ReminderAttempts`=\\?, `ReminderNextAttemptTime`=\\? WHERE `ID`=\\? AND `ReminderNextAttemptTime`=\\?"
+	mock.ExpectBegin()
+	mock.ExpectExec(record).WithArgs("DELIVERED", now, 1, mysql.NullTime{}, 1, lease).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// 第一次失败后等待 reminderBaseBackoff 重试
+	mock.ExpectExec(record).WithArgs("FAILED", now, 1, mysql.NullTime{Time: now.Add(reminderBaseBackoff), Valid: true}, 2, lease).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// 达到最大尝试次数后不再重试
+	mock.ExpectExec(record).WithArgs("FAILED", now, maxReminderAttempts, mysql.NullTime{}, 3, lease).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// 发送期间提醒被修改，租约已被清除
+	mock.ExpectExec(record).WithArgs("DELIVERED", now, 1, mysql.NullTime{}, 4, lease).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// 重复的 ToDo 发送成功后前进到下一次重复，重新等待发送
+	mock.ExpectExec("UPDATE ToDo SET `Reminder`=\\?, `ReminderStatus`=\\?, `ReminderDeliveryTime`=NULL, `ReminderAttempts`=0, `ReminderNextAttemptTime`=NULL WHERE `ID`=\\? AND `ReminderNextAttemptTime`=\\?").
+		WithArgs(now.Truncate(time.Second).AddDate(0, 0, 7), "PENDING", 5, lease).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// 还会重试的重复的 ToDo 不前进
+	mock.ExpectExec(record).WithArgs("FAILED", now, 1, mysql.NullTime{Time: now.Add(reminderBaseBackoff), Valid: true}, 6, lease).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// 用完重试次数后也前进到下一次重复
+	mock.ExpectExec("UPDATE ToDo SET `Reminder`=\\?, `ReminderStatus`=\\?, `ReminderDeliveryTime`=NULL, `ReminderAttempts`=0, `ReminderNextAttemptTime`=NULL WHERE `ID`=\\? AND `ReminderNextAttemptTime`=\\?").
+		WithArgs(now.Truncate(time.Second).AddDate(0, 0, 1), "PENDING", 7, lease).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// REMINDER 事件只在第一次尝试时和结果一起加入 webhook 发送队列
+	mock.ExpectQuery("SELECT `ID`, `Events` FROM Webhook").
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "Events"}).AddRow(5, "REMINDER"))
+	mock.ExpectExec("INSERT INTO WebhookDelivery").
+		WithArgs(5, sqlmock.AnyArg(), "PENDING", sqlmock.AnyArg(), sqlmock.AnyArg(), 5, sqlmock.AnyArg(), "PENDING", sqlmock.AnyArg(), sqlmock.AnyArg(),
+			5, sqlmock.AnyArg(), "PENDING", sqlmock.AnyArg(), sqlmock.AnyArg(), 5, sqlmock.AnyArg(), "PENDING", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 4))
+	mock.ExpectCommit()
+
+	hub := NewHub(DefaultHubHistory)
+	sub, _, _ := hub.Subscribe(0)
+
+	notifier := notifierFunc(func(ctx context.Context, todo *v1.ToDo) error {
+		if todo.Id == 2 || todo.Id == 3 || todo.Id == 6 || todo.Id == 7 {
+			return errors.New("mailbox is full")
+		}
+		return nil
+	})
+
+	n, err := deliverReminders(context.Background(), db, hub, notifier, now)
+	if err != nil || n != 7 {
+		t.Fatalf("deliverReminders() =%d, error =%v, want 7", n, err)
+	}
+
+	reminder, _ := ptypes.TimestampProto(now)
+	for _, want := range []v1.ToDo_ReminderStatus{v1.ToDo_DELIVERED, v1.ToDo_FAILED, v1.ToDo_FAILED} {
+		ev := <-sub.C
+		if ev.Type != v1.ToDoEvent_UPDATED || ev.ToDo.ReminderStatus != want || ev.ToDo.ReminderDeliveryTime == nil || !proto.Equal(ev.ToDo.Reminder, reminder) {
+			t.Errorf("published event =%v, want UPDATED with reminderStatus %v and the same reminder", ev, want)
+		}
+	}
+
+	nextWeek, _ := ptypes.TimestampProto(now.Truncate(time.Second).AddDate(0, 0, 7))
+	if ev := <-sub.C; ev.Type != v1.ToDoEvent_UPDATED || ev.ToDo.ReminderStatus != v1.ToDo_PENDING || ev.ToDo.ReminderDeliveryTime != nil || !proto.Equal(ev.ToDo.Reminder, nextWeek) {
+		t.Errorf("published event =%v, want UPDATED with the next weekly reminder pending", ev)
+	}
+	if ev := <-sub.C; ev.Type != v1.ToDoEvent_UPDATED || ev.ToDo.ReminderStatus != v1.ToDo_FAILED || !proto.Equal(ev.ToDo.Reminder, reminder) {
+		t.Errorf("published event =%v, want UPDATED with reminderStatus FAILED and the same reminder", ev)
+	}
+	tomorrow, _ := ptypes.TimestampProto(now.Truncate(time.Second).AddDate(0, 0, 1))
+	if ev := <-sub.C; ev.Type != v1.ToDoEvent_UPDATED || ev.ToDo.ReminderStatus != v1.ToDo_PENDING || !proto.Equal(ev.ToDo.Reminder, tomorrow) {
+		t.Errorf("published event =%v, want UPDATED with the next daily reminder pending", ev)
+	}
+
+	select {
+	case ev := <-sub.C:
+		t.Errorf("published event =%v, want none for a reminder whose lease was lost", ev)
+	default:
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDeliverRecurringReminders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	first := time.Now().UTC().Truncate(time.Second)
+	columns := []string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone", "ReminderAttempts"}
+
+	// 每周的提醒到期两次，每次发送后前进到下一周
+	for i := 0; i < 2; i++ {
+		now := first.AddDate(0, 0, 7*i)
+		lease := now.Add(reminderLease).Truncate(time.Second)
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ReminderStatus` IN").
+			WithArgs("PENDING", "FAILED", now, maxReminderAttempts, now, reminderBatchSize).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, "weekly", "", now, nil, "PENDING", nil, "DTSTART:"+first.Format("20060102T150405")+"\nRRULE:FREQ=WEEKLY", "", 0))
+		mock.ExpectExec("UPDATE ToDo SET `ReminderNextAttemptTime`=\\? WHERE `ID` IN \\(\\?\\)").WithArgs(lease, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE ToDo SET `Reminder`=\\?, `ReminderStatus`=\\?, `ReminderDeliveryTime`=NULL, `ReminderAttempts`=0, `ReminderNextAttemptTime`=NULL WHERE `ID`=\\? AND `ReminderNextAttemptTime`=\\?").
+			WithArgs(now.AddDate(0, 0, 7), "PENDING", 1, lease).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectNoWebhooks(mock)
+		mock.ExpectCommit()
+	}
+
+	var fired []time.Time
+	notifier := notifierFunc(func(ctx context.Context, todo *v1.ToDo) error {
+		reminder, _ := ptypes.Timestamp(todo.Reminder)
+		fired = append(fired, reminder)
+		return nil
+	})
+
+	hub := NewHub(DefaultHubHistory)
+	for i := 0; i < 2; i++ {
+		if n, err := deliverReminders(context.Background(), db, hub, notifier, first.AddDate(0, 0, 7*i)); err != nil || n != 1 {
+			t.Fatalf("deliverReminders() =%d, error =%v, want 1", n, err)
+		}
+	}
+
+	if len(fired) != 2 || !fired[0].Equal(first) || !fired[1].Equal(first.AddDate(0, 0, 7)) {
+		t.Errorf("fired reminders =%v, want %v and the week after", fired, first)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -15,20 +18,56 @@ import (
 const (
 	// 由服务器指定的版本号
 	apiVersion = "v1"
+
+	// toDoColumns 是 scanToDo 读取的列
+	toDoColumns = "`ID`, `Title`, `Description`, `Reminder`, `DeleteTime`, `ReminderStatus`, `ReminderDeliveryTime`, `Recurrence`, `TimeZone`"
 )
 
-type toDoServiceServer struct {
+// server 是各个服务共用的版本检查和数据库访问
+type server struct {
 	db *sql.DB
 }
 
-func NewToDoServiceServer(db *sql.DB) v1.ToDoServiceServer {
-	return &toDoServiceServer{db: db}
+type toDoServiceServer struct {
+	server
+	hub *Hub
+
+	// 幂等键的有效期
+	idempotencyWindow time.Duration
+	// 签发日历订阅令牌的密钥，为空时禁用日历订阅
+	calendarSecret string
+}
+
+// dbtx 是 *sql.Conn 和 *sql.Tx 共有的方法，查询函数可以在连接或事务中执行
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Server 是 v1 的 ToDoService，另外提供 v2 需要而 v1 API 没有的分页查询和部分更新
+type Server interface {
+	v1.ToDoServiceServer
+
+	// ListPage 按 ID 顺序返回 ID 大于 after 的最多 limit 个 ToDo
+	ListPage(ctx context.Context, after int64, limit int, showDeleted bool) ([]*v1.ToDo, error)
+	// Patch 在一个事务中锁定 ToDo，用 change 修改读出的内容后保存，返回保存的内容
+	Patch(ctx context.Context, id int64, change func(todo *v1.ToDo) error) (*v1.ToDo, error)
+}
+
+// NewToDoServiceServer 创建 ToDo 服务，Create/Update/Delete 成功后的变更事件发布到 hub
+// idempotencyWindow 是 Create 请求幂等键的有效期，calendarSecret 是签发日历订阅令牌的密钥
+func NewToDoServiceServer(db *sql.DB, hub *Hub, idempotencyWindow time.Duration, calendarSecret string) Server {
+	return &toDoServiceServer{server: server{db}, hub: hub, idempotencyWindow: idempotencyWindow, calendarSecret: calendarSecret}
 }
 
 // checkAPI 检测客户端请求的 api 版本是否被服务器支持
-func (t *toDoServiceServer) checkAPI(api string) error {
+func (s *server) checkAPI(api string) error {
 	// "" 版本号意味着使用现在的版本
 	if len(api) > 0 {
+		// v2 没有 api 字段，由单独的 v2.ToDoService 提供
+		if api == "v2" {
+			return status.Error(codes.Unimplemented, "API version 'v2' is served by the v2.ToDoService service (REST: /v2/todos)")
+		}
 		if apiVersion != api {
 			return status.Errorf(codes.Unimplemented, "unsupported API version: service implements API version '%s', but asked for '%s'", apiVersion, api)
 		}
@@ -39,10 +78,10 @@ func (t *toDoServiceServer) checkAPI(api string) error {
 
 // connect  从数据库连接池返回一个数据库连接
 // connect returns SQL database connection from the pool
-func (t *toDoServiceServer) connect(ctx context.Context) (*sql.Conn, error) {
-	conn, err := t.db.Conn(ctx)
+func (s *server) connect(ctx context.Context) (*sql.Conn, error) {
+	conn, err := s.db.Conn(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to connect to database-> "+err.Error())
+		return nil, unavailable(ctx, err)
 	}
 	return conn, nil
 }
@@ -56,32 +95,57 @@ func (t *toDoServiceServer) Create(ctx context.Context, in *v1.CreateRequest) (*
 		return nil, err
 	}
 
-	// 从数据库连接池中获取连接
-	// get SQL connection from pool
-	conn, err := t.connect(ctx)
-	if err != nil {
+	// 在访问数据库之前校验请求参数
+	if err := validateCreate(in); err != nil {
 		return nil, err
 	}
+	normalizeRecurrence(in.ToDo)
 
-	defer conn.Close()
+	// 带幂等键的请求在事务中创建并记录幂等键
+	if key := idempotencyKey(ctx, in); len(key) > 0 {
+		if err := validateIdempotencyKey(key); err != nil {
+			return nil, err
+		}
+		return t.createIdempotent(ctx, key, in)
+	}
 
 	reminder, err := ptypes.Timestamp(in.ToDo.Reminder)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "reminder field has invalid format->"+err.Error())
 	}
 
-	res, err := conn.ExecContext(ctx, "INSERT INTO ToDo(`Title`, `Description`, `Reminder`) VALUES (?,?,?)",
-		in.ToDo.Title, in.ToDo.Description, reminder)
+	// 开启事务，ToDo 和它的变更历史一起写入
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "INSERT INTO ToDo(`Title`, `Description`, `Reminder`, `Recurrence`, `TimeZone`, `Owner`) VALUES (?,?,?,?,?,?)",
+		in.ToDo.Title, in.ToDo.Description, reminder, in.ToDo.Recurrence, in.ToDo.TimeZone, owner(ctx))
 
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to insert into ToDo->"+err.Error())
+		return nil, dbError(ctx, "failed to insert into ToDo", err)
 	}
 
 	id, err := res.LastInsertId()
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to retrieve id for created ToDo->"+err.Error())
+		return nil, dbError(ctx, "failed to retrieve id for created ToDo", err)
+	}
+
+	created := *in.ToDo
+	created.Id = id
+	if err := recordHistory(ctx, tx, principal(ctx), historyEntry{v1.ToDoEvent_CREATED, nil, &created}); err != nil {
+		return nil, dbError(ctx, "failed to insert into ToDoHistory", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit transaction", err)
 	}
 
+	t.hub.Publish(v1.ToDoEvent_CREATED, &created)
+
 	return &v1.CreateResponse{
 		Api: apiVersion,
 		Id:  id,
@@ -94,83 +158,118 @@ func (t *toDoServiceServer) Read(ctx context.Context, in *v1.ReadRequest) (*v1.R
 		return nil, err
 	}
 
-	conn, err := t.db.Conn(ctx)
+	conn, err := t.connect(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	defer conn.Close()
 
-	rows, err := conn.QueryContext(ctx, "SELECT `ID`, `Title`, `Description`, `Reminder` FROM ToDo WHERE `ID`=?", in.Id)
+	todo, err := queryToDo(ctx, conn, in.Id, in.ShowDeleted)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to select from ToDo->"+err.Error())
+		return nil, err
 	}
 
-	defer rows.Close()
+	return &v1.ReadResponse{
+		Api:  apiVersion,
+		ToDo: todo,
+	}, nil
+}
 
-	if !rows.Next() {
-		if err = rows.Err(); err != nil {
-			return nil, status.Error(codes.Unknown, "failed to retrieve data from ToDo->"+err.Error())
-		}
-		return nil, status.Errorf(codes.NotFound, "ToDo with ID='%d' is not found", in.Id)
+func (t *toDoServiceServer) Update(ctx context.Context, in *v1.UpdateRequest) (*v1.UpdateResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
 	}
 
-	var todo v1.ToDo
-	var reminder time.Time
-	if err = rows.Scan(&todo.Id, &todo.Title, &todo.Description, &reminder); err != nil {
-		return nil, status.Error(codes.Unknown, "failed to retrieve field values from ToDo row->"+err.Error())
+	if err := validateUpdate(in); err != nil {
+		return nil, err
 	}
+	normalizeRecurrence(in.ToDo)
 
-	todo.Reminder, err = ptypes.TimestampProto(reminder)
+	_, rows, err := t.save(ctx, in.ToDo.Id, func(*v1.ToDo) (*v1.ToDo, error) { return in.ToDo, nil })
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "reminder field has invalid format->"+err.Error())
+		return nil, err
 	}
 
-	if rows.Next() {
-		return nil, status.Errorf(codes.Unknown, "found multiple ToDo rows with ID='%d'", in.Id)
-	}
-	return &v1.ReadResponse{
-		Api:  apiVersion,
-		ToDo: &todo,
+	return &v1.UpdateResponse{
+		Api:     apiVersion,
+		Updated: rows,
 	}, nil
+
 }
 
-func (t *toDoServiceServer) Update(ctx context.Context, in *v1.UpdateRequest) (*v1.UpdateResponse, error) {
-	if err := t.checkAPI(in.Api); err != nil {
-		return nil, err
-	}
+// Patch 在一个事务中锁定 ToDo，用 change 修改读出的内容，校验后保存; 与先 Read 再 Update 不同，并发的修改不会互相覆盖
+func (t *toDoServiceServer) Patch(ctx context.Context, id int64, change func(todo *v1.ToDo) error) (*v1.ToDo, error) {
+	todo, _, err := t.save(ctx, id, func(old *v1.ToDo) (*v1.ToDo, error) {
+		todo := proto.Clone(old).(*v1.ToDo)
+		if err := change(todo); err != nil {
+			return nil, err
+		}
+		if err := validateUpdate(&v1.UpdateRequest{ToDo: todo}); err != nil {
+			return nil, err
+		}
+		normalizeRecurrence(todo)
+		return todo, nil
+	})
+	return todo, err
+}
 
-	conn, err := t.db.Conn(ctx)
+// save 锁定不在回收站中的 ToDo，把 change 返回的新内容写入数据库并记录变更历史，返回新的内容和更新的行数
+func (t *toDoServiceServer) save(ctx context.Context, id int64, change func(old *v1.ToDo) (*v1.ToDo, error)) (*v1.ToDo, int64, error) {
+	tx, err := t.begin(ctx)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	defer conn.Close()
+	defer tx.Rollback()
 
-	reminder, err := ptypes.Timestamp(in.ToDo.Reminder)
+	// 锁定并读出更新前的内容，记录到变更历史
+	old, err := lockToDo(ctx, tx, id, false)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "reminder field has invalid format->"+err.Error())
+		return nil, 0, err
+	}
+	todo, err := change(old)
+	if err != nil {
+		return nil, 0, err
+	}
+	if v := checkReminderChange(old, todo, "toDo.reminder"); v != nil {
+		return nil, 0, invalidArgument([]*errdetails.BadRequest_FieldViolation{v})
+	}
+
+	reminder, err := ptypes.Timestamp(todo.Reminder)
+	if err != nil {
+		return nil, 0, status.Error(codes.InvalidArgument, "reminder field has invalid format->"+err.Error())
 	}
 
-	res, err := conn.ExecContext(ctx, "UPDATE ToDo SET `Title`=?, `Description`=?, `Reminder`=? WHERE `ID`=?", in.ToDo.Title, in.ToDo.Description, reminder, in.ToDo.Id)
+	res, err := tx.ExecContext(ctx, "UPDATE ToDo SET `Title`=?, `Description`=?, `Reminder`=?, `Recurrence`=?, `TimeZone`=? WHERE `ID`=? AND `DeleteTime` IS NULL",
+		todo.Title, todo.Description, reminder, todo.Recurrence, todo.TimeZone, id)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to update ToDo->"+err.Error())
+		return nil, 0, dbError(ctx, "failed to update ToDo", err)
 	}
 
 	rows, err := res.RowsAffected()
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to retrieve rows affected value->"+err.Error())
+		return nil, 0, dbError(ctx, "failed to retrieve rows affected value", err)
 	}
 
-	if rows == 0 {
-		return nil, status.Errorf(codes.NotFound, "ToDo with ID='%d' is not found", in.ToDo.Id)
+	// 提醒时间改变后重新等待发送
+	if carryReminder(old, todo) {
+		if err := resetReminders(ctx, tx, []interface{}{id}); err != nil {
+			return nil, 0, dbError(ctx, "failed to update ToDo", err)
+		}
 	}
 
-	return &v1.UpdateResponse{
-		Api:     apiVersion,
-		Updated: rows,
-	}, nil
+	if err := recordHistory(ctx, tx, principal(ctx), historyEntry{v1.ToDoEvent_UPDATED, old, todo}); err != nil {
+		return nil, 0, dbError(ctx, "failed to insert into ToDoHistory", err)
+	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, 0, dbError(ctx, "failed to commit transaction", err)
+	}
+
+	t.hub.Publish(v1.ToDoEvent_UPDATED, todo)
+
+	return todo, rows, nil
 }
 
 func (t *toDoServiceServer) Delete(ctx context.Context, in *v1.DeleteRequest) (*v1.DeleteResponse, error) {
@@ -178,27 +277,43 @@ func (t *toDoServiceServer) Delete(ctx context.Context, in *v1.DeleteRequest) (*
 		return nil, err
 	}
 
-	conn, err := t.db.Conn(ctx)
+	tx, err := t.begin(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	defer conn.Close()
+	defer tx.Rollback()
 
-	res, err := conn.ExecContext(ctx, "DELETE FROM ToDo WHERE `ID`=?", in.Id)
+	// 先锁定并读出要删除的 ToDo，变更历史和删除事件中需要携带完整内容
+	old, err := lockToDo(ctx, tx, in.Id, false)
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to delete ToDo->"+err.Error())
+		return nil, err
+	}
+
+	// 删除只是把 ToDo 移到回收站，可以通过 Undelete 恢复
+	now := time.Now().UTC()
+	res, err := tx.ExecContext(ctx, "UPDATE ToDo SET `DeleteTime`=? WHERE `ID`=? AND `DeleteTime` IS NULL", now, in.Id)
+	if err != nil {
+		return nil, dbError(ctx, "failed to delete ToDo", err)
 	}
 
 	rows, err := res.RowsAffected()
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to retrieve rows affected value->"+err.Error())
+		return nil, dbError(ctx, "failed to retrieve rows affected value", err)
+	}
+
+	todo := proto.Clone(old).(*v1.ToDo)
+	todo.DeleteTime, _ = ptypes.TimestampProto(now)
+	if err := recordHistory(ctx, tx, principal(ctx), historyEntry{v1.ToDoEvent_DELETED, old, todo}); err != nil {
+		return nil, dbError(ctx, "failed to insert into ToDoHistory", err)
 	}
 
-	if rows == 0 {
-		return nil, status.Errorf(codes.NotFound, "ToDo with ID='%d' is not found", in.Id)
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit transaction", err)
 	}
 
+	t.hub.Publish(v1.ToDoEvent_DELETED, todo)
+
 	return &v1.DeleteResponse{
 		Api:     apiVersion,
 		Deleted: rows,
@@ -211,40 +326,33 @@ func (t *toDoServiceServer) ReadAll(ctx context.Context, in *v1.ReadAllRequest)
 		return nil, err
 	}
 
-	conn, err := t.db.Conn(ctx)
+	conn, err := t.connect(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	defer conn.Close()
 
-	rows, err := conn.QueryContext(ctx, "SELECT `ID`, `Title`, `Description`, `Reminder` FROM ToDo")
+	rows, err := conn.QueryContext(ctx, "SELECT "+toDoColumns+" FROM ToDo"+notDeleted(in.ShowDeleted, "WHERE"))
 	if err != nil {
-		return nil, status.Error(codes.Unknown, "failed to select from ToDo->"+err.Error())
+		return nil, dbError(ctx, "failed to select from ToDo", err)
 	}
 
 	defer rows.Close()
 
-	var reminder time.Time
 	var list []*v1.ToDo
 
 	for rows.Next() {
-		todo := new(v1.ToDo)
-
-		if err := rows.Scan(&todo.Id, &todo.Title, &todo.Description, &reminder); err != nil {
-			return nil, status.Error(codes.Unknown, "failed to retrieve field values from ToDo row->"+err.Error())
-		}
-
-		todo.Reminder, err = ptypes.TimestampProto(reminder)
+		todo, err := scanToDo(ctx, rows)
 		if err != nil {
-			return nil, status.Error(codes.Unknown, "reminder field has invalid format->"+err.Error())
+			return nil, err
 		}
 
 		list = append(list, todo)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, status.Error(codes.Unknown, "failed to retrieve data from ToDo->"+err.Error())
+		return nil, dbError(ctx, "failed to retrieve data from ToDo", err)
 	}
 
 	return &v1.ReadAllResponse{
@@ -252,3 +360,194 @@ func (t *toDoServiceServer) ReadAll(ctx context.Context, in *v1.ReadAllRequest)
 		ToDos: list,
 	}, nil
 }
+
+// ListPage 按 ID 顺序返回 ID 大于 after 的最多 limit 个 ToDo，分页在数据库中完成
+func (t *toDoServiceServer) ListPage(ctx context.Context, after int64, limit int, showDeleted bool) ([]*v1.ToDo, error) {
+	conn, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, "SELECT "+toDoColumns+" FROM ToDo WHERE `ID`>?"+notDeleted(showDeleted, "AND")+" ORDER BY `ID` LIMIT ?", after, limit)
+	if err != nil {
+		return nil, dbError(ctx, "failed to select from ToDo", err)
+	}
+
+	defer rows.Close()
+
+	var list []*v1.ToDo
+	for rows.Next() {
+		todo, err := scanToDo(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, todo)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(ctx, "failed to retrieve data from ToDo", err)
+	}
+	return list, nil
+}
+
+// StreamAll 逐条发送扫描到的 ToDo，不在内存中缓存整个列表
+func (t *toDoServiceServer) StreamAll(in *v1.StreamAllRequest, stream v1.ToDoService_StreamAllServer) error {
+	if err := t.checkAPI(in.Api); err != nil {
+		return err
+	}
+
+	// 客户端取消或断开时 ctx 会被取消，QueryContext 随之结束
+	ctx := stream.Context()
+
+	conn, err := t.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, "SELECT "+toDoColumns+" FROM ToDo"+notDeleted(in.ShowDeleted, "WHERE"))
+	if err != nil {
+		return dbError(ctx, "failed to select from ToDo", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		todo, err := scanToDo(ctx, rows)
+		if err != nil {
+			return err
+		}
+
+		// Send 在客户端接收缓慢时会因流控阻塞，客户端断开时返回错误
+		if err := stream.Send(&v1.StreamAllResponse{Api: apiVersion, ToDo: todo}); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return dbError(ctx, "failed to retrieve data from ToDo", err)
+	}
+
+	return nil
+}
+
+// queryToDo 读取指定 ID 的 ToDo，不存在时返回 NotFound
+// showDeleted 为 false 时回收站中的 ToDo 也返回 NotFound
+func queryToDo(ctx context.Context, q dbtx, id int64, showDeleted bool) (*v1.ToDo, error) {
+	return selectToDo(ctx, q, id, showDeleted, "")
+}
+
+// lockToDo 与 queryToDo 相同，但在事务中锁定读取的行直到事务结束
+func lockToDo(ctx context.Context, tx *sql.Tx, id int64, showDeleted bool) (*v1.ToDo, error) {
+	return selectToDo(ctx, tx, id, showDeleted, " FOR UPDATE")
+}
+
+func selectToDo(ctx context.Context, q dbtx, id int64, showDeleted bool, lock string) (*v1.ToDo, error) {
+	rows, err := q.QueryContext(ctx, "SELECT "+toDoColumns+" FROM ToDo WHERE `ID`=?"+notDeleted(showDeleted, "AND")+lock, id)
+	if err != nil {
+		return nil, dbError(ctx, "failed to select from ToDo", err)
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return nil, dbError(ctx, "failed to retrieve data from ToDo", err)
+		}
+		return nil, status.Errorf(codes.NotFound, "ToDo with ID='%d' is not found", id)
+	}
+
+	todo, err := scanToDo(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if rows.Next() {
+		return nil, status.Errorf(codes.Internal, "found multiple ToDo rows with ID='%d'", id)
+	}
+	return todo, nil
+}
+
+// scanToDo 从当前行读取 ToDo，查询的列必须是 toDoColumns，之后的列读取到 extra
+func scanToDo(ctx context.Context, rows *sql.Rows, extra ...interface{}) (*v1.ToDo, error) {
+	var todo v1.ToDo
+	var reminder time.Time
+	var deleteTime, deliveryTime mysql.NullTime
+	var reminderStatus string
+	dest := []interface{}{&todo.Id, &todo.Title, &todo.Description, &reminder, &deleteTime, &reminderStatus, &deliveryTime, &todo.Recurrence, &todo.TimeZone}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return nil, dbError(ctx, "failed to retrieve field values from ToDo row", err)
+	}
+
+	var err error
+	todo.Reminder, err = ptypes.TimestampProto(reminder)
+	if err != nil {
+		return nil, dbError(ctx, "reminder field has invalid format", err)
+	}
+
+	if deleteTime.Valid {
+		todo.DeleteTime, err = ptypes.TimestampProto(deleteTime.Time)
+		if err != nil {
+			return nil, dbError(ctx, "deleteTime field has invalid format", err)
+		}
+	}
+
+	todo.ReminderStatus = v1.ToDo_ReminderStatus(v1.ToDo_ReminderStatus_value[reminderStatus])
+	if deliveryTime.Valid {
+		todo.ReminderDeliveryTime, err = ptypes.TimestampProto(deliveryTime.Time)
+		if err != nil {
+			return nil, dbError(ctx, "reminderDeliveryTime field has invalid format", err)
+		}
+	}
+
+	return &todo, nil
+}
+
+// notDeleted 返回排除回收站中 ToDo 的查询条件，op 是连接条件的关键字 (WHERE 或 AND)
+func notDeleted(showDeleted bool, op string) string {
+	if showDeleted {
+		return ""
+	}
+	return " " + op + " `DeleteTime` IS NULL"
+}
+
+// Watch 把 ToDo 的变更事件推送给客户端，sinceSequence 不为 0 时先补发该序号之后的事件
+func (t *toDoServiceServer) Watch(in *v1.WatchRequest, stream v1.ToDoService_WatchServer) error {
+	if err := t.checkAPI(in.Api); err != nil {
+		return err
+	}
+
+	sub, backlog, err := t.hub.Subscribe(in.SinceSequence)
+	if err == ErrSequenceExpired {
+		return status.Errorf(codes.OutOfRange, "sequence %d is no longer available, reload and watch again", in.SinceSequence)
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	defer t.hub.Unsubscribe(sub)
+
+	for _, ev := range backlog {
+		if err := stream.Send(&v1.WatchResponse{Api: apiVersion, Event: ev}); err != nil {
+			return err
+		}
+	}
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case ev, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Aborted, "watcher fell behind, resume from the last received sequence")
+			}
+			if err := stream.Send(&v1.WatchResponse{Api: apiVersion, Event: ev}); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -2,15 +2,23 @@ package v1
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
+	"net"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // https://github.com/amsokol/go-grpc-http-rest-microservice-tutorial/blob/part1/pkg/service/v1/todo-service_test.go
@@ -25,7 +33,7 @@ func TestCreate(t *testing.T) {
 
 	defer db.Close()
 
-	toDoServer := NewToDoServiceServer(db)
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
 	timeNow := time.Now().In(time.UTC)
 	reminder, _ := ptypes.TimestampProto(timeNow)
 
@@ -57,7 +65,11 @@ func TestCreate(t *testing.T) {
 				},
 			},
 			mock: func() {
-				mock.ExpectExec("INSERT INTO ToDo").WithArgs("title", "description", timeNow).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO ToDo").WithArgs("title", "description", timeNow, "", "", nil).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("INSERT INTO ToDoHistory").WithArgs(1, "CREATED", nil, sqlmock.AnyArg(), anonymous, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+				expectNoWebhooks(mock)
+				mock.ExpectCommit()
 			},
 			want: &v1.CreateResponse{
 				Api: "v1",
@@ -104,6 +116,34 @@ func TestCreate(t *testing.T) {
 			mock:    func() {},
 			wantErr: true,
 		},
+		{
+			name: "Nil ToDo",
+			s:    toDoServer,
+			args: args{
+				ctx: ctx,
+				request: &v1.CreateRequest{
+					Api: "v1",
+				},
+			},
+			mock:    func() {},
+			wantErr: true,
+		},
+		{
+			name: "Empty title",
+			s:    toDoServer,
+			args: args{
+				ctx: ctx,
+				request: &v1.CreateRequest{
+					Api: "v1",
+					ToDo: &v1.ToDo{
+						Description: "description",
+						Reminder:    reminder,
+					},
+				},
+			},
+			mock:    func() {},
+			wantErr: true,
+		},
 		{
 			name: "INSERT failed",
 			s:    toDoServer,
@@ -119,7 +159,9 @@ func TestCreate(t *testing.T) {
 				},
 			},
 			mock: func() {
-				mock.ExpectExec("INSERT INTO ToDo").WithArgs("title", "description", timeNow).WillReturnError(errors.New("INSERT failed"))
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO ToDo").WithArgs("title", "description", timeNow, "", "", nil).WillReturnError(errors.New("INSERT failed"))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -138,7 +180,9 @@ func TestCreate(t *testing.T) {
 				},
 			},
 			mock: func() {
-				mock.ExpectExec("INSERT INTO ToDO").WithArgs("title", "description", timeNow).WillReturnResult(sqlmock.NewErrorResult(errors.New("LasterInsertId failed")))
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT INTO ToDO").WithArgs("title", "description", timeNow, "", "").WillReturnResult(sqlmock.NewErrorResult(errors.New("LasterInsertId failed")))
+				mock.ExpectRollback()
 			},
 			wantErr: true,
 		},
@@ -161,3 +205,228 @@ func TestCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateUpdate(t *testing.T) {
+	reminder, _ := ptypes.TimestampProto(time.Now().Add(20 * 365 * 24 * time.Hour))
+
+	err := validateUpdate(&v1.UpdateRequest{
+		Api: "v1",
+		ToDo: &v1.ToDo{
+			Title:    strings.Repeat("x", maxTitleLength+1),
+			Reminder: reminder,
+		},
+	})
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("validateUpdate() error =%v, want InvalidArgument", err)
+	}
+
+	var fields []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.FieldViolations {
+				fields = append(fields, v.Field)
+			}
+		}
+	}
+
+	// reminder 的范围在 Update 读出修改前的 ToDo 后才检查
+	want := []string{"toDo.id", "toDo.title"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("validateUpdate() field violations =%v, want=%v", fields, want)
+	}
+}
+
+func TestUpdateOldReminder(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	old := time.Now().UTC().Truncate(time.Second).Add(-2 * maxReminderPast)
+	reminder, _ := ptypes.TimestampProto(old)
+	columns := []string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone"}
+
+	// reminder 没有改变，早已过去的 ToDo 可以原样保存
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID`=\\? AND `DeleteTime` IS NULL FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "old", "", old, nil, "DELIVERED", old, "", ""))
+	mock.ExpectExec("UPDATE ToDo").WithArgs("new", "", old, "", "", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO ToDoHistory").WillReturnResult(sqlmock.NewResult(1, 1))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	if _, err := toDoServer.Update(context.Background(), &v1.UpdateRequest{Api: "v1", ToDo: &v1.ToDo{Id: 1, Title: "new", Reminder: reminder}}); err != nil {
+		t.Fatalf("toDoServiceServer.Update() error =%v", err)
+	}
+
+	// 改成另一个超出范围的时间
+	moved, _ := ptypes.TimestampProto(old.Add(-time.Hour))
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID`=\\? AND `DeleteTime` IS NULL FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "old", "", old, nil, "DELIVERED", old, "", ""))
+	mock.ExpectRollback()
+
+	_, err = toDoServer.Update(context.Background(), &v1.UpdateRequest{Api: "v1", ToDo: &v1.ToDo{Id: 1, Title: "new", Reminder: moved}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("toDoServiceServer.Update() error =%v, want InvalidArgument", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestListPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	timeNow := time.Now().UTC()
+	columns := []string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone"}
+
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID`>\\? AND `DeleteTime` IS NULL ORDER BY `ID` LIMIT \\?").WithArgs(4, 3).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(5, "title 5", "", timeNow, nil, "PENDING", nil, "", "").
+			AddRow(7, "title 7", "", timeNow, nil, "PENDING", nil, "", ""))
+
+	list, err := toDoServer.ListPage(context.Background(), 4, 3, false)
+	if err != nil || len(list) != 2 || list[0].Id != 5 || list[1].Id != 7 {
+		t.Errorf("toDoServiceServer.ListPage() =%v, error =%v, want ToDo 5 and 7", list, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	reminder := time.Now().UTC().Truncate(time.Second).Add(time.Hour)
+	columns := []string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone"}
+
+	// 在锁定 ToDo 的事务中合并修改，没有修改的字段保留读出的内容
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID`=\\? AND `DeleteTime` IS NULL FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "old", "description", reminder, nil, "PENDING", nil, "", ""))
+	mock.ExpectExec("UPDATE ToDo").WithArgs("new", "description", reminder, "", "", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO ToDoHistory").WithArgs(1, "UPDATED", sqlmock.AnyArg(), sqlmock.AnyArg(), anonymous, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	got, err := toDoServer.Patch(context.Background(), 1, func(todo *v1.ToDo) error {
+		todo.Title = "new"
+		return nil
+	})
+	if err != nil || got.Title != "new" || got.Description != "description" {
+		t.Fatalf("toDoServiceServer.Patch() =%v, error =%v", got, err)
+	}
+
+	// 合并后的内容无效时回滚
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID`=\\? AND `DeleteTime` IS NULL FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "old", "description", reminder, nil, "PENDING", nil, "", ""))
+	mock.ExpectRollback()
+
+	_, err = toDoServer.Patch(context.Background(), 1, func(todo *v1.ToDo) error {
+		todo.Title = ""
+		return nil
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("toDoServiceServer.Patch() error =%v, want InvalidArgument", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDBCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"deadlock", &mysql.MySQLError{Number: 1213}, codes.Aborted},
+		{"lock wait timeout", &mysql.MySQLError{Number: 1205}, codes.Aborted},
+		{"duplicate key", &mysql.MySQLError{Number: 1062}, codes.AlreadyExists},
+		{"too many connections", &mysql.MySQLError{Number: 1040}, codes.Unavailable},
+		{"connection refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, codes.Unavailable},
+		{"bad connection", driver.ErrBadConn, codes.Unavailable},
+		{"context deadline", context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"context canceled", context.Canceled, codes.Canceled},
+		{"syntax error", &mysql.MySQLError{Number: 1064}, codes.Internal},
+		{"unknown", errors.New("boom"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dbCode(tt.err, codes.Internal); got != tt.want {
+				t.Errorf("dbCode() =%v, want=%v", got, tt.want)
+			}
+		})
+	}
+}
+
+// streamAllServer 记录 StreamAll 发送的消息
+type streamAllServer struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*v1.StreamAllResponse
+}
+
+func (s *streamAllServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *streamAllServer) Send(m *v1.StreamAllResponse) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func TestStreamAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	timeNow := time.Now().In(time.UTC)
+	reminder, _ := ptypes.TimestampProto(timeNow)
+
+	rows := sqlmock.NewRows([]string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone"}).
+		AddRow(1, "title 1", "description 1", timeNow, nil, "PENDING", nil, "", "").
+		AddRow(2, "title 2", "description 2", timeNow, nil, "PENDING", nil, "", "")
+	mock.ExpectQuery("SELECT (.+) FROM ToDo").WillReturnRows(rows)
+
+	stream := &streamAllServer{ctx: context.Background()}
+	if err := toDoServer.StreamAll(&v1.StreamAllRequest{Api: "v1"}, stream); err != nil {
+		t.Fatalf("toDoServiceServer.StreamAll() error =%v", err)
+	}
+
+	want := []*v1.StreamAllResponse{
+		{Api: "v1", ToDo: &v1.ToDo{Id: 1, Title: "title 1", Description: "description 1", Reminder: reminder}},
+		{Api: "v1", ToDo: &v1.ToDo{Id: 2, Title: "title 2", Description: "description 2", Reminder: reminder}},
+	}
+	if !reflect.DeepEqual(stream.sent, want) {
+		t.Errorf("toDoServiceServer.StreamAll() sent =%v, want=%v", stream.sent, want)
+	}
+}
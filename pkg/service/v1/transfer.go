@@ -0,0 +1,522 @@
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// exportChunkSize 是 Export 每个响应消息中数据的大小
+	exportChunkSize = 64 * 1024
+	// maxImportSize 是 Import 上传数据的最大字节数
+	maxImportSize = 32 << 20
+	// externalId 的最大长度(字符数)
+	maxExternalIDLength = 128
+)
+
+// csvHeader 是导出的 CSV 的列; 导入时按列名读取，列的顺序任意，未知的列被忽略
+var csvHeader = []string{"id", "externalId", "title", "description", "reminder", "timeZone", "recurrence", "deleteTime"}
+
+// record 是导入导出文件中的一条 ToDo，时间使用 RFC 3339 格式
+// 导出的 externalId 是导入时的外部 ID，没有外部 ID 的 ToDo 导出为空: 用 ID 代替会和其他导入的外部 ID 冲突
+type record struct {
+	ID          int64  `json:"id,omitempty"`
+	ExternalID  string `json:"externalId,omitempty"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Reminder    string `json:"reminder,omitempty"`
+	TimeZone    string `json:"timeZone,omitempty"`
+	Recurrence  string `json:"recurrence,omitempty"`
+	DeleteTime  string `json:"deleteTime,omitempty"`
+}
+
+func newRecord(todo *v1.ToDo, externalID string) record {
+	r := record{
+		ID:          todo.Id,
+		ExternalID:  externalID,
+		Title:       todo.Title,
+		Description: todo.Description,
+		TimeZone:    todo.TimeZone,
+		Recurrence:  todo.Recurrence,
+	}
+	if todo.Reminder != nil {
+		r.Reminder = ptypes.TimestampString(todo.Reminder)
+	}
+	if todo.DeleteTime != nil {
+		r.DeleteTime = ptypes.TimestampString(todo.DeleteTime)
+	}
+	return r
+}
+
+// fields 按 csvHeader 的顺序返回 CSV 的一行
+func (r record) fields() []string {
+	var id string
+	if r.ID != 0 {
+		id = strconv.FormatInt(r.ID, 10)
+	}
+	return []string{id, r.ExternalID, r.Title, r.Description, r.Reminder, r.TimeZone, r.Recurrence, r.DeleteTime}
+}
+
+// toDo 把导入的记录转换为新的 ToDo，id 和 deleteTime 被忽略
+func (r record) toDo() (*v1.ToDo, []*errdetails.BadRequest_FieldViolation) {
+	todo := &v1.ToDo{Title: r.Title, Description: r.Description, TimeZone: r.TimeZone, Recurrence: r.Recurrence}
+	if len(r.Reminder) > 0 {
+		reminder, err := time.Parse(time.RFC3339Nano, r.Reminder)
+		if err != nil {
+			return nil, []*errdetails.BadRequest_FieldViolation{{Field: "reminder", Description: "invalid format, must be RFC 3339"}}
+		}
+		if todo.Reminder, err = ptypes.TimestampProto(reminder); err != nil {
+			return nil, []*errdetails.BadRequest_FieldViolation{{Field: "reminder", Description: "invalid format: " + err.Error()}}
+		}
+	}
+	return todo, nil
+}
+
+// exportWriter 把写入的数据按 exportChunkSize 分块发送
+type exportWriter struct {
+	stream v1.ToDoService_ExportServer
+	buf    bytes.Buffer
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	if w.buf.Len() >= exportChunkSize {
+		return len(p), w.flush()
+	}
+	return len(p), nil
+}
+
+// flush 发送缓存的数据，SendMsg 返回前已经序列化消息，之后可以重用缓存
+func (w *exportWriter) flush() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	err := w.stream.Send(&v1.ExportResponse{Api: apiVersion, Data: w.buf.Bytes()})
+	w.buf.Reset()
+	return err
+}
+
+// Export 以 JSON Lines 或 CSV 分块发送 ToDo，可以按是否删除、提醒时间范围 [reminderFrom, reminderTo) 和标题过滤
+func (t *toDoServiceServer) Export(in *v1.ExportRequest, stream v1.ToDoService_ExportServer) error {
+	if err := t.checkAPI(in.Api); err != nil {
+		return err
+	}
+
+	var conditions []string
+	var args []interface{}
+	if !in.ShowDeleted {
+		conditions = append(conditions, "`DeleteTime` IS NULL")
+	}
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, f := range []struct {
+		field, condition string
+		ts               *timestamp.Timestamp
+	}{
+		{"reminderFrom", "`Reminder`>=?", in.ReminderFrom},
+		{"reminderTo", "`Reminder`<?", in.ReminderTo},
+	} {
+		if f.ts == nil {
+			continue
+		}
+		ts, err := ptypes.Timestamp(f.ts)
+		if err != nil {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: f.field, Description: "invalid format: " + err.Error()})
+			continue
+		}
+		conditions, args = append(conditions, f.condition), append(args, ts)
+	}
+	if len(in.TitleContains) > 0 {
+		conditions, args = append(conditions, "`Title` LIKE ?"), append(args, "%"+escapeLike(in.TitleContains)+"%")
+	}
+	if in.Format != v1.DataFormat_JSONL && in.Format != v1.DataFormat_CSV {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "format", Description: "must be JSONL or CSV"})
+	}
+	if len(violations) > 0 {
+		return invalidArgument(violations)
+	}
+
+	ctx := stream.Context()
+
+	conn, err := t.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	query := "SELECT " + toDoColumns + ", `ExternalID` FROM ToDo"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	rows, err := conn.QueryContext(ctx, query+" ORDER BY `ID`", args...)
+	if err != nil {
+		return dbError(ctx, "failed to select from ToDo", err)
+	}
+
+	defer rows.Close()
+
+	w := &exportWriter{stream: stream}
+	var encode func(record) error
+	var finish func() error
+	if in.Format == v1.DataFormat_CSV {
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvHeader); err != nil {
+			return err
+		}
+		encode = func(r record) error { return cw.Write(r.fields()) }
+		finish = func() error { cw.Flush(); return cw.Error() }
+	} else {
+		enc := json.NewEncoder(w)
+		encode = func(r record) error { return enc.Encode(r) }
+		finish = func() error { return nil }
+	}
+
+	for rows.Next() {
+		var externalID sql.NullString
+		todo, err := scanToDo(ctx, rows, &externalID)
+		if err != nil {
+			return err
+		}
+		if err := encode(newRecord(todo, externalID.String)); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return dbError(ctx, "failed to retrieve data from ToDo", err)
+	}
+
+	if err := finish(); err != nil {
+		return err
+	}
+	return w.flush()
+}
+
+// escapeLike 转义 LIKE 模式中的通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// importRecord 是解析后的一条导入记录，violations 不为空时记录无效
+type importRecord struct {
+	externalID string
+	todo       *v1.ToDo
+	violations []*errdetails.BadRequest_FieldViolation
+}
+
+// parseRecords 解析上传的数据; 单条记录的错误记录在 violations 中，整个文件无法解析时返回错误
+func parseRecords(format v1.DataFormat, r io.Reader) ([]importRecord, error) {
+	var records []importRecord
+	add := func(rec record, violations ...*errdetails.BadRequest_FieldViolation) {
+		ir := importRecord{externalID: rec.ExternalID, violations: violations}
+		if len(violations) == 0 {
+			ir.todo, ir.violations = rec.toDo()
+		}
+		records = append(records, ir)
+	}
+
+	switch format {
+	case v1.DataFormat_CSV:
+		cr := csv.NewReader(r)
+		// 列数和标题行不同的行作为无效记录报告，不拒绝整个文件
+		cr.FieldsPerRecord = -1
+		header, err := cr.Read()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		columns := make(map[string]int)
+		for i, name := range header {
+			columns[strings.TrimSpace(name)] = i
+		}
+		if _, ok := columns["title"]; !ok {
+			return nil, fmt.Errorf("CSV header must contain a title column")
+		}
+		get := func(fields []string, name string) string {
+			if i, ok := columns[name]; ok {
+				return fields[i]
+			}
+			return ""
+		}
+
+		for {
+			fields, err := cr.Read()
+			if err == io.EOF {
+				return records, nil
+			}
+			if e, ok := err.(*csv.ParseError); ok {
+				add(record{}, &errdetails.BadRequest_FieldViolation{Field: "record", Description: "invalid CSV: " + e.Err.Error()})
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if len(fields) != len(header) {
+				add(record{}, &errdetails.BadRequest_FieldViolation{Field: "record", Description: fmt.Sprintf("must have %d fields like the header", len(header))})
+				continue
+			}
+			add(record{
+				ExternalID:  get(fields, "externalId"),
+				Title:       get(fields, "title"),
+				Description: get(fields, "description"),
+				Reminder:    get(fields, "reminder"),
+				TimeZone:    get(fields, "timeZone"),
+				Recurrence:  get(fields, "recurrence"),
+			})
+		}
+	case v1.DataFormat_JSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, maxImportSize)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			var rec record
+			if err := json.Unmarshal(line, &rec); err != nil {
+				add(rec, &errdetails.BadRequest_FieldViolation{Field: "record", Description: "invalid JSON: " + err.Error()})
+				continue
+			}
+			add(rec)
+		}
+		return records, scanner.Err()
+	default:
+		return nil, fmt.Errorf("unsupported format %v", format)
+	}
+}
+
+// Import 接收客户端分块上传的 JSON Lines 或 CSV 并创建 ToDo，第一个消息的 api、format 和 dryRun 对整个上传生效
+// 无效的记录和外部 ID 已经存在 (包括回收站中的 ToDo) 或在文件中重复的记录被跳过，其余记录在一个事务中创建
+// dryRun 为 true 时只返回报告，不写入数据库
+func (t *toDoServiceServer) Import(stream v1.ToDoService_ImportServer) error {
+	ctx := stream.Context()
+
+	var first *v1.ImportRequest
+	var data bytes.Buffer
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first == nil {
+			if err := t.checkAPI(in.Api); err != nil {
+				return err
+			}
+			first = in
+		}
+		if data.Len()+len(in.Data) > maxImportSize {
+			return status.Errorf(codes.InvalidArgument, "import must not be larger than %d bytes", maxImportSize)
+		}
+		data.Write(in.Data)
+	}
+	if first == nil {
+		return status.Error(codes.InvalidArgument, "import must not be empty")
+	}
+
+	records, err := parseRecords(first.Format, &data)
+	if err == nil && len(records) == 0 {
+		err = fmt.Errorf("must contain at least one record")
+	}
+	if err != nil {
+		return invalidArgument([]*errdetails.BadRequest_FieldViolation{{Field: "data", Description: err.Error()}})
+	}
+
+	resp := &v1.ImportResponse{Api: apiVersion, DryRun: first.DryRun, Results: make([]*v1.ImportResult, len(records))}
+	var valid []int
+	var externalIDs []interface{}
+	seen := make(map[string]bool)
+	for i, r := range records {
+		result := &v1.ImportResult{Record: int32(i + 1), ExternalId: r.externalID}
+		resp.Results[i] = result
+
+		violations := r.violations
+		if r.todo != nil {
+			violations = applyRules(violations, "", r.todo, createToDoRules)
+		}
+		if desc := maxLength(r.externalID, maxExternalIDLength); len(desc) > 0 {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "externalId", Description: desc})
+		}
+		if len(violations) > 0 {
+			result.Outcome, result.Status = v1.ImportResult_INVALID, status.Convert(invalidArgument(violations)).Proto()
+			continue
+		}
+
+		if len(r.externalID) > 0 {
+			if seen[r.externalID] {
+				result.Outcome, result.Status = v1.ImportResult_DUPLICATE, duplicateStatus(r.externalID)
+				continue
+			}
+			seen[r.externalID] = true
+			externalIDs = append(externalIDs, r.externalID)
+		}
+		normalizeRecurrence(r.todo)
+		valid = append(valid, i)
+	}
+
+	if first.DryRun {
+		conn, err := t.connect(ctx)
+		if err != nil {
+			return err
+		}
+		_, err = skipExisting(ctx, conn, records, valid, externalIDs, resp.Results, false)
+		conn.Close()
+		if err != nil {
+			return err
+		}
+	} else {
+		entries, err := t.insertRecords(ctx, records, valid, externalIDs, resp.Results)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			t.hub.Publish(v1.ToDoEvent_CREATED, e.new)
+		}
+	}
+
+	for _, r := range resp.Results {
+		switch r.Outcome {
+		case v1.ImportResult_CREATED:
+			resp.Created++
+		case v1.ImportResult_DUPLICATE:
+			resp.Duplicates++
+		case v1.ImportResult_INVALID:
+			resp.Invalid++
+		}
+	}
+
+	return stream.SendAndClose(resp)
+}
+
+func duplicateStatus(externalID string) *rpcstatus.Status {
+	return status.Newf(codes.AlreadyExists, "ToDo with externalId='%s' already exists", externalID).Proto()
+}
+
+// skipExisting 把 valid 中外部 ID 已经存在的记录标记为 DUPLICATE，返回其余要创建的记录
+func skipExisting(ctx context.Context, q dbtx, records []importRecord, valid []int, externalIDs []interface{}, results []*v1.ImportResult, forUpdate bool) ([]int, error) {
+	existing, err := findExternalIDs(ctx, q, externalIDs, forUpdate)
+	if err != nil {
+		return nil, err
+	}
+	var created []int
+	for _, i := range valid {
+		if id, ok := existing[records[i].externalID]; ok {
+			results[i].Id, results[i].Outcome, results[i].Status = id, v1.ImportResult_DUPLICATE, duplicateStatus(records[i].externalID)
+			continue
+		}
+		results[i].Status = okStatus
+		created = append(created, i)
+	}
+	return created, nil
+}
+
+// findExternalIDs 返回已经存在的外部 ID 对应的 ToDo ID
+// forUpdate 为 true 时锁定这些外部 ID (包括不存在的)，并发导入相同外部 ID 的事务等待当前事务提交
+func findExternalIDs(ctx context.Context, q dbtx, externalIDs []interface{}, forUpdate bool) (map[string]int64, error) {
+	existing := make(map[string]int64)
+	var lock string
+	if forUpdate {
+		lock = " FOR UPDATE"
+	}
+
+	for start := 0; start < len(externalIDs); start += maxBatchSize {
+		chunk := externalIDs[start:minInt(start+maxBatchSize, len(externalIDs))]
+		rows, err := q.QueryContext(ctx, "SELECT `ExternalID`, `ID` FROM ToDo WHERE `ExternalID` IN ("+placeholders(len(chunk), "?")+")"+lock, chunk...)
+		if err != nil {
+			return nil, dbError(ctx, "failed to select from ToDo", err)
+		}
+		for rows.Next() {
+			var externalID string
+			var id int64
+			if err := rows.Scan(&externalID, &id); err != nil {
+				rows.Close()
+				return nil, dbError(ctx, "failed to retrieve field values from ToDo row", err)
+			}
+			existing[externalID] = id
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, dbError(ctx, "failed to retrieve data from ToDo", err)
+		}
+	}
+	return existing, nil
+}
+
+// insertRecords 在一个事务中跳过外部 ID 已经存在的记录，分批插入 valid 中其余的记录并记录变更历史，把 ID 写入 results
+// 外部 ID 在同一个事务中检查和插入，并发的导入不会因为唯一键冲突而整体失败
+func (t *toDoServiceServer) insertRecords(ctx context.Context, records []importRecord, valid []int, externalIDs []interface{}, results []*v1.ImportResult) ([]historyEntry, error) {
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	created, err := skipExisting(ctx, tx, records, valid, externalIDs, results, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(created) == 0 {
+		return nil, nil
+	}
+
+	var entries []historyEntry
+	for start := 0; start < len(created); start += maxBatchSize {
+		items := created[start:minInt(start+maxBatchSize, len(created))]
+		todos := make([]*v1.ToDo, len(items))
+		externalIDs := make([]string, len(items))
+		for n, i := range items {
+			todos[n], externalIDs[n] = records[i].todo, records[i].externalID
+		}
+
+		ids, err := insertToDos(ctx, tx, todos, externalIDs)
+		if err != nil {
+			return nil, dbError(ctx, "failed to insert into ToDo", err)
+		}
+
+		chunk := make([]historyEntry, len(items))
+		for n, i := range items {
+			results[i].Id, todos[n].Id = ids[n], ids[n]
+			chunk[n] = historyEntry{v1.ToDoEvent_CREATED, nil, todos[n]}
+		}
+		if err := recordHistory(ctx, tx, principal(ctx), chunk...); err != nil {
+			return nil, dbError(ctx, "failed to insert into ToDoHistory", err)
+		}
+		entries = append(entries, chunk...)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit transaction", err)
+	}
+	return entries, nil
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,159 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"google.golang.org/grpc"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+type exportStream struct {
+	grpc.ServerStream
+	data bytes.Buffer
+}
+
+func (s *exportStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *exportStream) Send(resp *v1.ExportResponse) error {
+	s.data.Write(resp.Data)
+	return nil
+}
+
+type importStream struct {
+	grpc.ServerStream
+	requests []*v1.ImportRequest
+	resp     *v1.ImportResponse
+}
+
+func (s *importStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *importStream) Recv() (*v1.ImportRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	in := s.requests[0]
+	s.requests = s.requests[1:]
+	return in, nil
+}
+
+func (s *importStream) SendAndClose(resp *v1.ImportResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestExport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	reminder := time.Date(2019, 5, 6, 7, 0, 0, 0, time.UTC)
+
+	columns := []string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone", "ExternalID"}
+	mock.ExpectQuery("SELECT (.+), `ExternalID` FROM ToDo WHERE `DeleteTime` IS NULL AND `Title` LIKE \\? ORDER BY `ID`").
+		WithArgs(`%50\%%`).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "save 50%", "a, b", reminder, nil, "PENDING", nil, "", "Europe/Berlin", nil).
+			AddRow(2, "save 50% more", "", reminder, nil, "PENDING", nil, "", "", "legacy-7"))
+
+	stream := &exportStream{}
+	if err := toDoServer.Export(&v1.ExportRequest{Api: "v1", Format: v1.DataFormat_CSV, TitleContains: "50%"}, stream); err != nil {
+		t.Fatalf("toDoServiceServer.Export() error =%v", err)
+	}
+
+	want := "id,externalId,title,description,reminder,timeZone,recurrence,deleteTime\n" +
+		"1,,save 50%,\"a, b\",2019-05-06T07:00:00Z,Europe/Berlin,,\n" +
+		"2,legacy-7,save 50% more,,2019-05-06T07:00:00Z,,,\n"
+	if got := stream.data.String(); got != want {
+		t.Errorf("toDoServiceServer.Export() =%q, want %q", got, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	toDoServer := NewToDoServiceServer(db, NewHub(DefaultHubHistory), DefaultIdempotencyWindow, "")
+	reminder := time.Now().UTC().Truncate(time.Second)
+	data := strings.Join([]string{
+		`{"externalId":"a","title":"new","reminder":"` + reminder.Format(time.RFC3339) + `"}`,
+		`{"externalId":"a","title":"same id again","reminder":"` + reminder.Format(time.RFC3339) + `"}`,
+		`{"externalId":"b","title":"already imported","reminder":"` + reminder.Format(time.RFC3339) + `"}`,
+		`{"externalId":"c","title":"","reminder":"yesterday"}`,
+		`not json`,
+	}, "\n")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `ExternalID`, `ID` FROM ToDo WHERE `ExternalID` IN \\(\\?,\\?\\) FOR UPDATE").WithArgs("a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"ExternalID", "ID"}).AddRow("b", 3))
+	mock.ExpectExec("INSERT INTO ToDo\\(`Title`, `Description`, `Reminder`, `Recurrence`, `TimeZone`, `Owner`, `ExternalID`\\)").
+		WithArgs("new", "", reminder, "", "", nil, "a").WillReturnResult(sqlmock.NewResult(10, 1))
+	mock.ExpectExec("INSERT INTO ToDoHistory").WithArgs(10, "CREATED", nil, sqlmock.AnyArg(), anonymous, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	// 数据分成两块上传
+	stream := &importStream{requests: []*v1.ImportRequest{
+		{Api: "v1", Format: v1.DataFormat_JSONL, Data: []byte(data[:40])},
+		{Data: []byte(data[40:])},
+	}}
+	if err := toDoServer.Import(stream); err != nil {
+		t.Fatalf("toDoServiceServer.Import() error =%v", err)
+	}
+
+	got := stream.resp
+	if got.Created != 1 || got.Duplicates != 2 || got.Invalid != 2 {
+		t.Errorf("toDoServiceServer.Import() =%v, want 1 created, 2 duplicates, 2 invalid", got)
+	}
+	if r := got.Results[0]; r.Id != 10 || r.Outcome != v1.ImportResult_CREATED {
+		t.Errorf("results[0] =%v, want created with ID 10", r)
+	}
+	if r := got.Results[2]; r.Id != 3 || r.Outcome != v1.ImportResult_DUPLICATE {
+		t.Errorf("results[2] =%v, want duplicate of ID 3", r)
+	}
+	if r := got.Results[3]; r.Outcome != v1.ImportResult_INVALID || len(r.Status.Details) != 1 {
+		t.Errorf("results[3] =%v, want invalid with field violations", r)
+	}
+
+	// dryRun 不写入数据库，格式错误的 CSV 行只影响这一行
+	mock.ExpectQuery("SELECT `ExternalID`, `ID` FROM ToDo WHERE `ExternalID` IN \\(\\?\\)$").WithArgs("x").WillReturnRows(sqlmock.NewRows([]string{"ExternalID", "ID"}))
+	stream = &importStream{requests: []*v1.ImportRequest{{
+		Api:    "v1",
+		Format: v1.DataFormat_CSV,
+		DryRun: true,
+		Data:   []byte("title,externalId,reminder\nfrom csv,x," + reminder.Format(time.RFC3339) + "\ntoo,few\nbad \"quote,y,\n"),
+	}}}
+	if err := toDoServer.Import(stream); err != nil {
+		t.Fatalf("toDoServiceServer.Import() error =%v", err)
+	}
+	if got := stream.resp; !got.DryRun || got.Created != 1 || got.Invalid != 2 || got.Results[0].Id != 0 {
+		t.Errorf("toDoServiceServer.Import() =%v, want 1 record to be created and 2 invalid in dry run", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,214 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// DefaultTrashRetention 是回收站中的 ToDo 被永久删除之前保留的时间
+	DefaultTrashRetention = 30 * 24 * time.Hour
+
+	// purgeInterval 是后台清理回收站的间隔
+	purgeInterval = time.Hour
+	// purgeBatchSize 是每条 DELETE 语句最多删除的行数，避免长时间锁表
+	purgeBatchSize = 1000
+)
+
+// Undelete 把回收站中的 ToDo 恢复
+func (t *toDoServiceServer) Undelete(ctx context.Context, in *v1.UndeleteRequest) (*v1.UndeleteResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	old, err := lockTrashed(ctx, tx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, "UPDATE ToDo SET `DeleteTime`=NULL WHERE `ID`=? AND `DeleteTime` IS NOT NULL", in.Id)
+	if err != nil {
+		return nil, dbError(ctx, "failed to undelete ToDo", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, dbError(ctx, "failed to retrieve rows affected value", err)
+	}
+
+	todo := proto.Clone(old).(*v1.ToDo)
+	todo.DeleteTime = nil
+	if err := recordHistory(ctx, tx, principal(ctx), historyEntry{v1.ToDoEvent_UNDELETED, old, todo}); err != nil {
+		return nil, dbError(ctx, "failed to insert into ToDoHistory", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit transaction", err)
+	}
+
+	t.hub.Publish(v1.ToDoEvent_UNDELETED, todo)
+
+	return &v1.UndeleteResponse{
+		Api:       apiVersion,
+		Undeleted: rows,
+	}, nil
+}
+
+// Purge 永久删除回收站中的 ToDo，不在回收站中的 ToDo 需要先 Delete
+func (t *toDoServiceServer) Purge(ctx context.Context, in *v1.PurgeRequest) (*v1.PurgeResponse, error) {
+	if err := t.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	old, err := lockTrashed(ctx, tx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM ToDo WHERE `ID`=? AND `DeleteTime` IS NOT NULL", in.Id)
+	if err != nil {
+		return nil, dbError(ctx, "failed to purge ToDo", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, dbError(ctx, "failed to retrieve rows affected value", err)
+	}
+
+	if err := recordHistory(ctx, tx, principal(ctx), historyEntry{v1.ToDoEvent_PURGED, old, nil}); err != nil {
+		return nil, dbError(ctx, "failed to insert into ToDoHistory", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dbError(ctx, "failed to commit transaction", err)
+	}
+
+	t.hub.Publish(v1.ToDoEvent_PURGED, &v1.ToDo{Id: in.Id})
+
+	return &v1.PurgeResponse{
+		Api:    apiVersion,
+		Purged: rows,
+	}, nil
+}
+
+// lockTrashed 锁定并读取回收站中的 ToDo，不在回收站中时返回 NotFound
+func lockTrashed(ctx context.Context, tx *sql.Tx, id int64) (*v1.ToDo, error) {
+	todo, err := lockToDo(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if todo.DeleteTime == nil {
+		return nil, status.Errorf(codes.NotFound, "ToDo with ID='%d' is not found in trash", id)
+	}
+	return todo, nil
+}
+
+// RunPurger 定期永久删除在回收站中超过 retention 的 ToDo，直到 ctx 被取消
+func RunPurger(ctx context.Context, db *sql.DB, hub *Hub, retention time.Duration) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := purge(ctx, db, hub, time.Now().UTC().Add(-retention)); err != nil {
+			log.Printf("failed to purge trash: %v", err)
+		} else if n > 0 {
+			log.Printf("purged %d ToDo from trash", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purge 分批永久删除 before 之前被删除的 ToDo，返回删除的数量
+func purge(ctx context.Context, db *sql.DB, hub *Hub, before time.Time) (int64, error) {
+	var total int64
+	for {
+		ids, err := purgeBatch(ctx, db, before)
+		if err != nil {
+			return total, err
+		}
+
+		total += int64(len(ids))
+		for _, id := range ids {
+			hub.Publish(v1.ToDoEvent_PURGED, &v1.ToDo{Id: id})
+		}
+
+		if len(ids) < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// purgeBatch 在一个事务中锁定并删除最多 purgeBatchSize 个 ToDo，返回删除的 ID
+func purgeBatch(ctx context.Context, db *sql.DB, before time.Time) ([]int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT "+toDoColumns+" FROM ToDo WHERE `DeleteTime` < ? LIMIT ? FOR UPDATE", before, purgeBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	var args []interface{}
+	var entries []historyEntry
+	for rows.Next() {
+		todo, err := scanToDo(ctx, rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, todo.Id)
+		args = append(args, todo.Id)
+		entries = append(entries, historyEntry{v1.ToDoEvent_PURGED, todo, nil})
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM ToDo WHERE `ID` IN ("+placeholders(len(ids), "?")+")", args...); err != nil {
+		return nil, err
+	}
+
+	if err := recordHistory(ctx, tx, systemPrincipal, entries...); err != nil {
+		return nil, err
+	}
+
+	return ids, tx.Commit()
+}
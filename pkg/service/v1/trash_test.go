@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+func TestDeleteMovesToTrash(t *testing.T) {
+	ctx := context.Background()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	hub := NewHub(DefaultHubHistory)
+	sub, _, _ := hub.Subscribe(0)
+	toDoServer := NewToDoServiceServer(db, hub, DefaultIdempotencyWindow, "")
+	timeNow := time.Now().In(time.UTC)
+
+	columns := []string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID`=\\? AND `DeleteTime` IS NULL FOR UPDATE").WithArgs(1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, "title", "", timeNow, nil, "PENDING", nil, "", ""))
+	mock.ExpectExec("UPDATE ToDo SET `DeleteTime`=\\? WHERE `ID`=\\? AND `DeleteTime` IS NULL").WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO ToDoHistory").WithArgs(1, "DELETED", sqlmock.AnyArg(), sqlmock.AnyArg(), anonymous, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	got, err := toDoServer.Delete(ctx, &v1.DeleteRequest{Api: "v1", Id: 1})
+	if err != nil || got.Deleted != 1 {
+		t.Fatalf("toDoServiceServer.Delete() =%v, error =%v", got, err)
+	}
+
+	ev := <-sub.C
+	if ev.Type != v1.ToDoEvent_DELETED || ev.ToDo.DeleteTime == nil {
+		t.Errorf("published event =%v, want DELETED with deleteTime", ev)
+	}
+
+	// 不在回收站中的 ToDo 不能恢复
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `ID`=\\? FOR UPDATE").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, "title", "", timeNow, nil, "PENDING", nil, "", ""))
+	mock.ExpectRollback()
+
+	if _, err := toDoServer.Undelete(ctx, &v1.UndeleteRequest{Api: "v1", Id: 2}); status.Code(err) != codes.NotFound {
+		t.Errorf("toDoServiceServer.Undelete() error =%v, want NotFound", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	before := time.Now().UTC()
+	deleteTime := before.Add(-time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM ToDo WHERE `DeleteTime` < \\? LIMIT \\? FOR UPDATE").WithArgs(before, purgeBatchSize).
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "Title", "Description", "Reminder", "DeleteTime", "ReminderStatus", "ReminderDeliveryTime", "Recurrence", "TimeZone"}).
+			AddRow(3, "title 3", "", before, deleteTime, "PENDING", nil, "", "").
+			AddRow(4, "title 4", "", before, deleteTime, "PENDING", nil, "", ""))
+	mock.ExpectExec("DELETE FROM ToDo WHERE `ID` IN \\(\\?,\\?\\)").WithArgs(3, 4).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("INSERT INTO ToDoHistory").
+		WithArgs(3, "PURGED", sqlmock.AnyArg(), nil, systemPrincipal, sqlmock.AnyArg(), 4, "PURGED", sqlmock.AnyArg(), nil, systemPrincipal, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	expectNoWebhooks(mock)
+	mock.ExpectCommit()
+
+	n, err := purge(context.Background(), db, NewHub(DefaultHubHistory), before)
+	if err != nil || n != 2 {
+		t.Fatalf("purge() =%d, error =%v, want 2", n, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
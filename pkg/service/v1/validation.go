@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// title 的最大长度(字符数)
+	maxTitleLength = 200
+	// description 的最大长度(字符数)
+	maxDescriptionLength = 1024
+	// recurrence 的最大长度(字符数)
+	maxRecurrenceLength = 1024
+	// timeZone 的最大长度(字符数)
+	maxTimeZoneLength = 64
+
+	// reminder 允许的最早时间(相对于当前时间)
+	maxReminderPast = 365 * 24 * time.Hour
+	// reminder 允许的最晚时间(相对于当前时间)
+	maxReminderFuture = 10 * 365 * 24 * time.Hour
+)
+
+// fieldRule 描述一条字段校验规则: check 返回空字符串表示校验通过，否则返回违规描述
+type fieldRule struct {
+	field string
+	check func(todo *v1.ToDo) string
+}
+
+// toDoRules 是 ToDo 的校验规则，字段名相对于 ToDo 消息
+var toDoRules = []fieldRule{
+	{"title", func(todo *v1.ToDo) string {
+		return required(todo.Title)
+	}},
+	{"title", func(todo *v1.ToDo) string {
+		return maxLength(todo.Title, maxTitleLength)
+	}},
+	{"description", func(todo *v1.ToDo) string {
+		return maxLength(todo.Description, maxDescriptionLength)
+	}},
+	{"reminder", validReminder},
+	{"recurrence", func(todo *v1.ToDo) string {
+		return maxLength(todo.Recurrence, maxRecurrenceLength)
+	}},
+	{"timeZone", func(todo *v1.ToDo) string {
+		return maxLength(todo.TimeZone, maxTimeZoneLength)
+	}},
+	{"timeZone", validTimeZone},
+	{"recurrence", func(todo *v1.ToDo) string {
+		if len(todo.Recurrence) == 0 || todo.Reminder == nil || len(validTimeZone(todo)) > 0 {
+			return ""
+		}
+		if _, err := parseRecurrence(todo); err != nil {
+			return "invalid rule: " + err.Error()
+		}
+		return ""
+	}},
+}
+
+// reminderRangeRule 检查 reminder 在允许的时间范围内
+// 修改 ToDo 时只在 reminder 改变时检查 (见 checkReminderChange)，提醒时间早已过去的 ToDo 仍然可以原样保存
+var reminderRangeRule = fieldRule{"reminder", func(todo *v1.ToDo) string {
+	return reminderInRange(todo, time.Now())
+}}
+
+// createToDoRules 是创建 ToDo 时的校验规则
+var createToDoRules = append(append([]fieldRule{}, toDoRules...), reminderRangeRule)
+
+// updateToDoRules 是 Update 时额外的 ToDo 校验规则
+var updateToDoRules = []fieldRule{
+	{"id", func(todo *v1.ToDo) string {
+		if todo.Id <= 0 {
+			return "must be a positive number"
+		}
+		return ""
+	}},
+}
+
+func required(s string) string {
+	if len(s) == 0 {
+		return "must not be empty"
+	}
+	return ""
+}
+
+func maxLength(s string, max int) string {
+	if utf8.RuneCountInString(s) > max {
+		return fmt.Sprintf("must not be longer than %d characters", max)
+	}
+	return ""
+}
+
+func validReminder(todo *v1.ToDo) string {
+	if todo.Reminder == nil {
+		return "must not be empty"
+	}
+	if _, err := ptypes.Timestamp(todo.Reminder); err != nil {
+		return "invalid format: " + err.Error()
+	}
+	return ""
+}
+
+func reminderInRange(todo *v1.ToDo, now time.Time) string {
+	if desc := validReminder(todo); len(desc) > 0 {
+		return desc
+	}
+	reminder, _ := ptypes.Timestamp(todo.Reminder)
+	if reminder.Before(now.Add(-maxReminderPast)) {
+		return fmt.Sprintf("must not be more than %v in the past", maxReminderPast)
+	}
+	if reminder.After(now.Add(maxReminderFuture)) {
+		return fmt.Sprintf("must not be more than %v in the future", maxReminderFuture)
+	}
+	return ""
+}
+
+// validTimeZone 检查 timeZone 是 IANA 时区数据库中的名字，空字符串表示 UTC
+func validTimeZone(todo *v1.ToDo) string {
+	if _, err := location(todo); err != nil {
+		return "unknown time zone, must be an IANA name such as Europe/Berlin"
+	}
+	return ""
+}
+
+// applyRules 对 todo 执行 rules，违规的字段以 prefix 为前缀追加到 violations
+func applyRules(violations []*errdetails.BadRequest_FieldViolation, prefix string, todo *v1.ToDo, rules []fieldRule) []*errdetails.BadRequest_FieldViolation {
+	for _, r := range rules {
+		if desc := r.check(todo); len(desc) > 0 {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       prefix + r.field,
+				Description: desc,
+			})
+		}
+	}
+	return violations
+}
+
+// invalidArgument 把字段违规信息转换为带 google.rpc.BadRequest 详情的 InvalidArgument 错误
+func invalidArgument(violations []*errdetails.BadRequest_FieldViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	st := status.New(codes.InvalidArgument, "invalid request: "+violations[0].Field+" "+violations[0].Description)
+	ds, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return st.Err()
+	}
+	return ds.Err()
+}
+
+// validateCreate 校验 CreateRequest
+func validateCreate(in *v1.CreateRequest) error {
+	var violations []*errdetails.BadRequest_FieldViolation
+	if in.ToDo == nil {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "toDo", Description: "must not be empty"})
+	} else {
+		violations = applyRules(violations, "toDo.", in.ToDo, createToDoRules)
+	}
+	return invalidArgument(violations)
+}
+
+// validateUpdate 校验 UpdateRequest，reminder 的范围在读出修改前的 ToDo 后由 checkReminderChange 检查
+func validateUpdate(in *v1.UpdateRequest) error {
+	var violations []*errdetails.BadRequest_FieldViolation
+	if in.ToDo == nil {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "toDo", Description: "must not be empty"})
+	} else {
+		violations = applyRules(violations, "toDo.", in.ToDo, updateToDoRules)
+		violations = applyRules(violations, "toDo.", in.ToDo, toDoRules)
+	}
+	return invalidArgument(violations)
+}
+
+// checkReminderChange 在 reminder 被修改时检查新的 reminder 是否在允许的范围内，field 是 reminder 字段的名字
+func checkReminderChange(old, new *v1.ToDo, field string) *errdetails.BadRequest_FieldViolation {
+	if proto.Equal(old.Reminder, new.Reminder) {
+		return nil
+	}
+	if desc := reminderInRange(new, time.Now()); len(desc) > 0 {
+		return &errdetails.BadRequest_FieldViolation{Field: field, Description: desc}
+	}
+	return nil
+}
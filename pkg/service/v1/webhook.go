@@ -0,0 +1,370 @@
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// url 的最大长度
+	maxWebhookURLLength = 2048
+	// secret 的最大长度
+	maxWebhookSecretLength = 255
+
+	// defaultDeliveriesLimit 是 ListWebhookDeliveries 默认返回的条数
+	defaultDeliveriesLimit = 100
+	// maxDeliveriesLimit 是 ListWebhookDeliveries 最多返回的条数
+	maxDeliveriesLimit = 1000
+
+	// webhookDeliveryColumns 是 scanWebhookDelivery 读取的列
+	webhookDeliveryColumns = "`ID`, `WebhookID`, `Payload`, `Status`, `Attempts`, `ResponseCode`, `Error`, `CreateTime`, `NextAttemptTime`, `DeliveryTime`"
+)
+
+type webhookServiceServer struct {
+	server
+}
+
+// NewWebhookServiceServer 创建 webhook 订阅管理服务
+func NewWebhookServiceServer(db *sql.DB) v1.WebhookServiceServer {
+	return &webhookServiceServer{server{db}}
+}
+
+// CreateWebhook 注册一个 webhook，没有指定 secret 时生成一个随机的 secret 并在响应中返回
+func (w *webhookServiceServer) CreateWebhook(ctx context.Context, in *v1.CreateWebhookRequest) (*v1.CreateWebhookResponse, error) {
+	if err := w.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	if err := validateWebhook(in.Webhook); err != nil {
+		return nil, err
+	}
+
+	secret := in.Webhook.Secret
+	if len(secret) == 0 {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, status.Error(codes.Internal, "failed to generate secret")
+		}
+		secret = hex.EncodeToString(b)
+	}
+
+	conn, err := w.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	res, err := conn.ExecContext(ctx, "INSERT INTO Webhook(`URL`, `Events`, `Secret`, `CreateTime`) VALUES (?,?,?,?)",
+		in.Webhook.Url, formatEvents(in.Webhook.Events), secret, time.Now().UTC())
+	if err != nil {
+		return nil, dbError(ctx, "failed to insert into Webhook", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, dbError(ctx, "failed to retrieve id for created Webhook", err)
+	}
+
+	return &v1.CreateWebhookResponse{
+		Api:    apiVersion,
+		Id:     id,
+		Secret: secret,
+	}, nil
+}
+
+// ListWebhooks 返回所有 webhook，secret 不会被返回
+func (w *webhookServiceServer) ListWebhooks(ctx context.Context, in *v1.ListWebhooksRequest) (*v1.ListWebhooksResponse, error) {
+	if err := w.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	conn, err := w.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(ctx, "SELECT `ID`, `URL`, `Events`, `CreateTime` FROM Webhook ORDER BY `ID`")
+	if err != nil {
+		return nil, dbError(ctx, "failed to select from Webhook", err)
+	}
+
+	defer rows.Close()
+
+	var list []*v1.Webhook
+	for rows.Next() {
+		var hook v1.Webhook
+		var events string
+		var createTime time.Time
+		if err := rows.Scan(&hook.Id, &hook.Url, &events, &createTime); err != nil {
+			return nil, dbError(ctx, "failed to retrieve field values from Webhook row", err)
+		}
+		hook.Events = parseEvents(events)
+		if hook.CreateTime, err = ptypes.TimestampProto(createTime); err != nil {
+			return nil, dbError(ctx, "createTime field has invalid format", err)
+		}
+		list = append(list, &hook)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(ctx, "failed to retrieve data from Webhook", err)
+	}
+
+	return &v1.ListWebhooksResponse{
+		Api:      apiVersion,
+		Webhooks: list,
+	}, nil
+}
+
+// DeleteWebhook 删除 webhook 和它的发送记录
+func (w *webhookServiceServer) DeleteWebhook(ctx context.Context, in *v1.DeleteWebhookRequest) (*v1.DeleteWebhookResponse, error) {
+	if err := w.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	conn, err := w.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	res, err := conn.ExecContext(ctx, "DELETE FROM Webhook WHERE `ID`=?", in.Id)
+	if err != nil {
+		return nil, dbError(ctx, "failed to delete Webhook", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, dbError(ctx, "failed to retrieve rows affected value", err)
+	}
+
+	if rows == 0 {
+		return nil, status.Errorf(codes.NotFound, "Webhook with ID='%d' is not found", in.Id)
+	}
+
+	return &v1.DeleteWebhookResponse{
+		Api:     apiVersion,
+		Deleted: rows,
+	}, nil
+}
+
+// ListWebhookDeliveries 从新到旧返回 webhook 的发送记录，deadOnly 为 true 时只返回放弃重试的记录
+func (w *webhookServiceServer) ListWebhookDeliveries(ctx context.Context, in *v1.ListWebhookDeliveriesRequest) (*v1.ListWebhookDeliveriesResponse, error) {
+	if err := w.checkAPI(in.Api); err != nil {
+		return nil, err
+	}
+
+	limit := int(in.Limit)
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+	if limit > maxDeliveriesLimit {
+		limit = maxDeliveriesLimit
+	}
+
+	conn, err := w.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	var exists int
+	err = conn.QueryRowContext(ctx, "SELECT 1 FROM Webhook WHERE `ID`=?", in.WebhookId).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "Webhook with ID='%d' is not found", in.WebhookId)
+	}
+	if err != nil {
+		return nil, dbError(ctx, "failed to select from Webhook", err)
+	}
+
+	query := "SELECT " + webhookDeliveryColumns + " FROM WebhookDelivery WHERE `WebhookID`=?"
+	args := []interface{}{in.WebhookId}
+	if in.DeadOnly {
+		query += " AND `Status`=?"
+		args = append(args, v1.WebhookDelivery_DEAD.String())
+	}
+	args = append(args, limit)
+
+	rows, err := conn.QueryContext(ctx, query+" ORDER BY `ID` DESC LIMIT ?", args...)
+	if err != nil {
+		return nil, dbError(ctx, "failed to select from WebhookDelivery", err)
+	}
+
+	defer rows.Close()
+
+	var list []*v1.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbError(ctx, "failed to retrieve data from WebhookDelivery", err)
+	}
+
+	return &v1.ListWebhookDeliveriesResponse{
+		Api:        apiVersion,
+		Deliveries: list,
+	}, nil
+}
+
+// scanWebhookDelivery 从当前行读取发送记录，查询的列必须是 webhookDeliveryColumns
+func scanWebhookDelivery(ctx context.Context, rows *sql.Rows) (*v1.WebhookDelivery, error) {
+	var d v1.WebhookDelivery
+	var payload, deliveryStatus string
+	var createTime, nextAttemptTime time.Time
+	var deliveryTime mysql.NullTime
+	if err := rows.Scan(&d.Id, &d.WebhookId, &payload, &deliveryStatus, &d.Attempts, &d.ResponseCode, &d.Error, &createTime, &nextAttemptTime, &deliveryTime); err != nil {
+		return nil, dbError(ctx, "failed to retrieve field values from WebhookDelivery row", err)
+	}
+
+	d.Event = &v1.ToDoEvent{}
+	if err := jsonpb.UnmarshalString(payload, d.Event); err != nil {
+		return nil, dbError(ctx, "payload field has invalid format", err)
+	}
+	d.Status = v1.WebhookDelivery_Status(v1.WebhookDelivery_Status_value[deliveryStatus])
+
+	var err error
+	if d.CreateTime, err = ptypes.TimestampProto(createTime); err != nil {
+		return nil, dbError(ctx, "createTime field has invalid format", err)
+	}
+	if d.NextAttemptTime, err = ptypes.TimestampProto(nextAttemptTime); err != nil {
+		return nil, dbError(ctx, "nextAttemptTime field has invalid format", err)
+	}
+	if deliveryTime.Valid {
+		if d.DeliveryTime, err = ptypes.TimestampProto(deliveryTime.Time); err != nil {
+			return nil, dbError(ctx, "deliveryTime field has invalid format", err)
+		}
+	}
+
+	return &d, nil
+}
+
+// validateWebhook 校验 CreateWebhookRequest 中的 webhook
+func validateWebhook(hook *v1.Webhook) error {
+	if hook == nil {
+		return invalidArgument([]*errdetails.BadRequest_FieldViolation{{Field: "webhook", Description: "must not be empty"}})
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	violation := func(field, desc string) {
+		if len(desc) > 0 {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "webhook." + field, Description: desc})
+		}
+	}
+
+	violation("url", webhookURL(hook.Url))
+	violation("secret", maxLength(hook.Secret, maxWebhookSecretLength))
+	for i, e := range hook.Events {
+		if _, ok := v1.ToDoEvent_Type_name[int32(e)]; !ok || e == v1.ToDoEvent_UNKNOWN {
+			violation(fmt.Sprintf("events[%d]", i), "must be a known event type")
+		}
+	}
+
+	return invalidArgument(violations)
+}
+
+func webhookURL(s string) string {
+	if desc := required(s); len(desc) > 0 {
+		return desc
+	}
+	if utf8.RuneCountInString(s) > maxWebhookURLLength {
+		return maxLength(s, maxWebhookURLLength)
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Hostname()) == 0 {
+		return "must be an absolute http or https URL"
+	}
+	// 拒绝明显指向内网的地址; 域名解析到的地址在连接时由 webhookDialer 检查
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "must not point to a loopback, private or link-local address"
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return "must not point to a loopback, private or link-local address"
+	}
+	return ""
+}
+
+// publicIP 检查 webhook 是否可以连接 ip: 回环、私有、链路本地 (包括云服务的元数据地址 169.254.169.254)、
+// 运营商级 NAT、组播和未指定地址都不可以连接
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range nonPublicNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// nonPublicNetworks 是私有地址 (RFC 1918、RFC 4193) 和运营商级 NAT 使用的地址 (RFC 6598)
+// net.IP.IsPrivate 需要 Go 1.17，go.mod 声明的是 go 1.12
+var nonPublicNetworks = []*net.IPNet{
+	{IP: net.IPv4(10, 0, 0, 0), Mask: net.CIDRMask(8, 32)},
+	{IP: net.IPv4(172, 16, 0, 0), Mask: net.CIDRMask(12, 32)},
+	{IP: net.IPv4(192, 168, 0, 0), Mask: net.CIDRMask(16, 32)},
+	{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)},
+	{IP: net.IP{0xfc, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, Mask: net.CIDRMask(7, 128)},
+}
+
+// formatEvents 把订阅的事件类型保存为逗号分隔的名字，空字符串表示订阅所有事件
+func formatEvents(events []v1.ToDoEvent_Type) string {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = e.String()
+	}
+	return strings.Join(names, ",")
+}
+
+// parseEvents 是 formatEvents 的逆操作
+func parseEvents(s string) []v1.ToDoEvent_Type {
+	if len(s) == 0 {
+		return nil
+	}
+	var events []v1.ToDoEvent_Type
+	for _, name := range strings.Split(s, ",") {
+		events = append(events, v1.ToDoEvent_Type(v1.ToDoEvent_Type_value[name]))
+	}
+	return events
+}
+
+// subscribed 判断订阅了 events 的 webhook 是否应该收到 typ 类型的事件
+func subscribed(events []v1.ToDoEvent_Type, typ v1.ToDoEvent_Type) bool {
+	if len(events) == 0 {
+		return true
+	}
+	for _, e := range events {
+		if e == typ {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,342 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+const (
+	// webhookInterval 是检查待发送 webhook 请求的间隔
+	webhookInterval = 5 * time.Second
+	// webhookBatchSize 是一次认领的最多 webhook 请求数
+	webhookBatchSize = 50
+	// webhookLease 是认领的请求等待发送结果的时间，必须大于一批请求的发送时间
+	webhookLease = 10 * time.Minute
+
+	// maxWebhookAttempts 是放弃重试之前的最大尝试次数，之后发送记录成为死信
+	maxWebhookAttempts = 10
+	// webhookBaseBackoff 是第一次重试前的等待时间，之后每次翻倍
+	webhookBaseBackoff = 30 * time.Second
+	// webhookMaxBackoff 是两次重试之间的最长等待时间
+	webhookMaxBackoff = 6 * time.Hour
+
+	// maxWebhookError 是发送记录中错误信息的最大长度
+	maxWebhookError = 1024
+
+	// WebhookSignatureHeader 是请求签名的 HTTP 头，值为 "sha256=" 加上 WebhookSignature 的结果
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	// WebhookTimestampHeader 是签名时间的 HTTP 头，值为 Unix 秒数，接收方可以用来拒绝重放的请求
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	// WebhookDeliveryHeader 是发送记录 ID 的 HTTP 头，重试时不变，接收方可以用来去重
+	WebhookDeliveryHeader = "X-Webhook-Delivery"
+)
+
+// WebhookSignature 返回 webhook 请求的签名: 用 secret 对 "timestamp.payload" 计算 HMAC-SHA256 的十六进制结果
+func WebhookSignature(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	io.WriteString(mac, timestamp+".")
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewWebhookClient 返回发送 webhook 请求的 HTTP 客户端，每个请求 (包括重定向) 的超时时间为 timeout
+// 客户端在连接前检查解析后的地址，不连接内网地址，防止 webhook 被用来访问内部服务 (SSRF)
+func NewWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: webhookDialControl,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// webhookDialControl 在连接前检查 DNS 解析后的地址，域名在创建 webhook 后改为解析到内网地址时也无法连接
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !publicIP(ip) {
+		return fmt.Errorf("webhook must not connect to %s", address)
+	}
+	return nil
+}
+
+// RunWebhooks 定期用 client 发送发送队列中到期的 webhook 请求，直到 ctx 被取消
+// 请求由 recordHistory 在变更的事务中加入队列，发送失败的请求按指数退避重试，多个副本可以同时运行
+func RunWebhooks(ctx context.Context, db *sql.DB, client *http.Client) {
+	ticker := time.NewTicker(webhookInterval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := deliverWebhooks(ctx, db, client, time.Now().UTC()); err != nil {
+			log.Printf("failed to deliver webhooks: %v", err)
+		} else if n > 0 {
+			log.Printf("sent %d webhook requests", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enqueueWebhooks 为订阅了 events 的每个 webhook 创建待发送的记录，必须和产生事件的变更在同一个事务中执行
+func enqueueWebhooks(ctx context.Context, q dbtx, events ...*v1.ToDoEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows, err := q.QueryContext(ctx, "SELECT `ID`, `Events` FROM Webhook")
+	if err != nil {
+		return err
+	}
+
+	type webhook struct {
+		id     int64
+		events []v1.ToDoEvent_Type
+	}
+	var hooks []webhook
+	for rows.Next() {
+		var id int64
+		var events string
+		if err := rows.Scan(&id, &events); err != nil {
+			rows.Close()
+			return err
+		}
+		hooks = append(hooks, webhook{id, parseEvents(events)})
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	var args []interface{}
+	for _, ev := range events {
+		var payload string
+		for _, hook := range hooks {
+			if !subscribed(hook.events, ev.Type) {
+				continue
+			}
+			if len(payload) == 0 {
+				if payload, err = (&jsonpb.Marshaler{}).MarshalToString(ev); err != nil {
+					return err
+				}
+			}
+			args = append(args, hook.id, payload, v1.WebhookDelivery_PENDING.String(), now, now)
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	_, err = q.ExecContext(ctx, "INSERT INTO WebhookDelivery(`WebhookID`, `Payload`, `Status`, `CreateTime`, `NextAttemptTime`) VALUES "+
+		placeholders(len(args)/5, "(?,?,?,?,?)"), args...)
+	return err
+}
+
+// deliverWebhooks 分批发送 now 之前到期的 webhook 请求，返回发送的数量
+// 每批请求先在一个短事务中认领，提交后再发送，最后在另一个事务中记录结果，发送期间不持有行锁
+func deliverWebhooks(ctx context.Context, db *sql.DB, client *http.Client, now time.Time) (int, error) {
+	lease := now.Add(webhookLease).Truncate(time.Second)
+
+	var total int
+	for {
+		pending, err := claimWebhooks(ctx, db, now, lease)
+		if err != nil {
+			return total, err
+		}
+
+		for _, p := range pending {
+			p.code, p.err = sendWebhook(ctx, client, p, now)
+			// 停止时不记录结果，租约过期后由下一次检查重新发送
+			if ctx.Err() != nil {
+				return total, ctx.Err()
+			}
+		}
+
+		if err := recordWebhooks(ctx, db, pending, now, lease); err != nil {
+			return total, err
+		}
+
+		total += len(pending)
+		if len(pending) < webhookBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// pendingDelivery 是一条已认领、等待发送的 webhook 请求
+type pendingDelivery struct {
+	id       int64
+	payload  string
+	attempts int32
+	url      string
+	secret   string
+
+	code int
+	err  error
+}
+
+// claimWebhooks 在一个事务中锁定最多 webhookBatchSize 条到期的记录，把下一次尝试的时间设置为 lease 后提交
+// 认领的记录在租约过期前不会被其它副本发送; 认领后进程退出的记录在租约过期后重新发送
+func claimWebhooks(ctx context.Context, db *sql.DB, now, lease time.Time) ([]*pendingDelivery, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT d.`ID`, d.`Payload`, d.`Attempts`, w.`URL`, w.`Secret` FROM WebhookDelivery d JOIN Webhook w ON w.`ID`=d.`WebhookID` "+
+		"WHERE d.`Status`=? AND d.`NextAttemptTime`<=? ORDER BY d.`NextAttemptTime` LIMIT ? FOR UPDATE OF d SKIP LOCKED",
+		v1.WebhookDelivery_PENDING.String(), now, webhookBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*pendingDelivery
+	for rows.Next() {
+		p := &pendingDelivery{}
+		if err := rows.Scan(&p.id, &p.payload, &p.attempts, &p.url, &p.secret); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		pending = append(pending, p)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	args := []interface{}{lease}
+	for _, p := range pending {
+		args = append(args, p.id)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE WebhookDelivery SET `NextAttemptTime`=? WHERE `ID` IN ("+placeholders(len(pending), "?")+")", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pending, tx.Commit()
+}
+
+// recordWebhooks 在一个事务中记录发送结果，租约过期后被其它副本重新认领的记录不再更新
+func recordWebhooks(ctx context.Context, db *sql.DB, pending []*pendingDelivery, now, lease time.Time) error {
+	if len(pending) == 0 {
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for _, p := range pending {
+		attempts := p.attempts + 1
+		if p.err == nil {
+			_, err = tx.ExecContext(ctx, "UPDATE WebhookDelivery SET `Status`=?, `Attempts`=?, `ResponseCode`=?, `Error`='', `DeliveryTime`=? WHERE `ID`=? AND `NextAttemptTime`=?",
+				v1.WebhookDelivery_DELIVERED.String(), attempts, p.code, now, p.id, lease)
+		} else {
+			s := v1.WebhookDelivery_PENDING
+			if attempts >= maxWebhookAttempts {
+				s = v1.WebhookDelivery_DEAD
+			}
+			msg := p.err.Error()
+			if len(msg) > maxWebhookError {
+				msg = msg[:maxWebhookError]
+			}
+			_, err = tx.ExecContext(ctx, "UPDATE WebhookDelivery SET `Status`=?, `Attempts`=?, `ResponseCode`=?, `Error`=?, `NextAttemptTime`=? WHERE `ID`=? AND `NextAttemptTime`=?",
+				s.String(), attempts, p.code, msg, now.Add(webhookBackoff(attempts)), p.id, lease)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// sendWebhook 发送一次签名的请求，返回响应状态码; 非 2xx 的响应作为错误返回
+func sendWebhook(ctx context.Context, client *http.Client, p *pendingDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, p.url, bytes.NewBufferString(p.payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(p.id, 10))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+WebhookSignature(p.secret, timestamp, []byte(p.payload)))
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookBackoff 返回第 attempts 次尝试失败后到下一次重试的等待时间
+func webhookBackoff(attempts int32) time.Duration {
+	return backoff(attempts, webhookBaseBackoff, webhookMaxBackoff)
+}
+
+// backoff 返回第 attempts 次尝试失败后到下一次重试的指数退避等待时间: 从 base 开始每次翻倍，最长为 max
+func backoff(attempts int32, base, max time.Duration) time.Duration {
+	d := base
+	for i := int32(1); i < attempts && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
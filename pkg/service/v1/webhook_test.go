@@ -0,0 +1,194 @@
+package v1
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+)
+
+func TestDeliverWebhooks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	payload := `{"sequence":"1","type":"CREATED","toDo":{"id":"1","title":"title"}}`
+	received := make(chan *http.Request, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := r.Header.Get(WebhookSignatureHeader), "sha256="+WebhookSignature("secret", r.Header.Get(WebhookTimestampHeader), body); got != want {
+			t.Errorf("%s =%s, want %s", WebhookSignatureHeader, got, want)
+		}
+		if string(body) != payload {
+			t.Errorf("payload =%s, want %s", body, payload)
+		}
+		received <- r
+	}))
+	defer receiver.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	now := time.Now().UTC()
+	lease := now.Add(webhookLease).Truncate(time.Second)
+
+	// 认领后提交，发送时不持有行锁
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM WebhookDelivery d JOIN Webhook w (.+) FOR UPDATE OF d SKIP LOCKED").
+		WithArgs("PENDING", now, webhookBatchSize).
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "Payload", "Attempts", "URL", "Secret"}).
+			AddRow(1, payload, 0, receiver.URL, "secret").
+			AddRow(2, payload, 2, failing.URL, "secret").
+			AddRow(3, payload, maxWebhookAttempts-1, failing.URL, "secret"))
+	mock.ExpectExec("UPDATE WebhookDelivery SET `NextAttemptTime`=\\? WHERE `ID` IN \\(\\?,\\?,\\?\\)").WithArgs(lease, 1, 2, 3).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE WebhookDelivery SET `Status`=\\?, `Attempts`=\\?, `ResponseCode`=\\?, `Error`='', `DeliveryTime`=\\? WHERE `ID`=\\? AND `NextAttemptTime`=\\?").
+		WithArgs("DELIVERED", 1, http.StatusOK, now, 1, lease).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE WebhookDelivery SET (.+) `NextAttemptTime`=\\? WHERE `ID`=\\? AND `NextAttemptTime`=\\?").
+		WithArgs("PENDING", 3, http.StatusInternalServerError, sqlmock.AnyArg(), now.Add(4*webhookBaseBackoff), 2, lease).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE WebhookDelivery SET (.+) `NextAttemptTime`=\\? WHERE `ID`=\\? AND `NextAttemptTime`=\\?").
+		WithArgs("DEAD", maxWebhookAttempts, http.StatusInternalServerError, sqlmock.AnyArg(), sqlmock.AnyArg(), 3, lease).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	n, err := deliverWebhooks(context.Background(), db, receiver.Client(), now)
+	if err != nil || n != 3 {
+		t.Fatalf("deliverWebhooks() =%d, error =%v, want 3", n, err)
+	}
+
+	if r := <-received; r.Header.Get(WebhookDeliveryHeader) != "1" {
+		t.Errorf("%s =%s, want 1", WebhookDeliveryHeader, r.Header.Get(WebhookDeliveryHeader))
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestEnqueueWebhooks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connectiong", err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT `ID`, `Events` FROM Webhook").
+		WillReturnRows(sqlmock.NewRows([]string{"ID", "Events"}).AddRow(1, "").AddRow(2, "DELETED,PURGED").AddRow(3, "CREATED"))
+	mock.ExpectExec("INSERT INTO WebhookDelivery").
+		WithArgs(1, sqlmock.AnyArg(), "PENDING", sqlmock.AnyArg(), sqlmock.AnyArg(), 3, sqlmock.AnyArg(), "PENDING", sqlmock.AnyArg(), sqlmock.AnyArg(),
+			1, sqlmock.AnyArg(), "PENDING", sqlmock.AnyArg(), sqlmock.AnyArg(), 2, sqlmock.AnyArg(), "PENDING", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 4))
+
+	created := &v1.ToDoEvent{Type: v1.ToDoEvent_CREATED, ToDo: &v1.ToDo{Id: 1, Title: "title"}}
+	deleted := &v1.ToDoEvent{Type: v1.ToDoEvent_DELETED, ToDo: &v1.ToDo{Id: 1, Title: "title"}}
+	if err := enqueueWebhooks(context.Background(), db, created, deleted); err != nil {
+		t.Fatalf("enqueueWebhooks() error =%v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// expectNoWebhooks 期望 recordHistory 在事务中查询订阅的 webhook，没有 webhook 时不加入发送队列
+func expectNoWebhooks(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("SELECT `ID`, `Events` FROM Webhook").WillReturnRows(sqlmock.NewRows([]string{"ID", "Events"}))
+}
+
+func TestCreateWebhookValidation(t *testing.T) {
+	server := NewWebhookServiceServer(nil)
+
+	tests := []*v1.Webhook{
+		nil,
+		{Url: ""},
+		{Url: "ftp://example.com/hook"},
+		{Url: "/relative"},
+		{Url: "https://example.com/hook", Events: []v1.ToDoEvent_Type{v1.ToDoEvent_UNKNOWN}},
+		// 内网地址
+		{Url: "http://localhost:8080/hook"},
+		{Url: "http://127.0.0.1/hook"},
+		{Url: "http://10.0.0.1/hook"},
+		{Url: "http://192.168.1.1/hook"},
+		{Url: "http://169.254.169.254/latest/meta-data/"},
+		{Url: "http://[::1]/hook"},
+		{Url: "http://[fd00:ec2::254]/hook"},
+		{Url: "http://0.0.0.0/hook"},
+	}
+	for _, hook := range tests {
+		_, err := server.CreateWebhook(context.Background(), &v1.CreateWebhookRequest{Api: "v1", Webhook: hook})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("webhookServiceServer.CreateWebhook(%v) error =%v, want InvalidArgument", hook, err)
+		}
+	}
+}
+
+func TestWebhookDialControl(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{"93.184.216.34:443", false},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", false},
+		{"127.0.0.1:80", true},
+		{"172.16.0.1:80", true},
+		{"169.254.169.254:80", true},
+		{"100.64.0.1:80", true},
+		{"[::ffff:127.0.0.1]:80", true},
+		{"[fe80::1]:80", true},
+		{"[fd12:3456::1]:80", true},
+		{"10.255.255.255:80", true},
+		{"172.31.255.255:80", true},
+		{"192.168.0.1:80", true},
+		// 私有地址段之外的相邻地址
+		{"172.32.0.1:443", false},
+		{"100.128.0.1:443", false},
+		{"[fe00::1]:443", false},
+	}
+	for _, tt := range tests {
+		if err := webhookDialControl("tcp", tt.address, nil); (err != nil) != tt.wantErr {
+			t.Errorf("webhookDialControl(%s) error =%v, wantErr %v", tt.address, err, tt.wantErr)
+		}
+	}
+
+	// 客户端在连接前拒绝内网地址
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("webhook client connected to a loopback address")
+	}))
+	defer receiver.Close()
+	if _, err := NewWebhookClient(time.Second).Get(receiver.URL); err == nil {
+		t.Errorf("NewWebhookClient().Get(%s) error = nil, want error", receiver.URL)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{1, webhookBaseBackoff},
+		{2, 2 * webhookBaseBackoff},
+		{5, 16 * webhookBaseBackoff},
+		{20, webhookMaxBackoff},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) =%v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
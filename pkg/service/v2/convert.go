@@ -0,0 +1,140 @@
+package v2
+
+import (
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v2"
+)
+
+// namePrefix 是 ToDo 资源名的前缀，资源名的格式为 todos/{id}
+const namePrefix = "todos/"
+
+// name 返回 v1 ID 对应的资源名
+func name(id int64) string {
+	return namePrefix + strconv.FormatInt(id, 10)
+}
+
+// parseName 返回资源名对应的 v1 ID
+func parseName(name string) (int64, error) {
+	if !strings.HasPrefix(name, namePrefix) {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid ToDo name '%s', must be todos/{id}", name)
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(name, namePrefix), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid ToDo name '%s', must be todos/{id}", name)
+	}
+	return id, nil
+}
+
+// reminderStatuses 把 v1 的提醒状态转换为 v2 的提醒状态，v2 的 0 值是 REMINDER_STATUS_UNSPECIFIED
+var reminderStatuses = map[v1.ToDo_ReminderStatus]v2.ToDo_ReminderStatus{
+	v1.ToDo_PENDING:   v2.ToDo_PENDING,
+	v1.ToDo_DELIVERED: v2.ToDo_DELIVERED,
+	v1.ToDo_FAILED:    v2.ToDo_FAILED,
+}
+
+func toV2(todo *v1.ToDo) *v2.ToDo {
+	return &v2.ToDo{
+		Name:                 name(todo.Id),
+		Title:                todo.Title,
+		Description:          todo.Description,
+		Reminder:             todo.Reminder,
+		Recurrence:           todo.Recurrence,
+		TimeZone:             todo.TimeZone,
+		ReminderStatus:       reminderStatuses[todo.ReminderStatus],
+		ReminderDeliveryTime: todo.ReminderDeliveryTime,
+		DeleteTime:           todo.DeleteTime,
+	}
+}
+
+// toV1 只转换可以修改的字段
+func toV1(todo *v2.ToDo) *v1.ToDo {
+	return &v1.ToDo{
+		Title:       todo.Title,
+		Description: todo.Description,
+		Reminder:    todo.Reminder,
+		Recurrence:  todo.Recurrence,
+		TimeZone:    todo.TimeZone,
+	}
+}
+
+// field 是 ToDo 的一个可修改字段
+type field struct {
+	// set 从 v2 的 ToDo 复制字段的值
+	set func(dst *v1.ToDo, src *v2.ToDo)
+	// empty 表示字段是 0 值
+	empty func(todo *v2.ToDo) bool
+}
+
+// mutableFields 是可以修改的字段，key 是 fieldKey 转换后的字段名
+var mutableFields = map[string]field{
+	"title": {
+		func(dst *v1.ToDo, src *v2.ToDo) { dst.Title = src.Title },
+		func(todo *v2.ToDo) bool { return len(todo.Title) == 0 },
+	},
+	"description": {
+		func(dst *v1.ToDo, src *v2.ToDo) { dst.Description = src.Description },
+		func(todo *v2.ToDo) bool { return len(todo.Description) == 0 },
+	},
+	"reminder": {
+		func(dst *v1.ToDo, src *v2.ToDo) { dst.Reminder = src.Reminder },
+		func(todo *v2.ToDo) bool { return todo.Reminder == nil },
+	},
+	"recurrence": {
+		func(dst *v1.ToDo, src *v2.ToDo) { dst.Recurrence = src.Recurrence },
+		func(todo *v2.ToDo) bool { return len(todo.Recurrence) == 0 },
+	},
+	"timezone": {
+		func(dst *v1.ToDo, src *v2.ToDo) { dst.TimeZone = src.TimeZone },
+		func(todo *v2.ToDo) bool { return len(todo.TimeZone) == 0 },
+	},
+}
+
+// ignoredFields 是出现在 updateMask 中也不会修改的字段: 资源名和只读字段
+var ignoredFields = map[string]bool{
+	"name":                 true,
+	"reminderstatus":       true,
+	"reminderdeliverytime": true,
+	"deletetime":           true,
+}
+
+// fieldKey 统一字段名的写法: gRPC 客户端使用 proto 字段名 timeZone，REST 网关转换为 Go 字段名 TimeZone
+func fieldKey(path string) string {
+	return strings.ToLower(strings.Replace(path, "_", "", -1))
+}
+
+// merge 把 src 中 paths 指定的字段复制到 dst
+// paths 为空时复制 src 中所有不为 0 值的字段，"*" 表示复制所有可以修改的字段
+func merge(dst *v1.ToDo, src *v2.ToDo, paths []string) error {
+	if len(paths) == 0 {
+		for _, f := range mutableFields {
+			if !f.empty(src) {
+				f.set(dst, src)
+			}
+		}
+		return nil
+	}
+
+	for _, path := range paths {
+		if path == "*" {
+			for _, f := range mutableFields {
+				f.set(dst, src)
+			}
+			continue
+		}
+		if ignoredFields[fieldKey(path)] {
+			continue
+		}
+		f, ok := mutableFields[fieldKey(path)]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "invalid updateMask path '%s'", path)
+		}
+		f.set(dst, src)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// Package v2 实现 v2 版本的 ToDoService
+//
+// v2 没有单独的存储: 请求转换为 v1 的请求交给 v1 的服务处理，两个版本共用数据、变更历史、幂等键和变更事件，
+// 迁移期间 v1 的客户端可以继续使用。v1 API 没有的分页查询和部分更新使用 Backend 的 ListPage 和 Patch。
+package v2
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v2"
+)
+
+const (
+	// defaultPageSize 是 ListToDos 没有指定 pageSize 时每页的数量
+	defaultPageSize = 50
+	// maxPageSize 是 ListToDos 每页最多的数量，更大的 pageSize 按 maxPageSize 处理
+	maxPageSize = 1000
+)
+
+// Backend 是 v2 读写数据使用的 v1 服务，service/v1 的 Server 实现了它
+type Backend interface {
+	v1.ToDoServiceServer
+
+	// ListPage 按 ID 顺序返回 ID 大于 after 的最多 limit 个 ToDo
+	ListPage(ctx context.Context, after int64, limit int, showDeleted bool) ([]*v1.ToDo, error)
+	// Patch 在一个事务中锁定 ToDo，用 change 修改读出的内容后保存，返回保存的内容
+	Patch(ctx context.Context, id int64, change func(todo *v1.ToDo) error) (*v1.ToDo, error)
+}
+
+type toDoServiceServer struct {
+	v1 Backend
+}
+
+// NewToDoServiceServer 创建通过 v1API 读写数据的 v2 ToDo 服务
+func NewToDoServiceServer(v1API Backend) v2.ToDoServiceServer {
+	return &toDoServiceServer{v1: v1API}
+}
+
+// GetToDo 读取 ToDo，回收站中的 ToDo 也可以读取
+func (s *toDoServiceServer) GetToDo(ctx context.Context, in *v2.GetToDoRequest) (*v2.ToDo, error) {
+	id, err := parseName(in.Name)
+	if err != nil {
+		return nil, err
+	}
+	return s.get(ctx, id)
+}
+
+func (s *toDoServiceServer) get(ctx context.Context, id int64) (*v2.ToDo, error) {
+	resp, err := s.v1.Read(ctx, &v1.ReadRequest{Id: id, ShowDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+	return toV2(resp.ToDo), nil
+}
+
+// ListToDos 按 ID 顺序分页返回 ToDo
+//
+// 每一页多读一个 ToDo 判断是否还有下一页，pageToken 是上一页最后一个 ToDo 的 ID
+func (s *toDoServiceServer) ListToDos(ctx context.Context, in *v2.ListToDosRequest) (*v2.ListToDosResponse, error) {
+	size := int(in.PageSize)
+	switch {
+	case size < 0:
+		return nil, status.Error(codes.InvalidArgument, "pageSize must not be negative")
+	case size == 0:
+		size = defaultPageSize
+	case size > maxPageSize:
+		size = maxPageSize
+	}
+
+	after, err := parsePageToken(in.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	todos, err := s.v1.ListPage(ctx, after, size+1, in.ShowDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v2.ListToDosResponse{}
+	if len(todos) > size {
+		todos = todos[:size]
+		resp.NextPageToken = pageToken(todos[size-1].Id)
+	}
+	for _, todo := range todos {
+		resp.ToDos = append(resp.ToDos, toV2(todo))
+	}
+	return resp, nil
+}
+
+// CreateToDo 创建 ToDo，toDo.name 和只读字段被忽略
+func (s *toDoServiceServer) CreateToDo(ctx context.Context, in *v2.CreateToDoRequest) (*v2.ToDo, error) {
+	if in.ToDo == nil {
+		return nil, status.Error(codes.InvalidArgument, "toDo is required")
+	}
+
+	resp, err := s.v1.Create(ctx, &v1.CreateRequest{ToDo: toV1(in.ToDo), RequestId: in.RequestId})
+	if err != nil {
+		return nil, err
+	}
+	return s.get(ctx, resp.Id)
+}
+
+// UpdateToDo 只修改 updateMask 中的字段
+//
+// v1 的 Update 替换整个 ToDo，这里在锁定 ToDo 的事务中合并要修改的字段，并发的修改不会互相覆盖
+func (s *toDoServiceServer) UpdateToDo(ctx context.Context, in *v2.UpdateToDoRequest) (*v2.ToDo, error) {
+	if in.ToDo == nil {
+		return nil, status.Error(codes.InvalidArgument, "toDo is required")
+	}
+	id, err := parseName(in.ToDo.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := in.UpdateMask.GetPaths()
+	if _, err := s.v1.Patch(ctx, id, func(todo *v1.ToDo) error { return merge(todo, in.ToDo, paths) }); err != nil {
+		return nil, err
+	}
+	return s.get(ctx, id)
+}
+
+// DeleteToDo 把 ToDo 移到回收站
+func (s *toDoServiceServer) DeleteToDo(ctx context.Context, in *v2.DeleteToDoRequest) (*v2.ToDo, error) {
+	id, err := parseName(in.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.v1.Delete(ctx, &v1.DeleteRequest{Id: id}); err != nil {
+		return nil, err
+	}
+	return s.get(ctx, id)
+}
+
+// UndeleteToDo 从回收站恢复 ToDo
+func (s *toDoServiceServer) UndeleteToDo(ctx context.Context, in *v2.UndeleteToDoRequest) (*v2.ToDo, error) {
+	id, err := parseName(in.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.v1.Undelete(ctx, &v1.UndeleteRequest{Id: id}); err != nil {
+		return nil, err
+	}
+	return s.get(ctx, id)
+}
+
+// pageToken 返回从 ID 大于 last 的 ToDo 开始的下一页
+func pageToken(last int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(last, 10)))
+}
+
+// parsePageToken 返回上一页最后一个 ToDo 的 ID，token 为空时返回 0
+func parsePageToken(token string) (int64, error) {
+	if len(token) == 0 {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, "invalid pageToken")
+	}
+	id, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, status.Error(codes.InvalidArgument, "invalid pageToken")
+	}
+	return id, nil
+}
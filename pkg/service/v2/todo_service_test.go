@@ -0,0 +1,257 @@
+package v2
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v1"
+	"go.xiaosongfu.com/go-grpc-http-rest-microservice-tutorial/pkg/api/v2"
+)
+
+// memoryServer 是保存在内存中的 v1 ToDoService
+type memoryServer struct {
+	v1.ToDoServiceServer
+
+	next  int64
+	todos map[int64]*v1.ToDo
+	// requests 记录收到的请求，检查 v2 没有设置 api 字段
+	requests []proto.Message
+}
+
+func newMemoryServer() *memoryServer {
+	return &memoryServer{todos: make(map[int64]*v1.ToDo)}
+}
+
+func (s *memoryServer) Create(_ context.Context, in *v1.CreateRequest) (*v1.CreateResponse, error) {
+	s.requests = append(s.requests, in)
+	s.next++
+	todo := *in.ToDo
+	todo.Id = s.next
+	s.todos[s.next] = &todo
+	return &v1.CreateResponse{Id: s.next}, nil
+}
+
+func (s *memoryServer) Read(_ context.Context, in *v1.ReadRequest) (*v1.ReadResponse, error) {
+	s.requests = append(s.requests, in)
+	todo, ok := s.todos[in.Id]
+	if !ok || (todo.DeleteTime != nil && !in.ShowDeleted) {
+		return nil, status.Errorf(codes.NotFound, "ToDo with ID='%d' is not found", in.Id)
+	}
+	copied := *todo
+	return &v1.ReadResponse{ToDo: &copied}, nil
+}
+
+func (s *memoryServer) ListPage(_ context.Context, after int64, limit int, showDeleted bool) ([]*v1.ToDo, error) {
+	var list []*v1.ToDo
+	for _, todo := range s.todos {
+		if todo.Id > after && (todo.DeleteTime == nil || showDeleted) {
+			list = append(list, todo)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (s *memoryServer) Patch(_ context.Context, id int64, change func(todo *v1.ToDo) error) (*v1.ToDo, error) {
+	todo, ok := s.todos[id]
+	if !ok || todo.DeleteTime != nil {
+		return nil, status.Errorf(codes.NotFound, "ToDo with ID='%d' is not found", id)
+	}
+	copied := *todo
+	if err := change(&copied); err != nil {
+		return nil, err
+	}
+	s.todos[id] = &copied
+	return &copied, nil
+}
+
+func (s *memoryServer) Update(_ context.Context, in *v1.UpdateRequest) (*v1.UpdateResponse, error) {
+	s.requests = append(s.requests, in)
+	todo := *in.ToDo
+	s.todos[todo.Id] = &todo
+	return &v1.UpdateResponse{Updated: 1}, nil
+}
+
+func (s *memoryServer) Delete(_ context.Context, in *v1.DeleteRequest) (*v1.DeleteResponse, error) {
+	s.requests = append(s.requests, in)
+	s.todos[in.Id].DeleteTime = ptypes.TimestampNow()
+	return &v1.DeleteResponse{Deleted: 1}, nil
+}
+
+func (s *memoryServer) Undelete(_ context.Context, in *v1.UndeleteRequest) (*v1.UndeleteResponse, error) {
+	s.requests = append(s.requests, in)
+	s.todos[in.Id].DeleteTime = nil
+	return &v1.UndeleteResponse{Undeleted: 1}, nil
+}
+
+func TestToDoServiceServer(t *testing.T) {
+	ctx := context.Background()
+	v1API := newMemoryServer()
+	s := NewToDoServiceServer(v1API)
+
+	reminder := ptypes.TimestampNow()
+	created, err := s.CreateToDo(ctx, &v2.CreateToDoRequest{
+		ToDo:      &v2.ToDo{Name: "todos/100", Title: "title", Description: "description", Reminder: reminder, ReminderStatus: v2.ToDo_FAILED},
+		RequestId: "req-1",
+	})
+	if err != nil {
+		t.Fatalf("CreateToDo() error = %v", err)
+	}
+	want := &v2.ToDo{Name: "todos/1", Title: "title", Description: "description", Reminder: reminder, ReminderStatus: v2.ToDo_PENDING}
+	if !proto.Equal(created, want) {
+		t.Errorf("CreateToDo() = %v, want %v", created, want)
+	}
+	if req := v1API.requests[0].(*v1.CreateRequest); req.RequestId != "req-1" || len(req.Api) > 0 {
+		t.Errorf("v1 CreateRequest = %v, want request ID and no api", req)
+	}
+
+	// 只修改 updateMask 中的字段，REST 网关传入的是 Go 字段名
+	updated, err := s.UpdateToDo(ctx, &v2.UpdateToDoRequest{
+		ToDo:       &v2.ToDo{Name: "todos/1", Title: "new title", TimeZone: "Asia/Shanghai"},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"title", "TimeZone", "reminderStatus"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateToDo() error = %v", err)
+	}
+	if updated.Title != "new title" || updated.TimeZone != "Asia/Shanghai" || updated.Description != "description" {
+		t.Errorf("UpdateToDo() = %v, want title and time zone changed", updated)
+	}
+
+	// 没有 updateMask 时只修改不为空的字段
+	updated, err = s.UpdateToDo(ctx, &v2.UpdateToDoRequest{ToDo: &v2.ToDo{Name: "todos/1", Description: "new description"}})
+	if err != nil {
+		t.Fatalf("UpdateToDo() error = %v", err)
+	}
+	if updated.Title != "new title" || updated.Description != "new description" {
+		t.Errorf("UpdateToDo() = %v, want only description changed", updated)
+	}
+
+	// "*" 替换所有可修改的字段
+	updated, err = s.UpdateToDo(ctx, &v2.UpdateToDoRequest{
+		ToDo:       &v2.ToDo{Name: "todos/1", Title: "replaced"},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"*"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateToDo() error = %v", err)
+	}
+	if updated.Title != "replaced" || len(updated.Description) > 0 || updated.Reminder != nil {
+		t.Errorf("UpdateToDo() = %v, want all fields replaced", updated)
+	}
+
+	deleted, err := s.DeleteToDo(ctx, &v2.DeleteToDoRequest{Name: "todos/1"})
+	if err != nil || deleted.DeleteTime == nil {
+		t.Errorf("DeleteToDo() = %v, %v, want ToDo with deleteTime", deleted, err)
+	}
+	if got, err := s.GetToDo(ctx, &v2.GetToDoRequest{Name: "todos/1"}); err != nil || got.DeleteTime == nil {
+		t.Errorf("GetToDo() = %v, %v, want deleted ToDo", got, err)
+	}
+	undeleted, err := s.UndeleteToDo(ctx, &v2.UndeleteToDoRequest{Name: "todos/1"})
+	if err != nil || undeleted.DeleteTime != nil {
+		t.Errorf("UndeleteToDo() = %v, %v, want ToDo without deleteTime", undeleted, err)
+	}
+}
+
+func TestToDoServiceServerErrors(t *testing.T) {
+	ctx := context.Background()
+	s := NewToDoServiceServer(newMemoryServer())
+
+	for _, name := range []string{"", "1", "todos/", "todos/abc", "todos/0", "users/1"} {
+		if _, err := s.GetToDo(ctx, &v2.GetToDoRequest{Name: name}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("GetToDo(%q) error = %v, want InvalidArgument", name, err)
+		}
+	}
+	if _, err := s.GetToDo(ctx, &v2.GetToDoRequest{Name: "todos/1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetToDo() error = %v, want NotFound", err)
+	}
+
+	if _, err := s.CreateToDo(ctx, &v2.CreateToDoRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CreateToDo() without toDo error = %v, want InvalidArgument", err)
+	}
+	if _, err := s.CreateToDo(ctx, &v2.CreateToDoRequest{ToDo: &v2.ToDo{Title: "title"}}); err != nil {
+		t.Fatalf("CreateToDo() error = %v", err)
+	}
+	_, err := s.UpdateToDo(ctx, &v2.UpdateToDoRequest{
+		ToDo:       &v2.ToDo{Name: "todos/1"},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"owner"}},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdateToDo() with unknown path error = %v, want InvalidArgument", err)
+	}
+
+	for _, token := range []string{"!", "YWJj", "MA"} {
+		if _, err := s.ListToDos(ctx, &v2.ListToDosRequest{PageToken: token}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ListToDos(pageToken=%q) error = %v, want InvalidArgument", token, err)
+		}
+	}
+	if _, err := s.ListToDos(ctx, &v2.ListToDosRequest{PageSize: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListToDos(pageSize=-1) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestListToDos(t *testing.T) {
+	ctx := context.Background()
+	v1API := newMemoryServer()
+	s := NewToDoServiceServer(v1API)
+	for i := 0; i < 5; i++ {
+		if _, err := s.CreateToDo(ctx, &v2.CreateToDoRequest{ToDo: &v2.ToDo{Title: "title"}}); err != nil {
+			t.Fatalf("CreateToDo() error = %v", err)
+		}
+	}
+	if _, err := s.DeleteToDo(ctx, &v2.DeleteToDoRequest{Name: "todos/3"}); err != nil {
+		t.Fatalf("DeleteToDo() error = %v", err)
+	}
+
+	var names []string
+	req := &v2.ListToDosRequest{PageSize: 2}
+	pages := 0
+	for ; ; pages++ {
+		if pages > 5 {
+			t.Fatal("ListToDos() does not stop paging")
+		}
+		resp, err := s.ListToDos(ctx, req)
+		if err != nil {
+			t.Fatalf("ListToDos() error = %v", err)
+		}
+		for _, todo := range resp.ToDos {
+			names = append(names, todo.Name)
+		}
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+	if got, want := names, []string{"todos/1", "todos/2", "todos/4", "todos/5"}; !equal(got, want) {
+		t.Errorf("ListToDos() = %v, want %v", got, want)
+	}
+	// 最后一页正好是 pageSize 个 ToDo 时不返回 nextPageToken
+	if pages != 1 {
+		t.Errorf("ListToDos() returned %d pages, want 2", pages+1)
+	}
+
+	resp, err := s.ListToDos(ctx, &v2.ListToDosRequest{ShowDeleted: true})
+	if err != nil || len(resp.ToDos) != 5 || len(resp.NextPageToken) > 0 {
+		t.Errorf("ListToDos(showDeleted) = %v, %v, want 5 ToDos on one page", resp, err)
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,11 @@
+-- Create 请求的幂等键，重放时返回第一次创建的 ID
+CREATE TABLE IF NOT EXISTS `Idempotency` (
+  `Key` varchar(128) NOT NULL,
+  `RequestHash` char(64) NOT NULL,
+  `ToDoID` bigint(20) NOT NULL,
+  `CreateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`Key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- 后台按 CreateTime 删除过期的幂等键
+ALTER TABLE `Idempotency` ADD KEY `CreateTime` (`CreateTime`);
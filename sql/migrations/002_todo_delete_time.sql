@@ -0,0 +1,4 @@
+-- 回收站: DeleteTime 不为 NULL 的 ToDo 已被删除，后台按 DeleteTime 永久删除过期的 ToDo
+ALTER TABLE `ToDo`
+  ADD COLUMN `DeleteTime` timestamp NULL DEFAULT NULL AFTER `Reminder`,
+  ADD KEY `DeleteTime` (`DeleteTime`);
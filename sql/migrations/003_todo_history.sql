@@ -0,0 +1,13 @@
+-- ToDo 的变更历史，OldValue 和 NewValue 是 ToDo 的 JSON，NULL 表示变更前或变更后不存在
+-- 已有的 ToDo 没有历史，第一次修改时记录修改前的内容
+CREATE TABLE IF NOT EXISTS `ToDoHistory` (
+  `ID` bigint(20) NOT NULL AUTO_INCREMENT,
+  `ToDoID` bigint(20) NOT NULL,
+  `Action` varchar(16) NOT NULL,
+  `OldValue` text NULL,
+  `NewValue` text NULL,
+  `Principal` varchar(128) NOT NULL,
+  `ChangeTime` timestamp(6) NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `ToDoID` (`ToDoID`, `ChangeTime`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
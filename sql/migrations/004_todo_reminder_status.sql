@@ -0,0 +1,8 @@
+-- 提醒的发送状态: 后台按 ReminderStatus 和 Reminder 查找到期的提醒
+ALTER TABLE `ToDo`
+  ADD COLUMN `ReminderStatus` varchar(16) NOT NULL DEFAULT 'PENDING' AFTER `DeleteTime`,
+  ADD COLUMN `ReminderDeliveryTime` timestamp NULL DEFAULT NULL AFTER `ReminderStatus`,
+  ADD KEY `DueReminder` (`ReminderStatus`, `Reminder`);
+
+-- 升级前已经过去的提醒不再发送，否则升级后会一次发送所有旧提醒
+UPDATE `ToDo` SET `ReminderStatus`='DELIVERED' WHERE `Reminder` < NOW();
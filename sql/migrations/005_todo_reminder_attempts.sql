@@ -0,0 +1,4 @@
+-- 提醒的重试: ReminderAttempts 是已经尝试发送的次数，ReminderNextAttemptTime 是认领租约的到期时间或下一次重试的时间
+ALTER TABLE `ToDo`
+  ADD COLUMN `ReminderAttempts` int(11) NOT NULL DEFAULT 0 AFTER `ReminderDeliveryTime`,
+  ADD COLUMN `ReminderNextAttemptTime` timestamp NULL DEFAULT NULL AFTER `ReminderAttempts`;
@@ -0,0 +1,27 @@
+-- webhook 订阅，Events 是逗号分隔的事件类型，空字符串表示订阅所有事件
+CREATE TABLE IF NOT EXISTS `Webhook` (
+  `ID` bigint(20) NOT NULL AUTO_INCREMENT,
+  `URL` varchar(2048) NOT NULL,
+  `Events` varchar(255) NOT NULL DEFAULT '',
+  `Secret` varchar(255) NOT NULL,
+  `CreateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`ID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- webhook 发送队列和发送记录，Status 为 DEAD 的记录是放弃重试的死信
+CREATE TABLE IF NOT EXISTS `WebhookDelivery` (
+  `ID` bigint(20) NOT NULL AUTO_INCREMENT,
+  `WebhookID` bigint(20) NOT NULL,
+  `Payload` text NOT NULL,
+  `Status` varchar(16) NOT NULL DEFAULT 'PENDING',
+  `Attempts` int(11) NOT NULL DEFAULT 0,
+  `ResponseCode` int(11) NOT NULL DEFAULT 0,
+  `Error` varchar(1024) NOT NULL DEFAULT '',
+  `CreateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `NextAttemptTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `DeliveryTime` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `Due` (`Status`, `NextAttemptTime`),
+  KEY `WebhookID` (`WebhookID`, `ID`),
+  CONSTRAINT `WebhookDelivery_Webhook` FOREIGN KEY (`WebhookID`) REFERENCES `Webhook` (`ID`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,3 @@
+-- 重复规则: Recurrence 是 RFC 5545 的 RRULE/EXDATE 文本，空字符串表示不重复
+ALTER TABLE `ToDo`
+  ADD COLUMN `Recurrence` varchar(1024) NOT NULL DEFAULT '' AFTER `ReminderNextAttemptTime`;
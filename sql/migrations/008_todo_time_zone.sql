@@ -0,0 +1,3 @@
+-- 重复的时区: TimeZone 是 IANA 时区名，重复按这个时区的墙上时间展开，空字符串表示 UTC
+ALTER TABLE `ToDo`
+  ADD COLUMN `TimeZone` varchar(64) NOT NULL DEFAULT '' AFTER `Recurrence`;
@@ -0,0 +1,4 @@
+-- 导入的外部 ID: ExternalID 在导入时用于跳过已经存在的记录，NULL 表示不是导入的 ToDo
+ALTER TABLE `ToDo`
+  ADD COLUMN `ExternalID` varchar(128) NULL DEFAULT NULL AFTER `TimeZone`,
+  ADD UNIQUE KEY `ExternalID` (`ExternalID`);
@@ -0,0 +1,5 @@
+-- ToDo 的所有者: 创建 ToDo 的通过认证的用户，日历订阅只导出所有者的 ToDo
+-- 已有的 ToDo 没有所有者，不会出现在日历订阅中: 认证之前变更历史中的身份是客户端自己声明的，不能用来确定所有者
+ALTER TABLE `ToDo`
+  ADD COLUMN `Owner` varchar(128) NULL DEFAULT NULL AFTER `ExternalID`,
+  ADD KEY `Owner` (`Owner`);
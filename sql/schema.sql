@@ -0,0 +1,71 @@
+CREATE TABLE `ToDo` (
+  `ID` bigint(20) NOT NULL AUTO_INCREMENT,
+  `Title` varchar(200) DEFAULT NULL,
+  `Description` varchar(1024) DEFAULT NULL,
+  `Reminder` timestamp NULL DEFAULT NULL,
+  `DeleteTime` timestamp NULL DEFAULT NULL,
+  `ReminderStatus` varchar(16) NOT NULL DEFAULT 'PENDING',
+  `ReminderDeliveryTime` timestamp NULL DEFAULT NULL,
+  `ReminderAttempts` int(11) NOT NULL DEFAULT 0,
+  `ReminderNextAttemptTime` timestamp NULL DEFAULT NULL,
+  `Recurrence` varchar(1024) NOT NULL DEFAULT '',
+  `TimeZone` varchar(64) NOT NULL DEFAULT '',
+  `ExternalID` varchar(128) NULL DEFAULT NULL,
+  `Owner` varchar(128) NULL DEFAULT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `Owner` (`Owner`),
+  KEY `DeleteTime` (`DeleteTime`),
+  KEY `DueReminder` (`ReminderStatus`, `Reminder`),
+  UNIQUE KEY `ExternalID` (`ExternalID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Create 请求的幂等键，重放时返回第一次创建的 ID
+CREATE TABLE `Idempotency` (
+  `Key` varchar(128) NOT NULL,
+  `RequestHash` char(64) NOT NULL,
+  `ToDoID` bigint(20) NOT NULL,
+  `CreateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`Key`),
+  KEY `CreateTime` (`CreateTime`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- ToDo 的变更历史，OldValue 和 NewValue 是 ToDo 的 JSON，NULL 表示变更前或变更后不存在
+CREATE TABLE `ToDoHistory` (
+  `ID` bigint(20) NOT NULL AUTO_INCREMENT,
+  `ToDoID` bigint(20) NOT NULL,
+  `Action` varchar(16) NOT NULL,
+  `OldValue` text NULL,
+  `NewValue` text NULL,
+  `Principal` varchar(128) NOT NULL,
+  `ChangeTime` timestamp(6) NOT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `ToDoID` (`ToDoID`, `ChangeTime`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- webhook 订阅，Events 是逗号分隔的事件类型，空字符串表示订阅所有事件
+CREATE TABLE `Webhook` (
+  `ID` bigint(20) NOT NULL AUTO_INCREMENT,
+  `URL` varchar(2048) NOT NULL,
+  `Events` varchar(255) NOT NULL DEFAULT '',
+  `Secret` varchar(255) NOT NULL,
+  `CreateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`ID`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- webhook 发送队列和发送记录，Status 为 DEAD 的记录是放弃重试的死信
+CREATE TABLE `WebhookDelivery` (
+  `ID` bigint(20) NOT NULL AUTO_INCREMENT,
+  `WebhookID` bigint(20) NOT NULL,
+  `Payload` text NOT NULL,
+  `Status` varchar(16) NOT NULL DEFAULT 'PENDING',
+  `Attempts` int(11) NOT NULL DEFAULT 0,
+  `ResponseCode` int(11) NOT NULL DEFAULT 0,
+  `Error` varchar(1024) NOT NULL DEFAULT '',
+  `CreateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `NextAttemptTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `DeliveryTime` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`ID`),
+  KEY `Due` (`Status`, `NextAttemptTime`),
+  KEY `WebhookID` (`WebhookID`, `ID`),
+  CONSTRAINT `WebhookDelivery_Webhook` FOREIGN KEY (`WebhookID`) REFERENCES `Webhook` (`ID`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
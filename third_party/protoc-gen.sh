@@ -7,4 +7,14 @@ protoc --proto_path=api/proto/v1 --proto_path=third_party --go_out=plugins=grpc:
 protoc --proto_path=api/proto/v1 --proto_path=third_party --grpc-gateway_out=logtostderr=true:pkg/api/v1 todo_service.proto
 
 # gen swagger file
-protoc --proto_path=api/proto/v1 --proto_path=third_party --swagger_out=logtostderr=true:api/swagger/v1 todo_service.proto
\ No newline at end of file
+protoc --proto_path=api/proto/v1 --proto_path=third_party --swagger_out=logtostderr=true:api/swagger/v1 todo_service.proto
+
+# gen v2 files, the proto file name keeps its v2/ directory so it doesn't clash with v1
+protoc --proto_path=api/proto --proto_path=third_party --go_out=plugins=grpc:pkg/api v2/todo_service.proto
+
+protoc --proto_path=api/proto --proto_path=third_party --grpc-gateway_out=logtostderr=true:pkg/api v2/todo_service.proto
+
+protoc --proto_path=api/proto --proto_path=third_party --swagger_out=logtostderr=true:api/swagger v2/todo_service.proto
+
+# embed the swagger files and swagger-ui into the REST gateway
+go generate ./pkg/protocol/rest